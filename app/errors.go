@@ -0,0 +1,162 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+)
+
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindBadRequest
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindMethodNotAllowed
+	KindConflict
+	KindTimeout
+	KindTooManyRequests
+)
+
+// Status returns the HTTP status code used to render errors of this kind.
+func (k ErrorKind) Status() int {
+	switch k {
+	case KindBadRequest, KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindMethodNotAllowed:
+		return http.StatusMethodNotAllowed
+	case KindConflict:
+		return http.StatusConflict
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	case KindTooManyRequests:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error is an error that is safe to show to clients. Code is a stable machine
+// readable identifier, Message is the human readable text, Details carries
+// structured data such as field errors and Err keeps the underlying cause for
+// logging without exposing it in responses.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Details any
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an *Error with the same code, so a sentinel
+// still matches after Wrap or WithMessage returned a copy of it.
+func (e *Error) Is(target error) bool {
+	var appErr *Error
+	if !errors.As(target, &appErr) {
+		return false
+	}
+	return e.Code == appErr.Code
+}
+
+// Status returns the HTTP status code of the error's kind.
+func (e *Error) Status() int {
+	return e.Kind.Status()
+}
+
+// Wrap returns a copy of the error that records err as its cause.
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+// WithMessage returns a copy of the error with a different message and the
+// same code.
+func (e *Error) WithMessage(message string) *Error {
+	wrapped := *e
+	wrapped.Message = message
+	return &wrapped
+}
+
+// WithDetails returns a copy of the error that carries details in the
+// response.
+func (e *Error) WithDetails(details any) *Error {
+	wrapped := *e
+	wrapped.Details = details
+	return &wrapped
+}
+
+func NewBadRequestError(code string, message string) *Error {
+	return &Error{Kind: KindBadRequest, Code: code, Message: message}
+}
+
+func NewValidationError(code string, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+func NewUnauthorizedError(code string, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+func NewForbiddenError(code string, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+func NewNotFoundError(code string, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func NewConflictError(code string, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func NewTimeoutError(code string, message string) *Error {
+	return &Error{Kind: KindTimeout, Code: code, Message: message}
+}
+
+func NewTooManyRequestsError(code string, message string) *Error {
+	return &Error{Kind: KindTooManyRequests, Code: code, Message: message}
+}
+
+var (
+	ErrInternal = &Error{Kind: KindInternal, Code: "internal_error", Message: "internal server error"}
+	ErrNotFound = NewNotFoundError("not_found", "resource not found")
+	ErrTimeout  = NewTimeoutError("request_timeout", "request timed out")
+)
+
+// AsError returns the *Error in err's chain. Errors that were not classified
+// by the application are reported as ErrInternal, keeping err as the cause so
+// driver messages never reach the client.
+func AsError(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrNotFound.Wrap(err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrTimeout.Wrap(err)
+	default:
+		return ErrInternal.Wrap(err)
+	}
+}
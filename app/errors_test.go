@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsError(t *testing.T) {
+	errUserNotFound := NewNotFoundError("user_not_found", "user not found")
+
+	testCaseList := []struct {
+		name            string
+		err             error
+		expectedCode    string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "application error",
+			err:             errUserNotFound,
+			expectedCode:    "user_not_found",
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "user not found",
+		},
+		{
+			name:            "wrapped application error",
+			err:             fmt.Errorf("get user: %w", errUserNotFound),
+			expectedCode:    "user_not_found",
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "user not found",
+		},
+		{
+			name:            "no rows",
+			err:             sql.ErrNoRows,
+			expectedCode:    "not_found",
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "resource not found",
+		},
+		{
+			name:            "deadline exceeded",
+			err:             context.DeadlineExceeded,
+			expectedCode:    "request_timeout",
+			expectedStatus:  http.StatusGatewayTimeout,
+			expectedMessage: "request timed out",
+		},
+		{
+			name:            "too many requests",
+			err:             NewTooManyRequestsError("rate_limited", "too many requests"),
+			expectedCode:    "rate_limited",
+			expectedStatus:  http.StatusTooManyRequests,
+			expectedMessage: "too many requests",
+		},
+		{
+			name:            "unknown error",
+			err:             errors.New("database is locked"),
+			expectedCode:    "internal_error",
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "internal server error",
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			appErr := AsError(testCase.err)
+
+			assert.Equal(t, testCase.expectedCode, appErr.Code)
+			assert.Equal(t, testCase.expectedStatus, appErr.Status())
+			assert.Equal(t, testCase.expectedMessage, appErr.Message)
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	errConflict := NewConflictError("duplicate", "resource already exists")
+	cause := errors.New("UNIQUE constraint failed")
+
+	wrapped := errConflict.Wrap(cause)
+
+	assert.ErrorIs(t, wrapped, errConflict)
+	assert.ErrorIs(t, wrapped, cause)
+	assert.ErrorIs(t, errConflict.WithMessage("user already exists"), errConflict)
+	assert.NotErrorIs(t, wrapped, NewConflictError("other", "resource already exists"))
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/openapi"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuthHandler interface {
+	Login(c *fiber.Ctx) error
+	Refresh(c *fiber.Ctx) error
+	Logout(c *fiber.Ctx) error
+}
+
+type authHandler struct {
+	authService services.AuthService
+}
+
+func NewAuthHandler(authService services.AuthService) AuthHandler {
+	return &authHandler{authService: authService}
+}
+
+func RegisterAuthRoutes(route fiber.Router, handler AuthHandler) {
+	route.Post("/login", handler.Login).Name("login")
+	route.Post("/refresh", handler.Refresh).Name("refreshToken")
+	route.Post("/logout", handler.Logout).Name("logout")
+}
+
+var authOperations = map[string]openapi.Operation{
+	"login":        {Summary: "Exchange username and password for a token pair or a two-factor challenge", Tags: []string{"auth"}, Request: models.UserLogin{}, Response: models.LoginResponse{}},
+	"refreshToken": {Summary: "Rotate a refresh token", Tags: []string{"auth"}, Request: models.RefreshTokenRequest{}, Response: models.TokenPair{}},
+	"logout":       {Summary: "Revoke the session of a refresh token", Tags: []string{"auth"}, Request: models.RefreshTokenRequest{}},
+}
+
+func (h *authHandler) Login(c *fiber.Ctx) error {
+	var login models.UserLogin
+	if err := c.BodyParser(&login); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &login); err != nil {
+		return err
+	}
+
+	response, err := h.authService.Login(c.UserContext(), &login, c.IP())
+	if err != nil {
+		setLockoutRetryAfter(c, err)
+		return err
+	}
+
+	if response.MFARequired {
+		return c.JSON(app.NewResponse("Two-factor authentication required", response))
+	}
+	return c.JSON(app.NewResponse("User logged in successfully", response))
+}
+
+func (h *authHandler) Refresh(c *fiber.Ctx) error {
+	var request models.RefreshTokenRequest
+	if err := c.BodyParser(&request); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &request); err != nil {
+		return err
+	}
+
+	tokenPair, err := h.authService.Refresh(c.UserContext(), request.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Token refreshed successfully", tokenPair))
+}
+
+func (h *authHandler) Logout(c *fiber.Ctx) error {
+	var request models.RefreshTokenRequest
+	if err := c.BodyParser(&request); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &request); err != nil {
+		return err
+	}
+
+	if err := h.authService.Logout(c.UserContext(), request.RefreshToken); err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("User logged out successfully", nil))
+}
+
+// setLockoutRetryAfter tells clients locked out by too many failed logins
+// when to try again.
+func setLockoutRetryAfter(c *fiber.Ctx, err error) {
+	var appErr *app.Error
+	if errors.As(err, &appErr) {
+		if lockout, ok := appErr.Details.(models.LoginLockout); ok {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(lockout.RetryAfter))
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/middleware"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthHandler(t *testing.T) {
+	tokenPair := &models.TokenPair{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", ExpiresIn: 900}
+	loginResponse := &models.LoginResponse{TokenPair: *tokenPair}
+
+	testCaseList := []struct {
+		name               string
+		url                string
+		method             string
+		jsonBody           string
+		expectedStatusCode int
+		expectedRetryAfter string
+		mockFunc           func(authServiceMock *services.AuthServiceMock)
+	}{
+		{
+			name:               "Login Success",
+			url:                "/login",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"username": "test", "password": "test"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.AuthServiceMock) {
+				serviceMock.On("Login", mock.Anything, mock.Anything, "0.0.0.0").Return(loginResponse, nil).Once()
+			},
+		},
+		{
+			name:               "Login MFA Required",
+			url:                "/login",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"username": "test", "password": "test"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.AuthServiceMock) {
+				challenge := &models.LoginResponse{MFARequired: true, ChallengeToken: "challenge", ChallengeExpiresIn: 300}
+				serviceMock.On("Login", mock.Anything, mock.Anything, "0.0.0.0").Return(challenge, nil).Once()
+			},
+		},
+		{
+			name:               "Login Invalid Credentials",
+			url:                "/login",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"username": "test", "password": "wrong"}`,
+			expectedStatusCode: 401,
+			mockFunc: func(serviceMock *services.AuthServiceMock) {
+				serviceMock.On("Login", mock.Anything, mock.Anything, "0.0.0.0").Return(nil, services.ErrInvalidCredentials).Once()
+			},
+		},
+		{
+			name:               "Login Locked",
+			url:                "/login",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"username": "test", "password": "test"}`,
+			expectedStatusCode: 429,
+			expectedRetryAfter: "60",
+			mockFunc: func(serviceMock *services.AuthServiceMock) {
+				lockedError := services.ErrLoginLocked.WithDetails(models.LoginLockout{RetryAfter: 60})
+				serviceMock.On("Login", mock.Anything, mock.Anything, "0.0.0.0").Return(nil, lockedError).Once()
+			},
+		},
+		{
+			name:               "Login Validation Error",
+			url:                "/login",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"username": "test", "password": ""}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.AuthServiceMock) {},
+		},
+		{
+			name:               "Login Body Empty",
+			url:                "/login",
+			method:             fiber.MethodPost,
+			jsonBody:           "",
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.AuthServiceMock) {},
+		},
+		{
+			name:               "Login Service Error",
+			url:                "/login",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"username": "test", "password": "test"}`,
+			expectedStatusCode: 500,
+			mockFunc: func(serviceMock *services.AuthServiceMock) {
+				serviceMock.On("Login", mock.Anything, mock.Anything, "0.0.0.0").Return(nil, errors.New("error")).Once()
+			},
+		},
+		{
+			name:               "Refresh Success",
+			url:                "/refresh",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"refreshToken": "refresh"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.AuthServiceMock) {
+				serviceMock.On("Refresh", mock.Anything, "refresh").Return(tokenPair, nil).Once()
+			},
+		},
+		{
+			name:               "Refresh Token Reused",
+			url:                "/refresh",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"refreshToken": "refresh"}`,
+			expectedStatusCode: 401,
+			mockFunc: func(serviceMock *services.AuthServiceMock) {
+				serviceMock.On("Refresh", mock.Anything, "refresh").Return(nil, services.ErrRefreshTokenReused).Once()
+			},
+		},
+		{
+			name:               "Refresh Validation Error",
+			url:                "/refresh",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"refreshToken": ""}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.AuthServiceMock) {},
+		},
+		{
+			name:               "Logout Success",
+			url:                "/logout",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"refreshToken": "refresh"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.AuthServiceMock) {
+				serviceMock.On("Logout", mock.Anything, "refresh").Return(nil).Once()
+			},
+		},
+		{
+			name:               "Logout Invalid Token",
+			url:                "/logout",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"refreshToken": "refresh"}`,
+			expectedStatusCode: 401,
+			mockFunc: func(serviceMock *services.AuthServiceMock) {
+				serviceMock.On("Logout", mock.Anything, "refresh").Return(services.ErrInvalidRefreshToken).Once()
+			},
+		},
+		{
+			name:               "Logout Body Empty",
+			url:                "/logout",
+			method:             fiber.MethodPost,
+			jsonBody:           "",
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.AuthServiceMock) {},
+		},
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	authServiceMock := services.NewAuthServiceMock()
+	handler := NewAuthHandler(authServiceMock)
+	group := "/api/v1/auth"
+	RegisterAuthRoutes(app.Group(group), handler)
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockFunc(authServiceMock)
+			req, _ := http.NewRequest(testCase.method, group+testCase.url, bytes.NewBufferString(testCase.jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			res, _ := app.Test(req, -1)
+			assert.Equal(t, testCase.expectedStatusCode, res.StatusCode)
+			assert.Equal(t, testCase.expectedRetryAfter, res.Header.Get(fiber.HeaderRetryAfter))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/logger"
+	"golang-template/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type EmailVerificationHandler interface {
+	VerifyEmail(c *fiber.Ctx) error
+	ResendVerification(c *fiber.Ctx) error
+}
+
+type emailVerificationHandler struct {
+	emailVerificationService services.EmailVerificationService
+}
+
+func NewEmailVerificationHandler(emailVerificationService services.EmailVerificationService) EmailVerificationHandler {
+	return &emailVerificationHandler{emailVerificationService: emailVerificationService}
+}
+
+func RegisterEmailVerificationRoutes(route fiber.Router, handler EmailVerificationHandler) {
+	route.Post("/verify-email", handler.VerifyEmail).Name("verifyEmail")
+	route.Post("/resend-verification", handler.ResendVerification).Name("resendVerification")
+}
+
+var emailVerificationOperations = map[string]openapi.Operation{
+	"verifyEmail":        {Summary: "Verify an email address with a mailed token", Tags: []string{"auth"}, Request: models.VerifyEmailRequest{}},
+	"resendVerification": {Summary: "Mail a new verification token to an unverified address", Tags: []string{"auth"}, Request: models.ResendVerificationRequest{}, Status: fiber.StatusAccepted},
+}
+
+func (h *emailVerificationHandler) VerifyEmail(c *fiber.Ctx) error {
+	var request models.VerifyEmailRequest
+	if err := c.BodyParser(&request); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &request); err != nil {
+		return err
+	}
+
+	if err := h.emailVerificationService.Verify(c.UserContext(), request.Token); err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Email verified successfully", nil))
+}
+
+// ResendVerification answers the same way whether or not the address is
+// registered. Failures are only logged, as they can only happen for
+// registered addresses and an error response would give them away.
+func (h *emailVerificationHandler) ResendVerification(c *fiber.Ctx) error {
+	var request models.ResendVerificationRequest
+	if err := c.BodyParser(&request); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &request); err != nil {
+		return err
+	}
+
+	if err := h.emailVerificationService.Resend(c.UserContext(), request.Email); err != nil {
+		logger.FromContext(c.UserContext()).Error("Verification email resend failed: ", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(app.NewResponse("Verification email sent if the address is unverified", nil))
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"golang-template/app/services"
+	"golang-template/middleware"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEmailVerificationHandler(t *testing.T) {
+	testCaseList := []struct {
+		name               string
+		url                string
+		jsonBody           string
+		expectedStatusCode int
+		mockFunc           func(serviceMock *services.EmailVerificationServiceMock)
+	}{
+		{
+			name:               "Verify Email Success",
+			url:                "/verify-email",
+			jsonBody:           `{"token": "token"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.EmailVerificationServiceMock) {
+				serviceMock.On("Verify", mock.Anything, "token").Return(nil).Once()
+			},
+		},
+		{
+			name:               "Verify Email Invalid Token",
+			url:                "/verify-email",
+			jsonBody:           `{"token": "token"}`,
+			expectedStatusCode: 400,
+			mockFunc: func(serviceMock *services.EmailVerificationServiceMock) {
+				serviceMock.On("Verify", mock.Anything, "token").Return(services.ErrInvalidVerificationToken).Once()
+			},
+		},
+		{
+			name:               "Verify Email Validation Error",
+			url:                "/verify-email",
+			jsonBody:           `{}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.EmailVerificationServiceMock) {},
+		},
+		{
+			name:               "Resend Verification Accepted",
+			url:                "/resend-verification",
+			jsonBody:           `{"email": "test@example.com"}`,
+			expectedStatusCode: 202,
+			mockFunc: func(serviceMock *services.EmailVerificationServiceMock) {
+				serviceMock.On("Resend", mock.Anything, "test@example.com").Return(nil).Once()
+			},
+		},
+		{
+			name:               "Resend Verification Invalid Email",
+			url:                "/resend-verification",
+			jsonBody:           `{"email": "test"}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.EmailVerificationServiceMock) {},
+		},
+		{
+			name:               "Resend Verification Service Error",
+			url:                "/resend-verification",
+			jsonBody:           `{"email": "test@example.com"}`,
+			expectedStatusCode: 202,
+			mockFunc: func(serviceMock *services.EmailVerificationServiceMock) {
+				serviceMock.On("Resend", mock.Anything, "test@example.com").Return(errors.New("error")).Once()
+			},
+		},
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	serviceMock := services.NewEmailVerificationServiceMock()
+	handler := NewEmailVerificationHandler(serviceMock)
+	group := "/api/v1/auth"
+	RegisterEmailVerificationRoutes(app.Group(group), handler)
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockFunc(serviceMock)
+			req, _ := http.NewRequest(fiber.MethodPost, group+testCase.url, bytes.NewBufferString(testCase.jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			res, _ := app.Test(req, -1)
+			assert.Equal(t, testCase.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"errors"
+	"golang-template/app"
+	"golang-template/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	errInvalidID    = app.NewBadRequestError("invalid_id", "invalid id")
+	errInvalidBody  = app.NewBadRequestError("invalid_body", "request body is invalid")
+	errInvalidQuery = app.NewBadRequestError("invalid_query", "query parameters are invalid")
+	errValidation   = app.NewValidationError("validation_failed", "validation failed")
+)
+
+// validate checks s with messages in the language the client asked for and
+// returns every failing field in the error details.
+func validate(c *fiber.Ctx, s any) error {
+	err := validator.ValidateStructLocale(s, c.Get(fiber.HeaderAcceptLanguage))
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if errors.As(err, &fieldErrors) {
+		return errValidation.WithDetails(fieldErrors)
+	}
+	return errValidation.Wrap(err)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/logger"
+	"golang-template/middleware"
+	"golang-template/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LogHandler interface {
+	GetLevel(c *fiber.Ctx) error
+	SetLevel(c *fiber.Ctx) error
+}
+
+type logHandler struct {
+	logger logger.Logger
+}
+
+func NewLogHandler(logger logger.Logger) LogHandler {
+	return &logHandler{logger: logger}
+}
+
+func RegisterLogRoutes(route fiber.Router, handler LogHandler, authMiddleware fiber.Handler) {
+	route.Use(authMiddleware)
+	route.Get("/level", middleware.RequirePermission("log:read"), handler.GetLevel).Name("getLogLevel")
+	route.Put("/level", middleware.RequirePermission("log:write"), handler.SetLevel).Name("setLogLevel")
+}
+
+var logOperations = map[string]openapi.Operation{
+	"getLogLevel": {Summary: "Get the log level", Tags: []string{"admin"}, Permission: "log:read", Response: models.LogLevel{}},
+	"setLogLevel": {Summary: "Change the log level at runtime", Tags: []string{"admin"}, Permission: "log:write", Request: models.LogLevel{}, Response: models.LogLevel{}},
+}
+
+func (h *logHandler) GetLevel(c *fiber.Ctx) error {
+	return c.JSON(app.NewResponse("Log level retrieved successfully", models.LogLevel{Level: h.logger.Level()}))
+}
+
+func (h *logHandler) SetLevel(c *fiber.Ctx) error {
+	var logLevel models.LogLevel
+	if err := c.BodyParser(&logLevel); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &logLevel); err != nil {
+		return err
+	}
+
+	previous := h.logger.Level()
+	if err := h.logger.SetLevel(logLevel.Level); err != nil {
+		return err
+	}
+
+	// Logged at warn so the change is recorded at any level.
+	h.logger.Warn(fmt.Sprintf("Log level changed from %s to %s by %s", previous, logLevel.Level, middleware.CurrentUser(c).Username))
+
+	return c.JSON(app.NewResponse("Log level updated successfully", logLevel))
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"bytes"
+	"golang-template/logger"
+	"golang-template/middleware"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogHandler(t *testing.T) {
+	testCaseList := []struct {
+		name               string
+		method             string
+		jsonBody           string
+		permissions        []string
+		expectedStatusCode int
+		expectedLevel      string
+	}{
+		{
+			name:               "Get Level",
+			method:             fiber.MethodGet,
+			permissions:        []string{"log:read"},
+			expectedStatusCode: 200,
+			expectedLevel:      "info",
+		},
+		{
+			name:               "Set Level",
+			method:             fiber.MethodPut,
+			jsonBody:           `{"level": "debug"}`,
+			permissions:        []string{"log:write"},
+			expectedStatusCode: 200,
+			expectedLevel:      "debug",
+		},
+		{
+			name:               "Set Unknown Level",
+			method:             fiber.MethodPut,
+			jsonBody:           `{"level": "verbose"}`,
+			permissions:        []string{"log:write"},
+			expectedStatusCode: 400,
+			expectedLevel:      "info",
+		},
+		{
+			name:               "Set Level Forbidden",
+			method:             fiber.MethodPut,
+			jsonBody:           `{"level": "debug"}`,
+			permissions:        []string{"log:read"},
+			expectedStatusCode: 403,
+			expectedLevel:      "info",
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			log := logger.NewLogger("info")
+			app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+			group := "/api/v1/admin/log"
+			RegisterLogRoutes(app.Group(group), NewLogHandler(log), newAuthMiddlewareStub(testCase.permissions...))
+
+			req, _ := http.NewRequest(testCase.method, group+"/level", bytes.NewBufferString(testCase.jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			res, _ := app.Test(req, -1)
+
+			assert.Equal(t, testCase.expectedStatusCode, res.StatusCode)
+			assert.Equal(t, testCase.expectedLevel, log.Level())
+		})
+	}
+}
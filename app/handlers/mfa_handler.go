@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/middleware"
+	"golang-template/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MFAHandler interface {
+	Enroll(c *fiber.Ctx) error
+	Confirm(c *fiber.Ctx) error
+	Disable(c *fiber.Ctx) error
+	Verify(c *fiber.Ctx) error
+}
+
+type mfaHandler struct {
+	mfaService  services.MFAService
+	authService services.AuthService
+}
+
+func NewMFAHandler(mfaService services.MFAService, authService services.AuthService) MFAHandler {
+	return &mfaHandler{mfaService: mfaService, authService: authService}
+}
+
+func RegisterMFARoutes(route fiber.Router, handler MFAHandler, authMiddleware fiber.Handler) {
+	route.Post("/enroll", authMiddleware, handler.Enroll).Name("enrollMFA")
+	route.Post("/confirm", authMiddleware, handler.Confirm).Name("confirmMFA")
+	route.Post("/disable", authMiddleware, handler.Disable).Name("disableMFA")
+	route.Post("/verify", handler.Verify).Name("verifyMFA")
+}
+
+var mfaOperations = map[string]openapi.Operation{
+	"enrollMFA":  {Summary: "Start two-factor enrollment with a new TOTP secret", Tags: []string{"mfa"}, Auth: true, Response: models.MFAEnrollment{}},
+	"confirmMFA": {Summary: "Enable two-factor authentication with a code and get recovery codes", Tags: []string{"mfa"}, Auth: true, Request: models.MFACodeRequest{}, Response: models.MFARecoveryCodes{}},
+	"disableMFA": {Summary: "Disable two-factor authentication", Tags: []string{"mfa"}, Auth: true, Request: models.MFADisableRequest{}},
+	"verifyMFA":  {Summary: "Complete a login challenge with a TOTP or recovery code", Tags: []string{"mfa"}, Request: models.MFAVerifyRequest{}, Response: models.MFAVerifyResponse{}},
+}
+
+func (h *mfaHandler) Enroll(c *fiber.Ctx) error {
+	enrollment, err := h.mfaService.Enroll(c.UserContext(), middleware.CurrentUser(c).UserID())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Two-factor enrollment started", enrollment))
+}
+
+func (h *mfaHandler) Confirm(c *fiber.Ctx) error {
+	var request models.MFACodeRequest
+	if err := c.BodyParser(&request); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &request); err != nil {
+		return err
+	}
+
+	recoveryCodes, err := h.mfaService.Confirm(c.UserContext(), middleware.CurrentUser(c).UserID(), request.OTP, c.IP())
+	if err != nil {
+		setLockoutRetryAfter(c, err)
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Two-factor authentication enabled", recoveryCodes))
+}
+
+func (h *mfaHandler) Disable(c *fiber.Ctx) error {
+	var request models.MFADisableRequest
+	if err := c.BodyParser(&request); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &request); err != nil {
+		return err
+	}
+
+	if err := h.mfaService.Disable(c.UserContext(), middleware.CurrentUser(c).UserID(), request.Password, request.OTP, c.IP()); err != nil {
+		setLockoutRetryAfter(c, err)
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Two-factor authentication disabled", nil))
+}
+
+func (h *mfaHandler) Verify(c *fiber.Ctx) error {
+	var request models.MFAVerifyRequest
+	if err := c.BodyParser(&request); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &request); err != nil {
+		return err
+	}
+
+	response, err := h.authService.VerifyMFA(c.UserContext(), &request, c.IP())
+	if err != nil {
+		setLockoutRetryAfter(c, err)
+		return err
+	}
+
+	return c.JSON(app.NewResponse("User logged in successfully", response))
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/middleware"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMFAHandler(t *testing.T) {
+	testCaseList := []struct {
+		name               string
+		url                string
+		jsonBody           string
+		expectedStatusCode int
+		expectedRetryAfter string
+		mockFunc           func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock)
+	}{
+		{
+			name:               "Enroll Success",
+			url:                "/enroll",
+			expectedStatusCode: 200,
+			mockFunc: func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock) {
+				mfaServiceMock.On("Enroll", mock.Anything, testUserID).Return(&models.MFAEnrollment{Secret: "SECRET"}, nil).Once()
+			},
+		},
+		{
+			name:               "Enroll Already Enabled",
+			url:                "/enroll",
+			expectedStatusCode: 409,
+			mockFunc: func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock) {
+				mfaServiceMock.On("Enroll", mock.Anything, testUserID).Return(nil, services.ErrMFAAlreadyEnabled).Once()
+			},
+		},
+		{
+			name:               "Confirm Success",
+			url:                "/confirm",
+			jsonBody:           `{"otp": "123456"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock) {
+				mfaServiceMock.On("Confirm", mock.Anything, testUserID, "123456", "0.0.0.0").Return(&models.MFARecoveryCodes{RecoveryCodes: []string{"abcd-efgh"}}, nil).Once()
+			},
+		},
+		{
+			name:               "Confirm Invalid Code",
+			url:                "/confirm",
+			jsonBody:           `{"otp": "000000"}`,
+			expectedStatusCode: 400,
+			mockFunc: func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock) {
+				mfaServiceMock.On("Confirm", mock.Anything, testUserID, "000000", "0.0.0.0").Return(nil, services.ErrInvalidMFACode).Once()
+			},
+		},
+		{
+			name:               "Confirm Missing Code",
+			url:                "/confirm",
+			jsonBody:           `{}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock) {},
+		},
+		{
+			name:               "Disable Success",
+			url:                "/disable",
+			jsonBody:           `{"password": "Passw0rd1", "otp": "123456"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock) {
+				mfaServiceMock.On("Disable", mock.Anything, testUserID, "Passw0rd1", "123456", "0.0.0.0").Return(nil).Once()
+			},
+		},
+		{
+			name:               "Disable Incorrect Password",
+			url:                "/disable",
+			jsonBody:           `{"password": "wrong", "otp": "123456"}`,
+			expectedStatusCode: 400,
+			mockFunc: func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock) {
+				mfaServiceMock.On("Disable", mock.Anything, testUserID, "wrong", "123456", "0.0.0.0").Return(services.ErrIncorrectPassword).Once()
+			},
+		},
+		{
+			name:               "Disable Locked",
+			url:                "/disable",
+			jsonBody:           `{"password": "Passw0rd1", "otp": "000000"}`,
+			expectedStatusCode: 429,
+			expectedRetryAfter: "60",
+			mockFunc: func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock) {
+				lockedError := services.ErrLoginLocked.WithDetails(models.LoginLockout{RetryAfter: 60})
+				mfaServiceMock.On("Disable", mock.Anything, testUserID, "Passw0rd1", "000000", "0.0.0.0").Return(lockedError).Once()
+			},
+		},
+		{
+			name:               "Verify Success",
+			url:                "/verify",
+			jsonBody:           `{"challengeToken": "challenge", "otp": "123456"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock) {
+				authServiceMock.On("VerifyMFA", mock.Anything, &models.MFAVerifyRequest{ChallengeToken: "challenge", OTP: "123456"}, "0.0.0.0").
+					Return(&models.MFAVerifyResponse{TokenPair: models.TokenPair{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", ExpiresIn: 900}}, nil).Once()
+			},
+		},
+		{
+			name:               "Verify Invalid Challenge",
+			url:                "/verify",
+			jsonBody:           `{"challengeToken": "expired", "otp": "123456"}`,
+			expectedStatusCode: 401,
+			mockFunc: func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock) {
+				authServiceMock.On("VerifyMFA", mock.Anything, mock.Anything, "0.0.0.0").Return(nil, services.ErrInvalidMFAChallenge).Once()
+			},
+		},
+		{
+			name:               "Verify Service Error",
+			url:                "/verify",
+			jsonBody:           `{"challengeToken": "challenge", "otp": "123456"}`,
+			expectedStatusCode: 500,
+			mockFunc: func(mfaServiceMock *services.MFAServiceMock, authServiceMock *services.AuthServiceMock) {
+				authServiceMock.On("VerifyMFA", mock.Anything, mock.Anything, "0.0.0.0").Return(nil, errors.New("error")).Once()
+			},
+		},
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	mfaServiceMock := services.NewMFAServiceMock()
+	authServiceMock := services.NewAuthServiceMock()
+	handler := NewMFAHandler(mfaServiceMock, authServiceMock)
+	group := "/api/v1/auth/mfa"
+	RegisterMFARoutes(app.Group(group), handler, newAuthMiddlewareStub())
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockFunc(mfaServiceMock, authServiceMock)
+			req, _ := http.NewRequest(fiber.MethodPost, group+testCase.url, bytes.NewBufferString(testCase.jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			res, _ := app.Test(req, -1)
+			assert.Equal(t, testCase.expectedStatusCode, res.StatusCode)
+			assert.Equal(t, testCase.expectedRetryAfter, res.Header.Get(fiber.HeaderRetryAfter))
+		})
+	}
+	mfaServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
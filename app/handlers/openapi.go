@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"golang-template/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handlers struct {
+	User              UserHandler
+	Role              RoleHandler
+	Permission        PermissionHandler
+	UserRole          UserRoleHandler
+	Auth              AuthHandler
+	EmailVerification EmailVerificationHandler
+	Password          PasswordHandler
+	MFA               MFAHandler
+	Log               LogHandler
+}
+
+// RegisterRoutes mounts every API route on the /api router. The OpenAPI
+// document is generated from the routes registered here.
+func RegisterRoutes(api fiber.Router, handlers Handlers, authMiddleware fiber.Handler) {
+	RegisterUserRoutes(api.Group("/v1"), handlers.User, authMiddleware)
+	RegisterRoleRoutes(api.Group("/v1/role"), handlers.Role, authMiddleware)
+	RegisterPermissionRoutes(api.Group("/v1/permission"), handlers.Permission, authMiddleware)
+	RegisterUserRoleRoutes(api.Group("/v1/user-role"), handlers.UserRole, authMiddleware)
+	RegisterAuthRoutes(api.Group("/v1/auth"), handlers.Auth)
+	RegisterEmailVerificationRoutes(api.Group("/v1/auth"), handlers.EmailVerification)
+	RegisterPasswordRoutes(api.Group("/v1/auth"), handlers.Password, authMiddleware)
+	RegisterMFARoutes(api.Group("/v1/auth/mfa"), handlers.MFA, authMiddleware)
+	RegisterLogRoutes(api.Group("/v1/admin/log"), handlers.Log, authMiddleware)
+}
+
+// OpenAPI describes the operations of every route RegisterRoutes mounts.
+func OpenAPI() openapi.Config {
+	operations := map[string]openapi.Operation{}
+	for _, group := range []map[string]openapi.Operation{
+		userOperations,
+		roleOperations,
+		permissionOperations,
+		userRoleOperations,
+		authOperations,
+		emailVerificationOperations,
+		passwordOperations,
+		mfaOperations,
+		logOperations,
+	} {
+		for name, operation := range group {
+			operations[name] = operation
+		}
+	}
+
+	return openapi.Config{
+		Info: openapi.Info{
+			Title:   "golang-template API",
+			Version: "1.0.0",
+		},
+		Operations: operations,
+		PathPrefix: "/api/",
+	}
+}
+
+type idParams struct {
+	ID int64 `params:"id" validate:"min=1"`
+}
+
+type uuidParams struct {
+	ID string `params:"id" validate:"uuid"`
+}
+
+type userRoleParams struct {
+	UserID       string `params:"userId" validate:"uuid"`
+	RoleID       int64  `params:"roleId" validate:"min=1"`
+	PermissionID int64  `params:"permissionId" validate:"min=1"`
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"golang-template/openapi"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const specPath = "../../docs/openapi.json"
+
+var updateSpec = flag.Bool("update", false, "rewrite "+specPath+" from the registered routes")
+
+// TestOpenAPISpec fails when docs/openapi.json no longer matches the routes.
+// Regenerate it with: go test ./app/handlers -run TestOpenAPISpec -update
+func TestOpenAPISpec(t *testing.T) {
+	app := fiber.New()
+	RegisterRoutes(app.Group("/api"), Handlers{
+		User:              NewUserHandler(nil),
+		Role:              NewRoleHandler(nil),
+		Permission:        NewPermissionHandler(nil),
+		UserRole:          NewUserRoleHandler(nil),
+		Auth:              NewAuthHandler(nil),
+		EmailVerification: NewEmailVerificationHandler(nil),
+		Password:          NewPasswordHandler(nil, nil),
+		MFA:               NewMFAHandler(nil, nil),
+		Log:               NewLogHandler(nil),
+	}, func(c *fiber.Ctx) error { return c.Next() })
+
+	spec, err := openapi.Generate(app.GetRoutes(true), OpenAPI())
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated = append(generated, '\n')
+
+	if *updateSpec {
+		if err := os.WriteFile(specPath, generated, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	committed, err := os.ReadFile(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(committed), string(generated), "%s is out of date, regenerate it with -update", specPath)
+}
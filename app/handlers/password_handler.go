@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/logger"
+	"golang-template/middleware"
+	"golang-template/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PasswordHandler interface {
+	ChangePassword(c *fiber.Ctx) error
+	ForgotPassword(c *fiber.Ctx) error
+	ResetPassword(c *fiber.Ctx) error
+}
+
+type passwordHandler struct {
+	userService          services.UserService
+	passwordResetService services.PasswordResetService
+}
+
+func NewPasswordHandler(userService services.UserService, passwordResetService services.PasswordResetService) PasswordHandler {
+	return &passwordHandler{userService: userService, passwordResetService: passwordResetService}
+}
+
+func RegisterPasswordRoutes(route fiber.Router, handler PasswordHandler, authMiddleware fiber.Handler) {
+	route.Post("/change-password", authMiddleware, handler.ChangePassword).Name("changePassword")
+	route.Post("/forgot-password", handler.ForgotPassword).Name("forgotPassword")
+	route.Post("/reset-password", handler.ResetPassword).Name("resetPassword")
+}
+
+var passwordOperations = map[string]openapi.Operation{
+	"changePassword": {Summary: "Change the password of the signed in user", Tags: []string{"auth"}, Auth: true, Request: models.ChangePasswordRequest{}},
+	"forgotPassword": {Summary: "Mail a password reset token", Tags: []string{"auth"}, Request: models.ForgotPasswordRequest{}, Status: fiber.StatusAccepted},
+	"resetPassword":  {Summary: "Set a new password with a mailed token and revoke every session", Tags: []string{"auth"}, Request: models.ResetPasswordRequest{}},
+}
+
+func (h *passwordHandler) ChangePassword(c *fiber.Ctx) error {
+	var request models.ChangePasswordRequest
+	if err := c.BodyParser(&request); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &request); err != nil {
+		return err
+	}
+
+	if err := h.userService.ChangePassword(c.UserContext(), middleware.CurrentUser(c).UserID(), &request, c.IP()); err != nil {
+		setLockoutRetryAfter(c, err)
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Password changed successfully", nil))
+}
+
+// ForgotPassword answers the same way whether or not the address is
+// registered. Failures are only logged, as they can only happen for
+// registered addresses and an error response would give them away.
+func (h *passwordHandler) ForgotPassword(c *fiber.Ctx) error {
+	var request models.ForgotPasswordRequest
+	if err := c.BodyParser(&request); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &request); err != nil {
+		return err
+	}
+
+	if err := h.passwordResetService.Forgot(c.UserContext(), request.Email); err != nil {
+		logger.FromContext(c.UserContext()).Error("Password reset request failed: ", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(app.NewResponse("Password reset email sent if the address is registered", nil))
+}
+
+func (h *passwordHandler) ResetPassword(c *fiber.Ctx) error {
+	var request models.ResetPasswordRequest
+	if err := c.BodyParser(&request); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &request); err != nil {
+		return err
+	}
+
+	if err := h.passwordResetService.Reset(c.UserContext(), request.Token, request.NewPassword); err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Password reset successfully", nil))
+}
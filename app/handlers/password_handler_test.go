@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/middleware"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPasswordHandler(t *testing.T) {
+	testCaseList := []struct {
+		name               string
+		url                string
+		jsonBody           string
+		expectedStatusCode int
+		mockFunc           func(userServiceMock *services.UserServiceMock, resetServiceMock *services.PasswordResetServiceMock)
+	}{
+		{
+			name:               "Change Password Success",
+			url:                "/change-password",
+			jsonBody:           `{"currentPassword": "old", "newPassword": "Passw0rd1"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(userServiceMock *services.UserServiceMock, resetServiceMock *services.PasswordResetServiceMock) {
+				userServiceMock.On("ChangePassword", mock.Anything, testUserID, &models.ChangePasswordRequest{CurrentPassword: "old", NewPassword: "Passw0rd1"}, "0.0.0.0").Return(nil).Once()
+			},
+		},
+		{
+			name:               "Change Password Incorrect",
+			url:                "/change-password",
+			jsonBody:           `{"currentPassword": "wrong", "newPassword": "Passw0rd1"}`,
+			expectedStatusCode: 400,
+			mockFunc: func(userServiceMock *services.UserServiceMock, resetServiceMock *services.PasswordResetServiceMock) {
+				userServiceMock.On("ChangePassword", mock.Anything, testUserID, mock.Anything, "0.0.0.0").Return(services.ErrIncorrectPassword).Once()
+			},
+		},
+		{
+			name:               "Change Password Locked",
+			url:                "/change-password",
+			jsonBody:           `{"currentPassword": "wrong", "newPassword": "Passw0rd1"}`,
+			expectedStatusCode: 429,
+			mockFunc: func(userServiceMock *services.UserServiceMock, resetServiceMock *services.PasswordResetServiceMock) {
+				userServiceMock.On("ChangePassword", mock.Anything, testUserID, mock.Anything, "0.0.0.0").Return(services.ErrLoginLocked).Once()
+			},
+		},
+		{
+			name:               "Change Password Weak",
+			url:                "/change-password",
+			jsonBody:           `{"currentPassword": "old", "newPassword": "weak"}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(userServiceMock *services.UserServiceMock, resetServiceMock *services.PasswordResetServiceMock) {},
+		},
+		{
+			name:               "Forgot Password Accepted",
+			url:                "/forgot-password",
+			jsonBody:           `{"email": "test@example.com"}`,
+			expectedStatusCode: 202,
+			mockFunc: func(userServiceMock *services.UserServiceMock, resetServiceMock *services.PasswordResetServiceMock) {
+				resetServiceMock.On("Forgot", mock.Anything, "test@example.com").Return(nil).Once()
+			},
+		},
+		{
+			name:               "Forgot Password Service Error",
+			url:                "/forgot-password",
+			jsonBody:           `{"email": "test@example.com"}`,
+			expectedStatusCode: 202,
+			mockFunc: func(userServiceMock *services.UserServiceMock, resetServiceMock *services.PasswordResetServiceMock) {
+				resetServiceMock.On("Forgot", mock.Anything, "test@example.com").Return(errors.New("error")).Once()
+			},
+		},
+		{
+			name:               "Reset Password Success",
+			url:                "/reset-password",
+			jsonBody:           `{"token": "token", "newPassword": "Passw0rd1"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(userServiceMock *services.UserServiceMock, resetServiceMock *services.PasswordResetServiceMock) {
+				resetServiceMock.On("Reset", mock.Anything, "token", "Passw0rd1").Return(nil).Once()
+			},
+		},
+		{
+			name:               "Reset Password Invalid Token",
+			url:                "/reset-password",
+			jsonBody:           `{"token": "token", "newPassword": "Passw0rd1"}`,
+			expectedStatusCode: 400,
+			mockFunc: func(userServiceMock *services.UserServiceMock, resetServiceMock *services.PasswordResetServiceMock) {
+				resetServiceMock.On("Reset", mock.Anything, "token", "Passw0rd1").Return(services.ErrInvalidResetToken).Once()
+			},
+		},
+		{
+			name:               "Reset Password Body Empty",
+			url:                "/reset-password",
+			jsonBody:           "",
+			expectedStatusCode: 400,
+			mockFunc:           func(userServiceMock *services.UserServiceMock, resetServiceMock *services.PasswordResetServiceMock) {},
+		},
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	userServiceMock := services.NewUserServiceMock()
+	resetServiceMock := services.NewPasswordResetServiceMock()
+	handler := NewPasswordHandler(userServiceMock, resetServiceMock)
+	group := "/api/v1/auth"
+	RegisterPasswordRoutes(app.Group(group), handler, newAuthMiddlewareStub())
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockFunc(userServiceMock, resetServiceMock)
+			req, _ := http.NewRequest(fiber.MethodPost, group+testCase.url, bytes.NewBufferString(testCase.jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			res, _ := app.Test(req, -1)
+			assert.Equal(t, testCase.expectedStatusCode, res.StatusCode)
+		})
+	}
+	userServiceMock.AssertExpectations(t)
+	resetServiceMock.AssertExpectations(t)
+}
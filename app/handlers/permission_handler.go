@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/middleware"
+	"golang-template/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PermissionHandler interface {
+	Create(c *fiber.Ctx) error
+	Get(c *fiber.Ctx) error
+	List(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+}
+
+type permissionHandler struct {
+	permissionService services.PermissionService
+}
+
+func NewPermissionHandler(permissionService services.PermissionService) PermissionHandler {
+	return &permissionHandler{permissionService: permissionService}
+}
+
+func RegisterPermissionRoutes(route fiber.Router, handler PermissionHandler, authMiddleware fiber.Handler) {
+	route.Use(authMiddleware)
+	route.Get("/", middleware.RequirePermission("permission:read"), handler.List).Name("listPermissions")
+	route.Get("/:id", middleware.RequirePermission("permission:read"), handler.Get).Name("getPermission")
+	route.Post("/", middleware.RequirePermission("permission:write"), handler.Create).Name("createPermission")
+	route.Put("/:id", middleware.RequirePermission("permission:write"), handler.Update).Name("updatePermission")
+	route.Delete("/:id", middleware.RequirePermission("permission:write"), handler.Delete).Name("deletePermission")
+}
+
+var permissionOperations = map[string]openapi.Operation{
+	"listPermissions":  {Summary: "List permissions", Tags: []string{"permission"}, Permission: "permission:read", Response: []models.Permission{}},
+	"getPermission":    {Summary: "Get a permission", Tags: []string{"permission"}, Permission: "permission:read", Params: idParams{}, Response: models.Permission{}},
+	"createPermission": {Summary: "Create a permission", Tags: []string{"permission"}, Permission: "permission:write", Request: models.PermissionCreate{}, Response: models.Permission{}, Status: fiber.StatusCreated},
+	"updatePermission": {Summary: "Update a permission", Tags: []string{"permission"}, Permission: "permission:write", Params: idParams{}, Request: models.PermissionUpdate{}, Response: models.Permission{}},
+	"deletePermission": {Summary: "Delete a permission", Tags: []string{"permission"}, Permission: "permission:write", Params: idParams{}},
+}
+
+func (h *permissionHandler) Create(c *fiber.Ctx) error {
+	var newPermission models.PermissionCreate
+	if err := c.BodyParser(&newPermission); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &newPermission); err != nil {
+		return err
+	}
+
+	permission, err := h.permissionService.Create(c.UserContext(), &newPermission)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(app.NewResponse("Permission created successfully", permission))
+}
+
+func (h *permissionHandler) Get(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	permission, err := h.permissionService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Permission retrieved successfully", permission))
+}
+
+func (h *permissionHandler) List(c *fiber.Ctx) error {
+	permissions, err := h.permissionService.List(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Permissions listed successfully", permissions))
+}
+
+func (h *permissionHandler) Update(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var permissionUpdate models.PermissionUpdate
+	if err := c.BodyParser(&permissionUpdate); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &permissionUpdate); err != nil {
+		return err
+	}
+
+	permission, err := h.permissionService.Update(c.UserContext(), id, &permissionUpdate)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Permission updated successfully", permission))
+}
+
+func (h *permissionHandler) Delete(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.permissionService.Delete(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Permission deleted successfully", nil))
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/middleware"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPermissionHandler(t *testing.T) {
+	permission := &models.Permission{ID: 1, Name: "user:read", Resource: "user", Action: "read"}
+
+	testCaseList := []struct {
+		name               string
+		url                string
+		method             string
+		jsonBody           string
+		expectedStatusCode int
+		mockFunc           func(serviceMock *services.PermissionServiceMock)
+	}{
+		{
+			name:               "Create Success",
+			url:                "/",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"resource": "user", "action": "read"}`,
+			expectedStatusCode: 201,
+			mockFunc: func(serviceMock *services.PermissionServiceMock) {
+				serviceMock.On("Create", mock.Anything, mock.Anything).Return(permission, nil).Once()
+			},
+		},
+		{
+			name:               "Create Validation Error",
+			url:                "/",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"resource": "user", "action": ""}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.PermissionServiceMock) {},
+		},
+		{
+			name:               "Create Conflict",
+			url:                "/",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"resource": "user", "action": "read"}`,
+			expectedStatusCode: 409,
+			mockFunc: func(serviceMock *services.PermissionServiceMock) {
+				serviceMock.On("Create", mock.Anything, mock.Anything).Return(nil, services.ErrPermissionAlreadyExists).Once()
+			},
+		},
+		{
+			name:               "Get Success",
+			url:                "/1",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.PermissionServiceMock) {
+				serviceMock.On("GetByID", mock.Anything, int64(1)).Return(permission, nil).Once()
+			},
+		},
+		{
+			name:               "Get Not Found",
+			url:                "/1",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.PermissionServiceMock) {
+				serviceMock.On("GetByID", mock.Anything, int64(1)).Return(nil, services.ErrPermissionNotFound).Once()
+			},
+		},
+		{
+			name:               "Get Invalid ID",
+			url:                "/abc",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.PermissionServiceMock) {},
+		},
+		{
+			name:               "List Success",
+			url:                "/",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.PermissionServiceMock) {
+				serviceMock.On("List", mock.Anything).Return(&[]models.Permission{*permission}, nil).Once()
+			},
+		},
+		{
+			name:               "List Failed",
+			url:                "/",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 500,
+			mockFunc: func(serviceMock *services.PermissionServiceMock) {
+				serviceMock.On("List", mock.Anything).Return(nil, errors.New("error")).Once()
+			},
+		},
+		{
+			name:               "Update Success",
+			url:                "/1",
+			method:             fiber.MethodPut,
+			jsonBody:           `{"description": "List users"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.PermissionServiceMock) {
+				serviceMock.On("Update", mock.Anything, int64(1), mock.Anything).Return(permission, nil).Once()
+			},
+		},
+		{
+			name:               "Update Not Found",
+			url:                "/1",
+			method:             fiber.MethodPut,
+			jsonBody:           `{"description": "List users"}`,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.PermissionServiceMock) {
+				serviceMock.On("Update", mock.Anything, int64(1), mock.Anything).Return(nil, services.ErrPermissionNotFound).Once()
+			},
+		},
+		{
+			name:               "Delete Success",
+			url:                "/1",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.PermissionServiceMock) {
+				serviceMock.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+			},
+		},
+		{
+			name:               "Delete Not Found",
+			url:                "/1",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.PermissionServiceMock) {
+				serviceMock.On("Delete", mock.Anything, int64(1)).Return(services.ErrPermissionNotFound).Once()
+			},
+		},
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	permissionServiceMock := services.NewPermissionServiceMock()
+	handler := NewPermissionHandler(permissionServiceMock)
+	group := "/api/v1/permission"
+	RegisterPermissionRoutes(app.Group(group), handler, newAuthMiddlewareStub("permission:read", "permission:write"))
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockFunc(permissionServiceMock)
+			req, _ := http.NewRequest(testCase.method, group+testCase.url, bytes.NewBufferString(testCase.jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			res, _ := app.Test(req, -1)
+			assert.Equal(t, testCase.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestPermissionHandler_Forbidden(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	permissionServiceMock := services.NewPermissionServiceMock()
+	group := "/api/v1/permission"
+	RegisterPermissionRoutes(app.Group(group), NewPermissionHandler(permissionServiceMock), newAuthMiddlewareStub("permission:read"))
+
+	req, _ := http.NewRequest(fiber.MethodPost, group+"/", bytes.NewBufferString(`{"resource": "user", "action": "read"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, _ := app.Test(req, -1)
+
+	assert.Equal(t, fiber.StatusForbidden, res.StatusCode)
+	permissionServiceMock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
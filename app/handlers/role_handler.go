@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/middleware"
+	"golang-template/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RoleHandler interface {
+	Create(c *fiber.Ctx) error
+	Get(c *fiber.Ctx) error
+	List(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+}
+
+type roleHandler struct {
+	roleService services.RoleService
+}
+
+func NewRoleHandler(roleService services.RoleService) RoleHandler {
+	return &roleHandler{roleService: roleService}
+}
+
+func RegisterRoleRoutes(route fiber.Router, handler RoleHandler, authMiddleware fiber.Handler) {
+	route.Use(authMiddleware)
+	route.Get("/", middleware.RequirePermission("role:read"), handler.List).Name("listRoles")
+	route.Get("/:id", middleware.RequirePermission("role:read"), handler.Get).Name("getRole")
+	route.Post("/", middleware.RequirePermission("role:write"), handler.Create).Name("createRole")
+	route.Put("/:id", middleware.RequirePermission("role:write"), handler.Update).Name("updateRole")
+	route.Delete("/:id", middleware.RequirePermission("role:write"), handler.Delete).Name("deleteRole")
+}
+
+var roleOperations = map[string]openapi.Operation{
+	"listRoles":  {Summary: "List roles", Tags: []string{"role"}, Permission: "role:read", Response: []models.Role{}},
+	"getRole":    {Summary: "Get a role", Tags: []string{"role"}, Permission: "role:read", Params: idParams{}, Response: models.Role{}},
+	"createRole": {Summary: "Create a role", Tags: []string{"role"}, Permission: "role:write", Request: models.RoleCreate{}, Response: models.Role{}, Status: fiber.StatusCreated},
+	"updateRole": {Summary: "Update a role", Tags: []string{"role"}, Permission: "role:write", Params: idParams{}, Request: models.RoleUpdate{}, Response: models.Role{}},
+	"deleteRole": {Summary: "Delete a role", Tags: []string{"role"}, Permission: "role:write", Params: idParams{}},
+}
+
+func (h *roleHandler) Create(c *fiber.Ctx) error {
+	var newRole models.RoleCreate
+	if err := c.BodyParser(&newRole); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &newRole); err != nil {
+		return err
+	}
+
+	role, err := h.roleService.Create(c.UserContext(), &newRole)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(app.NewResponse("Role created successfully", role))
+}
+
+func (h *roleHandler) Get(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	role, err := h.roleService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Role retrieved successfully", role))
+}
+
+func (h *roleHandler) List(c *fiber.Ctx) error {
+	roles, err := h.roleService.List(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Roles listed successfully", roles))
+}
+
+func (h *roleHandler) Update(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var roleUpdate models.RoleUpdate
+	if err := c.BodyParser(&roleUpdate); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &roleUpdate); err != nil {
+		return err
+	}
+
+	role, err := h.roleService.Update(c.UserContext(), id, &roleUpdate)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Role updated successfully", role))
+}
+
+func (h *roleHandler) Delete(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.roleService.Delete(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Role deleted successfully", nil))
+}
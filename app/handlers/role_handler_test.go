@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/middleware"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRoleHandler(t *testing.T) {
+	role := &models.Role{ID: 1, Name: "admin"}
+
+	testCaseList := []struct {
+		name               string
+		url                string
+		method             string
+		jsonBody           string
+		expectedStatusCode int
+		mockFunc           func(serviceMock *services.RoleServiceMock)
+	}{
+		{
+			name:               "Create Success",
+			url:                "/",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"name": "admin"}`,
+			expectedStatusCode: 201,
+			mockFunc: func(serviceMock *services.RoleServiceMock) {
+				serviceMock.On("Create", mock.Anything, mock.Anything).Return(role, nil).Once()
+			},
+		},
+		{
+			name:               "Create Validation Error",
+			url:                "/",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"name": ""}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.RoleServiceMock) {},
+		},
+		{
+			name:               "Create Conflict",
+			url:                "/",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"name": "admin"}`,
+			expectedStatusCode: 409,
+			mockFunc: func(serviceMock *services.RoleServiceMock) {
+				serviceMock.On("Create", mock.Anything, mock.Anything).Return(nil, services.ErrRoleAlreadyExists).Once()
+			},
+		},
+		{
+			name:               "Get Success",
+			url:                "/1",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.RoleServiceMock) {
+				serviceMock.On("GetByID", mock.Anything, int64(1)).Return(role, nil).Once()
+			},
+		},
+		{
+			name:               "Get Not Found",
+			url:                "/1",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.RoleServiceMock) {
+				serviceMock.On("GetByID", mock.Anything, int64(1)).Return(nil, services.ErrRoleNotFound).Once()
+			},
+		},
+		{
+			name:               "Get Invalid ID",
+			url:                "/abc",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.RoleServiceMock) {},
+		},
+		{
+			name:               "List Success",
+			url:                "/",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.RoleServiceMock) {
+				serviceMock.On("List", mock.Anything).Return(&[]models.Role{*role}, nil).Once()
+			},
+		},
+		{
+			name:               "List Failed",
+			url:                "/",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 500,
+			mockFunc: func(serviceMock *services.RoleServiceMock) {
+				serviceMock.On("List", mock.Anything).Return(nil, errors.New("error")).Once()
+			},
+		},
+		{
+			name:               "Update Success",
+			url:                "/1",
+			method:             fiber.MethodPut,
+			jsonBody:           `{"name": "admin", "description": "Full access"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.RoleServiceMock) {
+				serviceMock.On("Update", mock.Anything, int64(1), mock.Anything).Return(role, nil).Once()
+			},
+		},
+		{
+			name:               "Update Not Found",
+			url:                "/1",
+			method:             fiber.MethodPut,
+			jsonBody:           `{"name": "admin"}`,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.RoleServiceMock) {
+				serviceMock.On("Update", mock.Anything, int64(1), mock.Anything).Return(nil, services.ErrRoleNotFound).Once()
+			},
+		},
+		{
+			name:               "Delete Success",
+			url:                "/1",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.RoleServiceMock) {
+				serviceMock.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+			},
+		},
+		{
+			name:               "Delete Not Found",
+			url:                "/1",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.RoleServiceMock) {
+				serviceMock.On("Delete", mock.Anything, int64(1)).Return(services.ErrRoleNotFound).Once()
+			},
+		},
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	roleServiceMock := services.NewRoleServiceMock()
+	handler := NewRoleHandler(roleServiceMock)
+	group := "/api/v1/role"
+	RegisterRoleRoutes(app.Group(group), handler, newAuthMiddlewareStub("role:read", "role:write"))
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockFunc(roleServiceMock)
+			req, _ := http.NewRequest(testCase.method, group+testCase.url, bytes.NewBufferString(testCase.jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			res, _ := app.Test(req, -1)
+			assert.Equal(t, testCase.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestRoleHandler_Forbidden(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	roleServiceMock := services.NewRoleServiceMock()
+	group := "/api/v1/role"
+	RegisterRoleRoutes(app.Group(group), NewRoleHandler(roleServiceMock), newAuthMiddlewareStub("role:read"))
+
+	req, _ := http.NewRequest(fiber.MethodPost, group+"/", bytes.NewBufferString(`{"name": "admin"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, _ := app.Test(req, -1)
+
+	assert.Equal(t, fiber.StatusForbidden, res.StatusCode)
+	roleServiceMock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -4,15 +4,20 @@ import (
 	"golang-template/app"
 	"golang-template/app/models"
 	"golang-template/app/services"
-	"golang-template/validator"
+	"golang-template/middleware"
+	"golang-template/openapi"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type UserHandler interface {
 	Register(c *fiber.Ctx) error
-	Update(c *fiber.Ctx) error
 	List(c *fiber.Ctx) error
+	Get(c *fiber.Ctx) error
+	Patch(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	Restore(c *fiber.Ctx) error
+	Unlock(c *fiber.Ctx) error
 }
 
 type userHandler struct {
@@ -23,53 +28,143 @@ func NewUserHandler(userService services.UserService) UserHandler {
 	return &userHandler{userService: userService}
 }
 
-func RegisterUserRoutes(route fiber.Router, handler UserHandler) {
-	route.Post("/register", handler.Register)
-	route.Put("/update", handler.Update)
-	route.Get("/list", handler.List)
+// RegisterUserRoutes mounts the /user routes and the /users/:id resource on
+// the versioned router.
+func RegisterUserRoutes(route fiber.Router, handler UserHandler, authMiddleware fiber.Handler) {
+	route.Post("/user/register", handler.Register).Name("registerUser")
+	route.Get("/user/list", authMiddleware, middleware.RequirePermission("user:read"), handler.List).Name("listUsers")
+
+	users := route.Group("/users", authMiddleware)
+	users.Get("/:id", middleware.RequirePermission("user:read"), handler.Get).Name("getUser")
+	users.Patch("/:id", middleware.RequirePermission("user:write"), handler.Patch).Name("patchUser")
+	users.Delete("/:id", middleware.RequirePermission("user:delete"), handler.Delete).Name("deleteUser")
+	users.Post("/:id/restore", middleware.RequirePermission("user:restore"), handler.Restore).Name("restoreUser")
+	users.Post("/:id/unlock", middleware.RequirePermission("user:unlock"), handler.Unlock).Name("unlockUser")
+}
+
+var userOperations = map[string]openapi.Operation{
+	"registerUser": {Summary: "Register a user", Tags: []string{"user"}, Request: models.UserRegister{}},
+	"listUsers":    {Summary: "List users", Tags: []string{"user"}, Permission: "user:read", Query: models.UserListQuery{}, Response: []models.User{}, Paged: true},
+	"getUser":      {Summary: "Get a user", Tags: []string{"user"}, Permission: "user:read", Params: uuidParams{}, Response: models.User{}},
+	"patchUser":    {Summary: "Update a user's username or email", Tags: []string{"user"}, Permission: "user:write", Params: uuidParams{}, Request: models.UserPatch{}, Response: models.User{}},
+	"deleteUser":   {Summary: "Delete a user", Tags: []string{"user"}, Permission: "user:delete", Params: uuidParams{}},
+	"restoreUser":  {Summary: "Restore a deleted user", Tags: []string{"user"}, Permission: "user:restore", Params: uuidParams{}, Response: models.User{}},
+	"unlockUser":   {Summary: "Lift a user's login lockout", Tags: []string{"user"}, Permission: "user:unlock", Params: uuidParams{}},
 }
 
 func (h *userHandler) Register(c *fiber.Ctx) error {
 	var newUser models.UserRegister
 	if err := c.BodyParser(&newUser); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(app.NewResponseError(err))
+		return errInvalidBody.Wrap(err)
 	}
 
-	if err := validator.ValidateStruct(&newUser); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(app.NewResponseError(err))
+	if err := validate(c, &newUser); err != nil {
+		return err
 	}
 
-	err := h.userService.Register(&newUser)
+	err := h.userService.Register(c.UserContext(), &newUser)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(app.NewResponseError(err))
+		return err
 	}
 
 	return c.JSON(app.NewResponse("User registered successfully", nil))
 }
 
-func (h *userHandler) Update(c *fiber.Ctx) error {
-	var userUpdate models.UserUpdatePassword
-	if err := c.BodyParser(&userUpdate); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(app.NewResponseError(err))
+func (h *userHandler) List(c *fiber.Ctx) error {
+	var query models.UserListQuery
+	if err := c.QueryParser(&query); err != nil {
+		return errInvalidQuery.Wrap(err)
 	}
 
-	if err := validator.ValidateStruct(&userUpdate); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(app.NewResponseError(err))
+	if err := validate(c, &query); err != nil {
+		return err
 	}
 
-	err := h.userService.Update(&userUpdate)
+	list, err := h.userService.List(c.UserContext(), &query)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(app.NewResponseError(err))
+		return err
 	}
 
-	return c.JSON(app.NewResponse("User updated successfully", nil))
+	paging := app.Paging{PageSize: list.PageSize, Total: list.Total, NextCursor: list.NextCursor}
+	if query.Cursor == "" {
+		paging.Page = list.Page
+	}
+	return c.JSON(app.NewPagedResponse("Users listed successfully", list.Users, paging))
 }
 
-func (h *userHandler) List(c *fiber.Ctx) error {
-	users, err := h.userService.List()
+func (h *userHandler) Get(c *fiber.Ctx) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("User retrieved successfully", user))
+}
+
+func (h *userHandler) Patch(c *fiber.Ctx) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var patch models.UserPatch
+	if err := c.BodyParser(&patch); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &patch); err != nil {
+		return err
+	}
+
+	user, err := h.userService.Patch(c.UserContext(), id, &patch)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("User updated successfully", user))
+}
+
+func (h *userHandler) Delete(c *fiber.Ctx) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.userService.Delete(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("User deleted successfully", nil))
+}
+
+func (h *userHandler) Restore(c *fiber.Ctx) error {
+	id, err := paramUUID(c, "id")
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(app.NewResponseError(err))
+		return err
+	}
+
+	user, err := h.userService.Restore(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("User restored successfully", user))
+}
+
+func (h *userHandler) Unlock(c *fiber.Ctx) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.userService.Unlock(c.UserContext(), id); err != nil {
+		return err
 	}
 
-	return c.JSON(app.NewResponse("Users listed successfully", &users))
+	return c.JSON(app.NewResponse("User unlocked successfully", nil))
 }
@@ -6,15 +6,21 @@ import (
 	"fmt"
 	"golang-template/app/models"
 	"golang-template/app/services"
+	"golang-template/middleware"
+	"golang-template/token"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+const testUserID = "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01"
+
 type testCase struct {
 	name               string
 	url                string
@@ -29,85 +35,228 @@ func TestUserHandler(t *testing.T) {
 	testCaseList := []testCase{
 		{
 			name:               "Register Success",
-			url:                "/register",
+			url:                "/user/register",
 			method:             fiber.MethodPost,
-			jsonBody:           `{"username": "test", "email": "test@test.com", "password": "test"}`,
+			jsonBody:           `{"username": "test", "email": "test@test.com", "password": "Passw0rd1"}`,
 			expectedStatusCode: 200,
 			mockFunc: func(serviceMock *services.UserServiceMock) {
-				serviceMock.On("Register", mock.Anything).Return(nil).Once()
+				serviceMock.On("Register", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name:               "Register Duplicate",
+			url:                "/user/register",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"username": "test", "email": "test@test.com", "password": "Passw0rd1"}`,
+			expectedStatusCode: 409,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("Register", mock.Anything, mock.Anything).Return(services.ErrUserAlreadyExists).Once()
+			},
+		},
+		{
+			name:               "Register Unexpected Error",
+			url:                "/user/register",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"username": "test", "email": "test@test.com", "password": "Passw0rd1"}`,
+			expectedStatusCode: 500,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("Register", mock.Anything, mock.Anything).Return(errors.New("UNIQUE constraint failed")).Once()
 			},
 		},
 		{
 			name:               "Register Failed",
-			url:                "/register",
+			url:                "/user/register",
 			method:             fiber.MethodPost,
-			jsonBody:           `{"username": "", "email": "test@test.com", "password": "test"}`,
+			jsonBody:           `{"username": "", "email": "test@test.com", "password": "Passw0rd1"}`,
 			expectedStatusCode: 400,
 			mockFunc: func(serviceMock *services.UserServiceMock) {
-				serviceMock.On("Register", mock.Anything).Return(errors.New("error")).Once()
+				serviceMock.On("Register", mock.Anything, mock.Anything).Return(errors.New("error")).Once()
 			},
 		},
+		{
+			name:               "Register Weak Password",
+			url:                "/user/register",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"username": "test", "email": "test@test.com", "password": "password"}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.UserServiceMock) {},
+		},
+		{
+			name:               "Register Password Too Long",
+			url:                "/user/register",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"username": "test", "email": "test@test.com", "password": "Passw0rd` + strings.Repeat("x", 65) + `"}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.UserServiceMock) {},
+		},
 		{
 			name:               "Register Body Empty",
-			url:                "/register",
+			url:                "/user/register",
 			method:             fiber.MethodPost,
 			jsonBody:           "",
 			expectedStatusCode: 400,
 			mockFunc:           func(serviceMock *services.UserServiceMock) {},
 		},
 		{
-			name:               "Update Password Success",
-			url:                "/update",
-			method:             fiber.MethodPut,
-			jsonBody:           `{"username": "test", "newPassword": "test"}`,
+			name:               "List Success",
+			url:                "/user/list?page=2&pageSize=10&sort=-createdAt&q=test",
+			method:             fiber.MethodGet,
 			expectedStatusCode: 200,
 			mockFunc: func(serviceMock *services.UserServiceMock) {
-				serviceMock.On("Update", mock.Anything).Return(nil).Once()
+				serviceMock.On("List", mock.Anything, &models.UserListQuery{Page: 2, PageSize: 10, Sort: "-createdAt", Q: "test"}).
+					Return(&models.UserList{Users: []models.User{}, Page: 2, PageSize: 10}, nil).Once()
 			},
 		},
 		{
-			name:               "Update Password Failed",
-			url:                "/update",
-			method:             fiber.MethodPut,
-			jsonBody:           `{"username": "test", "newPassword": ""}`,
+			name:               "List Invalid Sort",
+			url:                "/user/list?sort=password",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.UserServiceMock) {},
+		},
+		{
+			name:               "List Invalid Cursor",
+			url:                "/user/list?cursor=bad",
+			method:             fiber.MethodGet,
 			expectedStatusCode: 400,
 			mockFunc: func(serviceMock *services.UserServiceMock) {
-				serviceMock.On("Update", mock.Anything).Return(errors.New("error")).Once()
+				serviceMock.On("List", mock.Anything, mock.Anything).Return(nil, services.ErrInvalidCursor).Once()
 			},
 		},
 		{
-			name:               "Update Password Body Empty",
-			url:                "/update",
-			method:             fiber.MethodPut,
-			jsonBody:           "",
-			expectedStatusCode: 400,
-			mockFunc:           func(serviceMock *services.UserServiceMock) {},
+			name:               "List Failed",
+			url:                "/user/list",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 500,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("List", mock.Anything, mock.Anything).Return(nil, errors.New("error")).Once()
+			},
 		},
 		{
-			name:               "List Success",
-			url:                "/list",
+			name:               "Get Success",
+			url:                "/users/" + testUserID,
 			method:             fiber.MethodGet,
 			expectedStatusCode: 200,
 			mockFunc: func(serviceMock *services.UserServiceMock) {
-				serviceMock.On("List", mock.Anything).Return(&[]models.User{}, nil).Once()
+				serviceMock.On("GetByID", mock.Anything, testUserID).Return(&models.User{ID: testUserID}, nil).Once()
 			},
 		},
 		{
-			name:               "List Failed",
-			url:                "/list",
+			name:               "Get Upper Case ID",
+			url:                "/users/0190F5A6-3C1E-7B2A-9D4F-5E6A7B8C9D01",
 			method:             fiber.MethodGet,
-			expectedStatusCode: 500,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("GetByID", mock.Anything, testUserID).Return(&models.User{ID: testUserID}, nil).Once()
+			},
+		},
+		{
+			name:               "Get Not Found",
+			url:                "/users/" + testUserID,
+			method:             fiber.MethodGet,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("GetByID", mock.Anything, testUserID).Return(nil, services.ErrUserNotFound).Once()
+			},
+		},
+		{
+			name:               "Get Invalid ID",
+			url:                "/users/1",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.UserServiceMock) {},
+		},
+		{
+			name:               "Patch Success",
+			url:                "/users/" + testUserID,
+			method:             fiber.MethodPatch,
+			jsonBody:           `{"email": "new@test.com"}`,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("Patch", mock.Anything, testUserID, mock.MatchedBy(func(patch *models.UserPatch) bool {
+					return patch.Username == nil && patch.Email != nil && *patch.Email == "new@test.com"
+				})).Return(&models.User{ID: testUserID}, nil).Once()
+			},
+		},
+		{
+			name:               "Patch Invalid Email",
+			url:                "/users/" + testUserID,
+			method:             fiber.MethodPatch,
+			jsonBody:           `{"email": "not-an-email"}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.UserServiceMock) {},
+		},
+		{
+			name:               "Patch Duplicate",
+			url:                "/users/" + testUserID,
+			method:             fiber.MethodPatch,
+			jsonBody:           `{"username": "taken"}`,
+			expectedStatusCode: 409,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("Patch", mock.Anything, testUserID, mock.Anything).Return(nil, services.ErrUserAlreadyExists).Once()
+			},
+		},
+		{
+			name:               "Delete Success",
+			url:                "/users/" + testUserID,
+			method:             fiber.MethodDelete,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("Delete", mock.Anything, testUserID).Return(nil).Once()
+			},
+		},
+		{
+			name:               "Delete Not Found",
+			url:                "/users/" + testUserID,
+			method:             fiber.MethodDelete,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("Delete", mock.Anything, testUserID).Return(services.ErrUserNotFound).Once()
+			},
+		},
+		{
+			name:               "Restore Success",
+			url:                "/users/" + testUserID + "/restore",
+			method:             fiber.MethodPost,
+			expectedStatusCode: 200,
 			mockFunc: func(serviceMock *services.UserServiceMock) {
-				serviceMock.On("List", mock.Anything).Return(nil, errors.New("error")).Once()
+				serviceMock.On("Restore", mock.Anything, testUserID).Return(&models.User{ID: testUserID}, nil).Once()
+			},
+		},
+		{
+			name:               "Restore Not Deleted",
+			url:                "/users/" + testUserID + "/restore",
+			method:             fiber.MethodPost,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("Restore", mock.Anything, testUserID).Return(nil, services.ErrDeletedUserNotFound).Once()
+			},
+		},
+		{
+			name:               "Unlock Success",
+			url:                "/users/" + testUserID + "/unlock",
+			method:             fiber.MethodPost,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("Unlock", mock.Anything, testUserID).Return(nil).Once()
+			},
+		},
+		{
+			name:               "Unlock Not Found",
+			url:                "/users/" + testUserID + "/unlock",
+			method:             fiber.MethodPost,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.UserServiceMock) {
+				serviceMock.On("Unlock", mock.Anything, testUserID).Return(services.ErrUserNotFound).Once()
 			},
 		},
 	}
 
-	app := fiber.New()
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
 	userServiceMock := services.NewUserServiceMock()
 	handler := NewUserHandler(userServiceMock)
-	group := "/api/v1/user"
-	RegisterUserRoutes(app.Group(group), handler)
+	group := "/api/v1"
+	RegisterUserRoutes(app.Group(group), handler, newAuthMiddlewareStub("user:read", "user:write", "user:delete", "user:restore", "user:unlock"))
 
 	for _, testCase := range testCaseList {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -122,3 +271,14 @@ func TestUserHandler(t *testing.T) {
 	}
 
 }
+
+func newAuthMiddlewareStub(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		middleware.SetCurrentUser(c, &token.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{Subject: testUserID},
+			Username:         "test",
+			Permissions:      permissions,
+		})
+		return c.Next()
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/middleware"
+	"golang-template/openapi"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type UserRoleHandler interface {
+	AssignRole(c *fiber.Ctx) error
+	RemoveRole(c *fiber.Ctx) error
+	ListUserRoles(c *fiber.Ctx) error
+	ListUserPermissions(c *fiber.Ctx) error
+	AssignPermission(c *fiber.Ctx) error
+	RemovePermission(c *fiber.Ctx) error
+	ListRolePermissions(c *fiber.Ctx) error
+}
+
+type userRoleHandler struct {
+	userRoleService services.UserRoleService
+}
+
+func NewUserRoleHandler(userRoleService services.UserRoleService) UserRoleHandler {
+	return &userRoleHandler{userRoleService: userRoleService}
+}
+
+func RegisterUserRoleRoutes(route fiber.Router, handler UserRoleHandler, authMiddleware fiber.Handler) {
+	route.Use(authMiddleware)
+	route.Get("/users/:userId/roles", middleware.RequirePermission("role:read"), handler.ListUserRoles).Name("listUserRoles")
+	route.Post("/users/:userId/roles", middleware.RequirePermission("role:write"), handler.AssignRole).Name("assignRole")
+	route.Delete("/users/:userId/roles/:roleId", middleware.RequirePermission("role:write"), handler.RemoveRole).Name("removeRole")
+	route.Get("/users/:userId/permissions", middleware.RequirePermission("role:read"), handler.ListUserPermissions).Name("listUserPermissions")
+	route.Get("/roles/:roleId/permissions", middleware.RequirePermission("role:read"), handler.ListRolePermissions).Name("listRolePermissions")
+	route.Post("/roles/:roleId/permissions", middleware.RequirePermission("role:write"), handler.AssignPermission).Name("assignPermission")
+	route.Delete("/roles/:roleId/permissions/:permissionId", middleware.RequirePermission("role:write"), handler.RemovePermission).Name("removePermission")
+}
+
+var userRoleOperations = map[string]openapi.Operation{
+	"listUserRoles":       {Summary: "List a user's roles", Tags: []string{"user-role"}, Permission: "role:read", Params: userRoleParams{}, Response: []models.Role{}},
+	"assignRole":          {Summary: "Assign a role to a user", Tags: []string{"user-role"}, Permission: "role:write", Params: userRoleParams{}, Request: models.UserRoleAssign{}},
+	"removeRole":          {Summary: "Remove a role from a user", Tags: []string{"user-role"}, Permission: "role:write", Params: userRoleParams{}},
+	"listUserPermissions": {Summary: "List a user's effective permissions", Tags: []string{"user-role"}, Permission: "role:read", Params: userRoleParams{}, Response: []models.Permission{}},
+	"listRolePermissions": {Summary: "List a role's permissions", Tags: []string{"user-role"}, Permission: "role:read", Params: userRoleParams{}, Response: []models.Permission{}},
+	"assignPermission":    {Summary: "Grant a permission to a role", Tags: []string{"user-role"}, Permission: "role:write", Params: userRoleParams{}, Request: models.RolePermissionAssign{}},
+	"removePermission":    {Summary: "Revoke a permission from a role", Tags: []string{"user-role"}, Permission: "role:write", Params: userRoleParams{}},
+}
+
+func (h *userRoleHandler) AssignRole(c *fiber.Ctx) error {
+	userID, err := paramUUID(c, "userId")
+	if err != nil {
+		return err
+	}
+
+	var assign models.UserRoleAssign
+	if err := c.BodyParser(&assign); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &assign); err != nil {
+		return err
+	}
+
+	if err := h.userRoleService.AssignRole(c.UserContext(), userID, assign.RoleID); err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Role assigned successfully", nil))
+}
+
+func (h *userRoleHandler) RemoveRole(c *fiber.Ctx) error {
+	userID, err := paramUUID(c, "userId")
+	if err != nil {
+		return err
+	}
+
+	roleID, err := paramID(c, "roleId")
+	if err != nil {
+		return err
+	}
+
+	if err := h.userRoleService.RemoveRole(c.UserContext(), userID, roleID); err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Role removed successfully", nil))
+}
+
+func (h *userRoleHandler) ListUserRoles(c *fiber.Ctx) error {
+	userID, err := paramUUID(c, "userId")
+	if err != nil {
+		return err
+	}
+
+	roles, err := h.userRoleService.ListUserRoles(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("User roles listed successfully", roles))
+}
+
+func (h *userRoleHandler) ListUserPermissions(c *fiber.Ctx) error {
+	userID, err := paramUUID(c, "userId")
+	if err != nil {
+		return err
+	}
+
+	permissions, err := h.userRoleService.ListUserPermissions(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("User permissions listed successfully", permissions))
+}
+
+func (h *userRoleHandler) AssignPermission(c *fiber.Ctx) error {
+	roleID, err := paramID(c, "roleId")
+	if err != nil {
+		return err
+	}
+
+	var assign models.RolePermissionAssign
+	if err := c.BodyParser(&assign); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+
+	if err := validate(c, &assign); err != nil {
+		return err
+	}
+
+	if err := h.userRoleService.AssignPermission(c.UserContext(), roleID, assign.PermissionID); err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Permission assigned successfully", nil))
+}
+
+func (h *userRoleHandler) RemovePermission(c *fiber.Ctx) error {
+	roleID, err := paramID(c, "roleId")
+	if err != nil {
+		return err
+	}
+
+	permissionID, err := paramID(c, "permissionId")
+	if err != nil {
+		return err
+	}
+
+	if err := h.userRoleService.RemovePermission(c.UserContext(), roleID, permissionID); err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Permission removed successfully", nil))
+}
+
+func (h *userRoleHandler) ListRolePermissions(c *fiber.Ctx) error {
+	roleID, err := paramID(c, "roleId")
+	if err != nil {
+		return err
+	}
+
+	permissions, err := h.userRoleService.ListRolePermissions(c.UserContext(), roleID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(app.NewResponse("Role permissions listed successfully", permissions))
+}
+
+func paramID(c *fiber.Ctx, key string) (int64, error) {
+	id, err := c.ParamsInt(key)
+	if err != nil || id <= 0 {
+		return 0, errInvalidID
+	}
+	return int64(id), nil
+}
+
+// paramUUID returns the parameter in canonical form, so differently cased
+// IDs address the same row.
+func paramUUID(c *fiber.Ctx, key string) (string, error) {
+	id, err := uuid.Parse(c.Params(key))
+	if err != nil {
+		return "", errInvalidID
+	}
+	return id.String(), nil
+}
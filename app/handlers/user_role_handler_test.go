@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"golang-template/app/models"
+	"golang-template/app/services"
+	"golang-template/middleware"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserRoleHandler(t *testing.T) {
+	testCaseList := []struct {
+		name               string
+		url                string
+		method             string
+		jsonBody           string
+		expectedStatusCode int
+		mockFunc           func(serviceMock *services.UserRoleServiceMock)
+	}{
+		{
+			name:               "Assign Role Success",
+			url:                "/users/" + testUserID + "/roles",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"roleId": 2}`,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.UserRoleServiceMock) {
+				serviceMock.On("AssignRole", mock.Anything, testUserID, int64(2)).Return(nil).Once()
+			},
+		},
+		{
+			name:               "Assign Role Validation Error",
+			url:                "/users/" + testUserID + "/roles",
+			method:             fiber.MethodPost,
+			jsonBody:           `{}`,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.UserRoleServiceMock) {},
+		},
+		{
+			name:               "Assign Role User Not Found",
+			url:                "/users/" + testUserID + "/roles",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"roleId": 2}`,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.UserRoleServiceMock) {
+				serviceMock.On("AssignRole", mock.Anything, testUserID, int64(2)).Return(services.ErrUserNotFound).Once()
+			},
+		},
+		{
+			name:               "Remove Role Success",
+			url:                "/users/" + testUserID + "/roles/2",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.UserRoleServiceMock) {
+				serviceMock.On("RemoveRole", mock.Anything, testUserID, int64(2)).Return(nil).Once()
+			},
+		},
+		{
+			name:               "Remove Role Not Assigned",
+			url:                "/users/" + testUserID + "/roles/2",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.UserRoleServiceMock) {
+				serviceMock.On("RemoveRole", mock.Anything, testUserID, int64(2)).Return(services.ErrRoleNotAssigned).Once()
+			},
+		},
+		{
+			name:               "Remove Role Invalid ID",
+			url:                "/users/" + testUserID + "/roles/abc",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.UserRoleServiceMock) {},
+		},
+		{
+			name:               "List User Roles Success",
+			url:                "/users/" + testUserID + "/roles",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.UserRoleServiceMock) {
+				serviceMock.On("ListUserRoles", mock.Anything, testUserID).Return(&[]models.Role{{ID: 2, Name: "admin"}}, nil).Once()
+			},
+		},
+		{
+			name:               "List User Permissions Failed",
+			url:                "/users/" + testUserID + "/permissions",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 500,
+			mockFunc: func(serviceMock *services.UserRoleServiceMock) {
+				serviceMock.On("ListUserPermissions", mock.Anything, testUserID).Return(nil, errors.New("error")).Once()
+			},
+		},
+		{
+			name:               "List User Roles Invalid User ID",
+			url:                "/users/1/roles",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 400,
+			mockFunc:           func(serviceMock *services.UserRoleServiceMock) {},
+		},
+		{
+			name:               "Assign Permission Success",
+			url:                "/roles/2/permissions",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"permissionId": 3}`,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.UserRoleServiceMock) {
+				serviceMock.On("AssignPermission", mock.Anything, int64(2), int64(3)).Return(nil).Once()
+			},
+		},
+		{
+			name:               "Assign Permission Not Found",
+			url:                "/roles/2/permissions",
+			method:             fiber.MethodPost,
+			jsonBody:           `{"permissionId": 3}`,
+			expectedStatusCode: 404,
+			mockFunc: func(serviceMock *services.UserRoleServiceMock) {
+				serviceMock.On("AssignPermission", mock.Anything, int64(2), int64(3)).Return(services.ErrPermissionNotFound).Once()
+			},
+		},
+		{
+			name:               "Remove Permission Success",
+			url:                "/roles/2/permissions/3",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.UserRoleServiceMock) {
+				serviceMock.On("RemovePermission", mock.Anything, int64(2), int64(3)).Return(nil).Once()
+			},
+		},
+		{
+			name:               "List Role Permissions Success",
+			url:                "/roles/2/permissions",
+			method:             fiber.MethodGet,
+			expectedStatusCode: 200,
+			mockFunc: func(serviceMock *services.UserRoleServiceMock) {
+				serviceMock.On("ListRolePermissions", mock.Anything, int64(2)).Return(&[]models.Permission{{ID: 3, Name: "user:read"}}, nil).Once()
+			},
+		},
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	userRoleServiceMock := services.NewUserRoleServiceMock()
+	handler := NewUserRoleHandler(userRoleServiceMock)
+	group := "/api/v1/user-role"
+	RegisterUserRoleRoutes(app.Group(group), handler, newAuthMiddlewareStub("role:read", "role:write"))
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockFunc(userRoleServiceMock)
+			req, _ := http.NewRequest(testCase.method, group+testCase.url, bytes.NewBufferString(testCase.jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			res, _ := app.Test(req, -1)
+			assert.Equal(t, testCase.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package models
+
+import "time"
+
+type UserLogin struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	TokenType    string `json:"tokenType,omitempty"`
+	ExpiresIn    int64  `json:"expiresIn,omitempty"`
+}
+
+// LoginResponse holds either the token pair or, for users with two-factor
+// authentication, the challenge to complete at /auth/mfa/verify.
+type LoginResponse struct {
+	TokenPair
+	MFARequired        bool   `json:"mfaRequired,omitempty"`
+	ChallengeToken     string `json:"challengeToken,omitempty"`
+	ChallengeExpiresIn int64  `json:"challengeExpiresIn,omitempty"`
+	// MFAEnrollment is set for users that must use two-factor authentication
+	// but haven't enabled it. Completing the challenge with a code of this
+	// secret enables it.
+	MFAEnrollment *MFAEnrollment `json:"mfaEnrollment,omitempty"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type EmailVerificationToken struct {
+	ID     int64
+	UserID string
+	// Email is the address the token was mailed to and the only one it
+	// verifies.
+	Email     string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,password"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,password"`
+}
+
+type PasswordResetToken struct {
+	ID        int64
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type RefreshToken struct {
+	ID        int64
+	UserID    string
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// LoginAttempt counts failed logins for one account or client IP. A lockout
+// sets LockedUntil and starts counting failures again.
+type LoginAttempt struct {
+	Scope        string
+	Key          string
+	Failures     int
+	Lockouts     int
+	LockedUntil  *time.Time
+	LastFailedAt time.Time
+}
+
+// LoginLockout is returned in the details of a login_locked error.
+type LoginLockout struct {
+	RetryAfter int `json:"retryAfter"`
+}
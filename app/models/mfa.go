@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// UserTOTP is a user's TOTP secret. Two-factor authentication is enabled once
+// ConfirmedAt is set.
+type UserTOTP struct {
+	UserID      string
+	Secret      string
+	ConfirmedAt *time.Time
+	// LastUsedStep is the time step of the last accepted code, codes of this
+	// or earlier steps are refused so a code works only once.
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+type MFAChallenge struct {
+	ID        int64
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	Attempts  int
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MFAEnrollment is what the user adds to an authenticator app, by scanning
+// the QR code or entering the secret.
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauthUri"`
+	// QRCodePNG is the base64 encoded PNG of OTPAuthURI.
+	QRCodePNG string `json:"qrCodePng"`
+}
+
+type MFACodeRequest struct {
+	OTP string `json:"otp" validate:"required"`
+}
+
+// MFAVerifyRequest completes a login. OTP is a code from the app or an
+// unused recovery code.
+type MFAVerifyRequest struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	OTP            string `json:"otp" validate:"required"`
+}
+
+type MFADisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	OTP      string `json:"otp" validate:"required"`
+}
+
+// MFAVerifyResponse holds the token pair and, when the login enabled
+// two-factor authentication, the new recovery codes.
+type MFAVerifyResponse struct {
+	TokenPair
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+}
+
+// MFARecoveryCodes are shown once, only their hashes are stored.
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type Permission struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Resource    string    `json:"resource"`
+	Action      string    `json:"action"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type PermissionCreate struct {
+	Resource    string `json:"resource" validate:"required"`
+	Action      string `json:"action" validate:"required"`
+	Description string `json:"description"`
+}
+
+type PermissionUpdate struct {
+	Description string `json:"description"`
+}
+
+func PermissionName(resource string, action string) string {
+	return resource + ":" + action
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type Role struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type RoleCreate struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+type RoleUpdate struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
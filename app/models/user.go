@@ -3,19 +3,42 @@ package models
 import "time"
 
 type User struct {
-	Username  string    `json:"username"`
-	Email     string    `json:"email" `
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email" `
+	Password string `json:"-"`
+	// VerifiedAt is nil until the user proves they own Email.
+	VerifiedAt *time.Time `json:"verifiedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
 }
 
 type UserRegister struct {
-	Username string `json:"username" validate:"required"`
+	Username string `json:"username" validate:"required,username"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Password string `json:"password" validate:"required,password"`
 }
 
-type UserUpdatePassword struct {
-	Username    string `json:"username" validate:"required"`
-	NewPassword string `json:"newPassword" validate:"required"`
+// UserPatch changes only the fields that are set.
+type UserPatch struct {
+	Username *string `json:"username" validate:"omitempty,username"`
+	Email    *string `json:"email" validate:"omitempty,email"`
+}
+
+type UserListQuery struct {
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	PageSize int    `query:"pageSize" validate:"omitempty,min=1,max=100"`
+	Cursor   string `query:"cursor"`
+	Sort     string `query:"sort" validate:"omitempty,oneof=id -id username -username email -email createdAt -createdAt updatedAt -updatedAt"`
+	Username string `query:"username"`
+	Email    string `query:"email" validate:"omitempty,email"`
+	Q        string `query:"q" validate:"omitempty,max=100"`
+}
+
+type UserList struct {
+	Users      []User
+	Page       int
+	PageSize   int
+	Total      int64
+	NextCursor string
 }
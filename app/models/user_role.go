@@ -0,0 +1,9 @@
+package models
+
+type UserRoleAssign struct {
+	RoleID int64 `json:"roleId" validate:"required"`
+}
+
+type RolePermissionAssign struct {
+	PermissionID int64 `json:"permissionId" validate:"required"`
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+)
+
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, verificationToken *models.EmailVerificationToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error)
+	MarkVerified(ctx context.Context, userID string, email string) error
+}
+
+type emailVerificationRepository struct {
+	db *sql.DB
+}
+
+func NewEmailVerificationRepository(db *sql.DB) EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+func (r *emailVerificationRepository) Create(ctx context.Context, verificationToken *models.EmailVerificationToken) error {
+	query := `
+		INSERT INTO email_verification_tokens (user_id, email, token_hash, expires_at)
+		VALUES (?, ?, ?, ?)
+	`
+	result, err := r.db.ExecContext(ctx, query, verificationToken.UserID, verificationToken.Email, verificationToken.TokenHash, verificationToken.ExpiresAt)
+	if err != nil {
+		return mapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	verificationToken.ID = id
+	return nil
+}
+
+func (r *emailVerificationRepository) GetByHash(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error) {
+	query := `
+		SELECT id, user_id, email, token_hash, expires_at, used_at, created_at FROM email_verification_tokens
+		WHERE token_hash = ?
+	`
+	var verificationToken models.EmailVerificationToken
+	var usedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&verificationToken.ID,
+		&verificationToken.UserID,
+		&verificationToken.Email,
+		&verificationToken.TokenHash,
+		&verificationToken.ExpiresAt,
+		&usedAt,
+		&verificationToken.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		verificationToken.UsedAt = &usedAt.Time
+	}
+	return &verificationToken, nil
+}
+
+// MarkVerified uses up every pending token of the user for email and marks
+// the user verified if email is still their address. It returns
+// sql.ErrNoRows when no token was pending, which means a concurrent request
+// already used it, or when the address changed since the token was sent.
+func (r *emailVerificationRepository) MarkVerified(ctx context.Context, userID string, email string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE email_verification_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND email = ? AND used_at IS NULL
+	`, userID, email)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	err = execAffectingRow(ctx, tx, `
+		UPDATE users SET verified_at = COALESCE(verified_at, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND email = ? AND deleted_at IS NULL
+	`, userID, email)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
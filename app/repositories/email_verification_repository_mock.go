@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type EmailVerificationRepositoryMock struct {
+	mock.Mock
+}
+
+func NewEmailVerificationRepositoryMock() *EmailVerificationRepositoryMock {
+	return &EmailVerificationRepositoryMock{}
+}
+
+func (m *EmailVerificationRepositoryMock) Create(ctx context.Context, verificationToken *models.EmailVerificationToken) error {
+	args := m.Mock.Called(ctx, verificationToken)
+	return args.Error(0)
+}
+
+func (m *EmailVerificationRepositoryMock) GetByHash(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error) {
+	args := m.Mock.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailVerificationToken), args.Error(1)
+}
+
+func (m *EmailVerificationRepositoryMock) MarkVerified(ctx context.Context, userID string, email string) error {
+	args := m.Mock.Called(ctx, userID, email)
+	return args.Error(0)
+}
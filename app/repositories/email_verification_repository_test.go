@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailVerificationRepository_Create(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewEmailVerificationRepository(db)
+	expiresAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	verificationToken := &models.EmailVerificationToken{UserID: testUserID, Email: "test@example.com", TokenHash: "hash", ExpiresAt: expiresAt}
+
+	mock.ExpectExec("INSERT INTO email_verification_tokens").
+		WithArgs(testUserID, "test@example.com", "hash", expiresAt).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	err := repo.Create(context.Background(), verificationToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), verificationToken.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEmailVerificationRepository_GetByHash(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewEmailVerificationRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "email", "token_hash", "expires_at", "used_at", "created_at"}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedToken *models.EmailVerificationToken
+		expectedError error
+	}{
+		{
+			name: "unused token",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM email_verification_tokens WHERE token_hash = ?").
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, testUserID, "test@example.com", "hash", testTime, nil, testTime))
+			},
+			expectedToken: &models.EmailVerificationToken{ID: 1, UserID: testUserID, Email: "test@example.com", TokenHash: "hash", ExpiresAt: testTime, CreatedAt: testTime},
+		},
+		{
+			name: "used token",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM email_verification_tokens").
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, testUserID, "test@example.com", "hash", testTime, testTime, testTime))
+			},
+			expectedToken: &models.EmailVerificationToken{ID: 1, UserID: testUserID, Email: "test@example.com", TokenHash: "hash", ExpiresAt: testTime, UsedAt: &testTime, CreatedAt: testTime},
+		},
+		{
+			name: "unknown token",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM email_verification_tokens").
+					WithArgs("hash").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			verificationToken, err := repo.GetByHash(context.Background(), "hash")
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedToken, verificationToken)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestEmailVerificationRepository_MarkVerified(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewEmailVerificationRepository(db)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "uses the tokens and verifies the user",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE email_verification_tokens SET used_at = CURRENT_TIMESTAMP\s+WHERE user_id = \? AND email = \? AND used_at IS NULL`).
+					WithArgs(testUserID, "test@example.com").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`UPDATE users SET verified_at = COALESCE\(verified_at, CURRENT_TIMESTAMP\)(.+)WHERE id = \? AND email = \?`).
+					WithArgs(testUserID, "test@example.com").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "address changed since sending",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE email_verification_tokens`).
+					WithArgs(testUserID, "test@example.com").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE users`).
+					WithArgs(testUserID, "test@example.com").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedError: sql.ErrNoRows,
+		},
+		{
+			name: "token already used",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE email_verification_tokens`).
+					WithArgs(testUserID, "test@example.com").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.MarkVerified(context.Background(), testUserID, "test@example.com")
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"errors"
+	"golang-template/app"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	ErrDuplicate          = app.NewConflictError("duplicate", "resource already exists")
+	ErrConstraintViolated = app.NewConflictError("constraint_violation", "request conflicts with existing data")
+)
+
+// mapError translates SQLite constraint failures into application errors so
+// raw driver messages never reach clients. Other errors are returned as is.
+func mapError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.Code != sqlite3.ErrConstraint {
+		return err
+	}
+
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return ErrDuplicate.Wrap(err)
+	default:
+		return ErrConstraintViolated.Wrap(err)
+	}
+}
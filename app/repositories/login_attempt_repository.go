@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-template/app/models"
+	"sync"
+	"time"
+)
+
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, scope string, key string) (*models.LoginAttempt, error)
+	Update(ctx context.Context, scope string, key string, fn func(attempt *models.LoginAttempt)) (*models.LoginAttempt, error)
+	Delete(ctx context.Context, scope string, key string) error
+	DeleteStale(ctx context.Context, before time.Time) (int64, error)
+}
+
+type loginAttemptRepository struct {
+	// mu serializes updates: SQLite fails instead of waiting when two
+	// deferred transactions both try to upgrade their read lock.
+	mu sync.Mutex
+	db *sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Get(ctx context.Context, scope string, key string) (*models.LoginAttempt, error) {
+	return getLoginAttempt(ctx, r.db, scope, key)
+}
+
+// Update reads the attempt, or a zero one with scope and key set, passes it to
+// fn and saves the result in one transaction.
+func (r *loginAttemptRepository) Update(ctx context.Context, scope string, key string, fn func(attempt *models.LoginAttempt)) (*models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	attempt, err := getLoginAttempt(ctx, tx, scope, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		attempt, err = &models.LoginAttempt{Scope: scope, Key: key}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fn(attempt)
+
+	var lockedUntil any
+	if attempt.LockedUntil != nil {
+		lockedUntil = attempt.LockedUntil.UTC().Format(sqliteTimeFormat)
+	}
+	query := `
+		INSERT INTO login_attempts (scope, key, failures, lockouts, locked_until, last_failed_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			failures = excluded.failures,
+			lockouts = excluded.lockouts,
+			locked_until = excluded.locked_until,
+			last_failed_at = excluded.last_failed_at
+	`
+	_, err = tx.ExecContext(ctx, query, scope, key, attempt.Failures, attempt.Lockouts, lockedUntil, attempt.LastFailedAt.UTC().Format(sqliteTimeFormat))
+	if err != nil {
+		return nil, err
+	}
+	return attempt, tx.Commit()
+}
+
+// Delete removes the attempt and returns sql.ErrNoRows when there was none.
+func (r *loginAttemptRepository) Delete(ctx context.Context, scope string, key string) error {
+	return execAffectingRow(ctx, r.db, "DELETE FROM login_attempts WHERE scope = ? AND key = ?", scope, key)
+}
+
+// DeleteStale removes attempts whose last failure and lockout both ended
+// before the given time.
+func (r *loginAttemptRepository) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM login_attempts
+		WHERE last_failed_at < ? AND (locked_until IS NULL OR locked_until < ?)
+	`
+	cutoff := before.UTC().Format(sqliteTimeFormat)
+	result, err := r.db.ExecContext(ctx, query, cutoff, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func getLoginAttempt(ctx context.Context, db queryRower, scope string, key string) (*models.LoginAttempt, error) {
+	query := `
+		SELECT scope, key, failures, lockouts, locked_until, last_failed_at FROM login_attempts
+		WHERE scope = ? AND key = ?
+	`
+	var attempt models.LoginAttempt
+	var lockedUntil sql.NullTime
+	err := db.QueryRowContext(ctx, query, scope, key).Scan(
+		&attempt.Scope,
+		&attempt.Key,
+		&attempt.Failures,
+		&attempt.Lockouts,
+		&lockedUntil,
+		&attempt.LastFailedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if lockedUntil.Valid {
+		attempt.LockedUntil = &lockedUntil.Time
+	}
+	return &attempt, nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"golang-template/app/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LoginAttemptRepositoryMock struct {
+	mock.Mock
+}
+
+func NewLoginAttemptRepositoryMock() *LoginAttemptRepositoryMock {
+	return &LoginAttemptRepositoryMock{}
+}
+
+func (m *LoginAttemptRepositoryMock) Get(ctx context.Context, scope string, key string) (*models.LoginAttempt, error) {
+	args := m.Mock.Called(ctx, scope, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttempt), args.Error(1)
+}
+
+// Update applies fn to the attempt given as the first return value, or to a
+// new one when it is nil, like the real repository would.
+func (m *LoginAttemptRepositoryMock) Update(ctx context.Context, scope string, key string, fn func(attempt *models.LoginAttempt)) (*models.LoginAttempt, error) {
+	args := m.Mock.Called(ctx, scope, key, fn)
+	if err := args.Error(1); err != nil {
+		return nil, err
+	}
+	attempt := &models.LoginAttempt{Scope: scope, Key: key}
+	if stored, ok := args.Get(0).(*models.LoginAttempt); ok && stored != nil {
+		copied := *stored
+		attempt = &copied
+	}
+	fn(attempt)
+	return attempt, nil
+}
+
+func (m *LoginAttemptRepositoryMock) Delete(ctx context.Context, scope string, key string) error {
+	args := m.Mock.Called(ctx, scope, key)
+	return args.Error(0)
+}
+
+func (m *LoginAttemptRepositoryMock) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Mock.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var loginAttemptColumns = []string{"scope", "key", "failures", "lockouts", "locked_until", "last_failed_at"}
+
+func TestLoginAttemptRepository_Get(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name            string
+		mockSetup       func(sqlmock.Sqlmock)
+		expectedAttempt *models.LoginAttempt
+		expectedError   error
+	}{
+		{
+			name: "locked account",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM login_attempts WHERE scope = \? AND key = \?`).
+					WithArgs("user", "alice").
+					WillReturnRows(sqlmock.NewRows(loginAttemptColumns).AddRow("user", "alice", 0, 1, testTime, testTime))
+			},
+			expectedAttempt: &models.LoginAttempt{Scope: "user", Key: "alice", Lockouts: 1, LockedUntil: &testTime, LastFailedAt: testTime},
+		},
+		{
+			name: "no failures",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM login_attempts").
+					WithArgs("user", "alice").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			attempt, err := repo.Get(context.Background(), "user", "alice")
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedAttempt, attempt)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLoginAttemptRepository_Update(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db)
+	lastFailedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	lockedUntil := now.Add(time.Minute)
+
+	testCaseList := []struct {
+		name            string
+		mockSetup       func(sqlmock.Sqlmock)
+		fn              func(attempt *models.LoginAttempt)
+		expectedAttempt *models.LoginAttempt
+		expectedError   error
+	}{
+		{
+			name: "first failure",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM login_attempts").
+					WithArgs("ip", "10.0.0.1").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec("INSERT INTO login_attempts (.+) ON CONFLICT").
+					WithArgs("ip", "10.0.0.1", 1, 0, nil, "2024-01-01 01:00:00").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(attempt *models.LoginAttempt) {
+				attempt.Failures++
+				attempt.LastFailedAt = now
+			},
+			expectedAttempt: &models.LoginAttempt{Scope: "ip", Key: "10.0.0.1", Failures: 1, LastFailedAt: now},
+		},
+		{
+			name: "lockout",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM login_attempts").
+					WithArgs("ip", "10.0.0.1").
+					WillReturnRows(sqlmock.NewRows(loginAttemptColumns).AddRow("ip", "10.0.0.1", 4, 0, nil, lastFailedAt))
+				mock.ExpectExec("INSERT INTO login_attempts").
+					WithArgs("ip", "10.0.0.1", 0, 1, "2024-01-01 01:01:00", "2024-01-01 01:00:00").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(attempt *models.LoginAttempt) {
+				attempt.Failures = 0
+				attempt.Lockouts++
+				attempt.LockedUntil = &lockedUntil
+				attempt.LastFailedAt = now
+			},
+			expectedAttempt: &models.LoginAttempt{Scope: "ip", Key: "10.0.0.1", Lockouts: 1, LockedUntil: &lockedUntil, LastFailedAt: now},
+		},
+		{
+			name: "read error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM login_attempts").
+					WithArgs("ip", "10.0.0.1").
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			fn:            func(attempt *models.LoginAttempt) {},
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			attempt, err := repo.Update(context.Background(), "ip", "10.0.0.1", testCase.fn)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedAttempt, attempt)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLoginAttemptRepository_Delete(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db)
+
+	mock.ExpectExec(`DELETE FROM login_attempts WHERE scope = \? AND key = \?`).
+		WithArgs("user", "alice").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Delete(context.Background(), "user", "alice")
+
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptRepository_DeleteStale(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db)
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("UTC+7", 7*60*60))
+
+	mock.ExpectExec(`DELETE FROM login_attempts WHERE last_failed_at < \? AND \(locked_until IS NULL OR locked_until < \?\)`).
+		WithArgs("2023-12-31 17:00:00", "2023-12-31 17:00:00").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := repo.DeleteStale(context.Background(), before)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"time"
+)
+
+type MFARepository interface {
+	GetTOTP(ctx context.Context, userID string) (*models.UserTOTP, error)
+	SaveTOTP(ctx context.Context, userTOTP *models.UserTOTP) error
+	Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error
+	UseStep(ctx context.Context, userID string, step int64) error
+	UseRecoveryCode(ctx context.Context, userID string, codeHash string) error
+	Disable(ctx context.Context, userID string) error
+	CreateChallenge(ctx context.Context, challenge *models.MFAChallenge) error
+	GetChallengeByHash(ctx context.Context, tokenHash string) (*models.MFAChallenge, error)
+	RecordChallengeAttempt(ctx context.Context, id int64, maxAttempts int) error
+	UseChallenge(ctx context.Context, id int64, maxAttempts int) error
+	DeleteExpiredChallenges(ctx context.Context, before time.Time) (int64, error)
+}
+
+type mfaRepository struct {
+	db *sql.DB
+}
+
+func NewMFARepository(db *sql.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+func (r *mfaRepository) GetTOTP(ctx context.Context, userID string) (*models.UserTOTP, error) {
+	query := `
+		SELECT user_id, secret, confirmed_at, last_used_step, created_at FROM user_totp
+		WHERE user_id = ?
+	`
+	var userTOTP models.UserTOTP
+	var confirmedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&userTOTP.UserID,
+		&userTOTP.Secret,
+		&confirmedAt,
+		&userTOTP.LastUsedStep,
+		&userTOTP.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if confirmedAt.Valid {
+		userTOTP.ConfirmedAt = &confirmedAt.Time
+	}
+	return &userTOTP, nil
+}
+
+// SaveTOTP stores a new unconfirmed secret, replacing an earlier unconfirmed
+// one. It returns sql.ErrNoRows when the user already confirmed a secret.
+func (r *mfaRepository) SaveTOTP(ctx context.Context, userTOTP *models.UserTOTP) error {
+	query := `
+		INSERT INTO user_totp (user_id, secret) VALUES (?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret = excluded.secret,
+			last_used_step = 0,
+			created_at = CURRENT_TIMESTAMP
+		WHERE confirmed_at IS NULL
+	`
+	return execAffectingRow(ctx, r.db, query, userTOTP.UserID, userTOTP.Secret)
+}
+
+// Enable confirms the secret, marks step as used and replaces the user's
+// recovery codes in one transaction. It returns sql.ErrNoRows when there is
+// no unconfirmed secret.
+func (r *mfaRepository) Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE user_totp SET confirmed_at = CURRENT_TIMESTAMP, last_used_step = ?
+		WHERE user_id = ? AND confirmed_at IS NULL
+	`
+	result, err := tx.ExecContext(ctx, query, step, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM mfa_recovery_codes WHERE user_id = ?", userID); err != nil {
+		return err
+	}
+	for _, codeHash := range recoveryCodeHashes {
+		_, err := tx.ExecContext(ctx, "INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES (?, ?)", userID, codeHash)
+		if err != nil {
+			return mapError(err)
+		}
+	}
+	return tx.Commit()
+}
+
+// UseStep records the time step of an accepted code. It returns
+// sql.ErrNoRows when a code of this or a later step was already used.
+func (r *mfaRepository) UseStep(ctx context.Context, userID string, step int64) error {
+	query := `
+		UPDATE user_totp SET last_used_step = ?
+		WHERE user_id = ? AND confirmed_at IS NOT NULL AND last_used_step < ?
+	`
+	return execAffectingRow(ctx, r.db, query, step, userID, step)
+}
+
+// UseRecoveryCode marks the code as used and returns sql.ErrNoRows when the
+// user has no such unused code.
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) error {
+	query := `
+		UPDATE mfa_recovery_codes SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL
+	`
+	return execAffectingRow(ctx, r.db, query, userID, codeHash)
+}
+
+// Disable removes the user's secret, recovery codes and pending challenges.
+func (r *mfaRepository) Disable(ctx context.Context, userID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		"DELETE FROM mfa_challenges WHERE user_id = ?",
+		"DELETE FROM mfa_recovery_codes WHERE user_id = ?",
+		"DELETE FROM user_totp WHERE user_id = ?",
+	} {
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *mfaRepository) CreateChallenge(ctx context.Context, challenge *models.MFAChallenge) error {
+	query := `
+		INSERT INTO mfa_challenges (user_id, token_hash, expires_at)
+		VALUES (?, ?, ?)
+	`
+	expiresAt := challenge.ExpiresAt.UTC().Format(sqliteTimeFormat)
+	result, err := r.db.ExecContext(ctx, query, challenge.UserID, challenge.TokenHash, expiresAt)
+	if err != nil {
+		return mapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	challenge.ID = id
+	return nil
+}
+
+func (r *mfaRepository) GetChallengeByHash(ctx context.Context, tokenHash string) (*models.MFAChallenge, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, attempts, used_at, created_at FROM mfa_challenges
+		WHERE token_hash = ?
+	`
+	var challenge models.MFAChallenge
+	var usedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.TokenHash,
+		&challenge.ExpiresAt,
+		&challenge.Attempts,
+		&usedAt,
+		&challenge.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		challenge.UsedAt = &usedAt.Time
+	}
+	return &challenge, nil
+}
+
+// RecordChallengeAttempt counts an attempt at the challenge before its code
+// is checked, and returns sql.ErrNoRows when the challenge was used or had
+// maxAttempts already, so parallel requests can't get more guesses.
+func (r *mfaRepository) RecordChallengeAttempt(ctx context.Context, id int64, maxAttempts int) error {
+	query := "UPDATE mfa_challenges SET attempts = attempts + 1 WHERE id = ? AND attempts < ? AND used_at IS NULL"
+	return execAffectingRow(ctx, r.db, query, id, maxAttempts)
+}
+
+// UseChallenge returns sql.ErrNoRows when the challenge was already used or
+// had more than maxAttempts, counting the attempt that completes it.
+func (r *mfaRepository) UseChallenge(ctx context.Context, id int64, maxAttempts int) error {
+	query := "UPDATE mfa_challenges SET used_at = CURRENT_TIMESTAMP WHERE id = ? AND attempts <= ? AND used_at IS NULL"
+	return execAffectingRow(ctx, r.db, query, id, maxAttempts)
+}
+
+func (r *mfaRepository) DeleteExpiredChallenges(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM mfa_challenges WHERE expires_at < ?", before.UTC().Format(sqliteTimeFormat))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
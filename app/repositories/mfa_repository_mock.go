@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"golang-template/app/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MFARepositoryMock struct {
+	mock.Mock
+}
+
+func NewMFARepositoryMock() *MFARepositoryMock {
+	return &MFARepositoryMock{}
+}
+
+func (m *MFARepositoryMock) GetTOTP(ctx context.Context, userID string) (*models.UserTOTP, error) {
+	args := m.Mock.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserTOTP), args.Error(1)
+}
+
+func (m *MFARepositoryMock) SaveTOTP(ctx context.Context, userTOTP *models.UserTOTP) error {
+	args := m.Mock.Called(ctx, userTOTP)
+	return args.Error(0)
+}
+
+func (m *MFARepositoryMock) Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error {
+	args := m.Mock.Called(ctx, userID, step, recoveryCodeHashes)
+	return args.Error(0)
+}
+
+func (m *MFARepositoryMock) UseStep(ctx context.Context, userID string, step int64) error {
+	args := m.Mock.Called(ctx, userID, step)
+	return args.Error(0)
+}
+
+func (m *MFARepositoryMock) UseRecoveryCode(ctx context.Context, userID string, codeHash string) error {
+	args := m.Mock.Called(ctx, userID, codeHash)
+	return args.Error(0)
+}
+
+func (m *MFARepositoryMock) Disable(ctx context.Context, userID string) error {
+	args := m.Mock.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MFARepositoryMock) CreateChallenge(ctx context.Context, challenge *models.MFAChallenge) error {
+	args := m.Mock.Called(ctx, challenge)
+	return args.Error(0)
+}
+
+func (m *MFARepositoryMock) GetChallengeByHash(ctx context.Context, tokenHash string) (*models.MFAChallenge, error) {
+	args := m.Mock.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MFAChallenge), args.Error(1)
+}
+
+func (m *MFARepositoryMock) RecordChallengeAttempt(ctx context.Context, id int64, maxAttempts int) error {
+	args := m.Mock.Called(ctx, id, maxAttempts)
+	return args.Error(0)
+}
+
+func (m *MFARepositoryMock) UseChallenge(ctx context.Context, id int64, maxAttempts int) error {
+	args := m.Mock.Called(ctx, id, maxAttempts)
+	return args.Error(0)
+}
+
+func (m *MFARepositoryMock) DeleteExpiredChallenges(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Mock.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
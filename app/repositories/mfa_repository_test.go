@@ -0,0 +1,280 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFARepository_GetTOTP(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMFARepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"user_id", "secret", "confirmed_at", "last_used_step", "created_at"}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedTOTP  *models.UserTOTP
+		expectedError error
+	}{
+		{
+			name: "confirmed secret",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM user_totp WHERE user_id = \?`).
+					WithArgs(testUserID).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(testUserID, "SECRET", testTime, 42, testTime))
+			},
+			expectedTOTP: &models.UserTOTP{UserID: testUserID, Secret: "SECRET", ConfirmedAt: &testTime, LastUsedStep: 42, CreatedAt: testTime},
+		},
+		{
+			name: "not enrolled",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM user_totp").
+					WithArgs(testUserID).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			userTOTP, err := repo.GetTOTP(context.Background(), testUserID)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedTOTP, userTOTP)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFARepository_SaveTOTP(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMFARepository(db)
+
+	testCaseList := []struct {
+		name          string
+		rowsAffected  int64
+		expectedError error
+	}{
+		{name: "new or unconfirmed secret", rowsAffected: 1},
+		{name: "already confirmed", rowsAffected: 0, expectedError: sql.ErrNoRows},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			mock.ExpectExec(`INSERT INTO user_totp (.+) ON CONFLICT \(user_id\) DO UPDATE SET (.+) WHERE confirmed_at IS NULL`).
+				WithArgs(testUserID, "SECRET").
+				WillReturnResult(sqlmock.NewResult(0, testCase.rowsAffected))
+
+			err := repo.SaveTOTP(context.Background(), &models.UserTOTP{UserID: testUserID, Secret: "SECRET"})
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFARepository_Enable(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMFARepository(db)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "confirms and stores the recovery codes",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE user_totp SET confirmed_at = CURRENT_TIMESTAMP, last_used_step = \? WHERE user_id = \? AND confirmed_at IS NULL`).
+					WithArgs(int64(42), testUserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM mfa_recovery_codes WHERE user_id = \?`).
+					WithArgs(testUserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO mfa_recovery_codes`).
+					WithArgs(testUserID, "hash-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO mfa_recovery_codes`).
+					WithArgs(testUserID, "hash-2").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "no pending secret",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE user_totp`).
+					WithArgs(int64(42), testUserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.Enable(context.Background(), testUserID, 42, []string{"hash-1", "hash-2"})
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFARepository_UseStep(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMFARepository(db)
+
+	testCaseList := []struct {
+		name          string
+		rowsAffected  int64
+		expectedError error
+	}{
+		{name: "new step", rowsAffected: 1},
+		{name: "replayed step", rowsAffected: 0, expectedError: sql.ErrNoRows},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			mock.ExpectExec(`UPDATE user_totp SET last_used_step = \? WHERE user_id = \? AND confirmed_at IS NOT NULL AND last_used_step < \?`).
+				WithArgs(int64(42), testUserID, int64(42)).
+				WillReturnResult(sqlmock.NewResult(0, testCase.rowsAffected))
+
+			err := repo.UseStep(context.Background(), testUserID, 42)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFARepository_UseRecoveryCode(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMFARepository(db)
+
+	mock.ExpectExec(`UPDATE mfa_recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE user_id = \? AND code_hash = \? AND used_at IS NULL`).
+		WithArgs(testUserID, "hash").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.UseRecoveryCode(context.Background(), testUserID, "hash")
+
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepository_Disable(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMFARepository(db)
+
+	mock.ExpectBegin()
+	for _, table := range []string{"mfa_challenges", "mfa_recovery_codes", "user_totp"} {
+		mock.ExpectExec(`DELETE FROM ` + table + ` WHERE user_id = \?`).
+			WithArgs(testUserID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	err := repo.Disable(context.Background(), testUserID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepository_CreateChallenge(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMFARepository(db)
+	challenge := &models.MFAChallenge{UserID: testUserID, TokenHash: "hash", ExpiresAt: time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC)}
+
+	mock.ExpectExec("INSERT INTO mfa_challenges").
+		WithArgs(testUserID, "hash", "2024-01-01 00:05:00").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	err := repo.CreateChallenge(context.Background(), challenge)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), challenge.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepository_GetChallengeByHash(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMFARepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "token_hash", "expires_at", "attempts", "used_at", "created_at"}
+
+	mock.ExpectQuery(`SELECT (.+) FROM mfa_challenges WHERE token_hash = \?`).
+		WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(7, testUserID, "hash", testTime, 2, nil, testTime))
+
+	challenge, err := repo.GetChallengeByHash(context.Background(), "hash")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.MFAChallenge{ID: 7, UserID: testUserID, TokenHash: "hash", ExpiresAt: testTime, Attempts: 2, CreatedAt: testTime}, challenge)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepository_UseChallenge(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMFARepository(db)
+
+	mock.ExpectExec(`UPDATE mfa_challenges SET attempts = attempts \+ 1 WHERE id = \? AND attempts < \? AND used_at IS NULL`).
+		WithArgs(int64(7), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE mfa_challenges SET attempts = attempts \+ 1 WHERE id = \? AND attempts < \? AND used_at IS NULL`).
+		WithArgs(int64(7), 5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE mfa_challenges SET used_at = CURRENT_TIMESTAMP WHERE id = \? AND attempts <= \? AND used_at IS NULL`).
+		WithArgs(int64(7), 5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.RecordChallengeAttempt(context.Background(), 7, 5))
+	assert.Equal(t, sql.ErrNoRows, repo.RecordChallengeAttempt(context.Background(), 7, 5))
+	assert.Equal(t, sql.ErrNoRows, repo.UseChallenge(context.Background(), 7, 5))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepository_DeleteExpiredChallenges(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMFARepository(db)
+
+	mock.ExpectExec(`DELETE FROM mfa_challenges WHERE expires_at < \?`).
+		WithArgs("2024-01-01 00:00:00").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := repo.DeleteExpiredChallenges(context.Background(), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
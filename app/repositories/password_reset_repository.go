@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, resetToken *models.PasswordResetToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	Reset(ctx context.Context, resetToken *models.PasswordResetToken, passwordHash string) error
+}
+
+type passwordResetRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, resetToken *models.PasswordResetToken) error {
+	query := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+		VALUES (?, ?, ?)
+	`
+	result, err := r.db.ExecContext(ctx, query, resetToken.UserID, resetToken.TokenHash, resetToken.ExpiresAt)
+	if err != nil {
+		return mapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	resetToken.ID = id
+	return nil
+}
+
+func (r *passwordResetRepository) GetByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at FROM password_reset_tokens
+		WHERE token_hash = ?
+	`
+	var resetToken models.PasswordResetToken
+	var usedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&resetToken.ID,
+		&resetToken.UserID,
+		&resetToken.TokenHash,
+		&resetToken.ExpiresAt,
+		&usedAt,
+		&resetToken.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		resetToken.UsedAt = &usedAt.Time
+	}
+	return &resetToken, nil
+}
+
+// Reset uses up the token, and every other pending token of the user, sets
+// the new password and revokes all of the user's refresh tokens in one
+// transaction. It returns sql.ErrNoRows when the token was already used or
+// the user is gone. The mailed token proves the user owns the address, so an
+// unverified user becomes verified as well.
+func (r *passwordResetRepository) Reset(ctx context.Context, resetToken *models.PasswordResetToken, passwordHash string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range []struct {
+		query string
+		args  []any
+	}{
+		{"UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = ? AND used_at IS NULL", []any{resetToken.ID}},
+		{`UPDATE users SET password = ?, verified_at = COALESCE(verified_at, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL`, []any{passwordHash, resetToken.UserID}},
+	} {
+		result, err := tx.ExecContext(ctx, statement.query, statement.args...)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return sql.ErrNoRows
+		}
+	}
+
+	for _, query := range []string{
+		"UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = ? AND used_at IS NULL",
+		"UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND revoked_at IS NULL",
+	} {
+		if _, err := tx.ExecContext(ctx, query, resetToken.UserID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PasswordResetRepositoryMock struct {
+	mock.Mock
+}
+
+func NewPasswordResetRepositoryMock() *PasswordResetRepositoryMock {
+	return &PasswordResetRepositoryMock{}
+}
+
+func (m *PasswordResetRepositoryMock) Create(ctx context.Context, resetToken *models.PasswordResetToken) error {
+	args := m.Mock.Called(ctx, resetToken)
+	return args.Error(0)
+}
+
+func (m *PasswordResetRepositoryMock) GetByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	args := m.Mock.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PasswordResetToken), args.Error(1)
+}
+
+func (m *PasswordResetRepositoryMock) Reset(ctx context.Context, resetToken *models.PasswordResetToken, passwordHash string) error {
+	args := m.Mock.Called(ctx, resetToken, passwordHash)
+	return args.Error(0)
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetRepository_Create(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPasswordResetRepository(db)
+	expiresAt := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	resetToken := &models.PasswordResetToken{UserID: testUserID, TokenHash: "hash", ExpiresAt: expiresAt}
+
+	mock.ExpectExec("INSERT INTO password_reset_tokens").
+		WithArgs(testUserID, "hash", expiresAt).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+
+	err := repo.Create(context.Background(), resetToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), resetToken.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPasswordResetRepository_GetByHash(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPasswordResetRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "token_hash", "expires_at", "used_at", "created_at"}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedToken *models.PasswordResetToken
+		expectedError error
+	}{
+		{
+			name: "used token",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM password_reset_tokens WHERE token_hash = ?").
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, testUserID, "hash", testTime, testTime, testTime))
+			},
+			expectedToken: &models.PasswordResetToken{ID: 1, UserID: testUserID, TokenHash: "hash", ExpiresAt: testTime, UsedAt: &testTime, CreatedAt: testTime},
+		},
+		{
+			name: "unknown token",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM password_reset_tokens").
+					WithArgs("hash").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			resetToken, err := repo.GetByHash(context.Background(), "hash")
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedToken, resetToken)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPasswordResetRepository_Reset(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPasswordResetRepository(db)
+	resetToken := &models.PasswordResetToken{ID: 4, UserID: testUserID}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "sets the password and revokes the sessions",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = \? AND used_at IS NULL`).
+					WithArgs(int64(4)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE users SET password = \?, verified_at = COALESCE\(verified_at, CURRENT_TIMESTAMP\)`).
+					WithArgs("new-hash", testUserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = \? AND used_at IS NULL`).
+					WithArgs(testUserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = \? AND revoked_at IS NULL`).
+					WithArgs(testUserID).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "token already used",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE password_reset_tokens`).
+					WithArgs(int64(4)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedError: sql.ErrNoRows,
+		},
+		{
+			name: "deleted user",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE password_reset_tokens`).
+					WithArgs(int64(4)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE users`).
+					WithArgs("new-hash", testUserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.Reset(context.Background(), resetToken, "new-hash")
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+)
+
+type PermissionRepository interface {
+	Create(ctx context.Context, permission *models.PermissionCreate) (*models.Permission, error)
+	GetByID(ctx context.Context, id int64) (*models.Permission, error)
+	GetByName(ctx context.Context, name string) (*models.Permission, error)
+	List(ctx context.Context) (*[]models.Permission, error)
+	Update(ctx context.Context, id int64, permission *models.PermissionUpdate) (*models.Permission, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type permissionRepository struct {
+	db *sql.DB
+}
+
+func NewPermissionRepository(db *sql.DB) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+func (r *permissionRepository) Create(ctx context.Context, permission *models.PermissionCreate) (*models.Permission, error) {
+	query := `
+		INSERT INTO permissions (name, description, resource, action)
+		VALUES (?, ?, ?, ?)
+	`
+	name := models.PermissionName(permission.Resource, permission.Action)
+	result, err := r.db.ExecContext(ctx, query, name, permission.Description, permission.Resource, permission.Action)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *permissionRepository) GetByID(ctx context.Context, id int64) (*models.Permission, error) {
+	query := `
+		SELECT id, name, description, resource, action, created_at, updated_at FROM permissions
+		WHERE id = ?
+	`
+	return scanPermission(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *permissionRepository) GetByName(ctx context.Context, name string) (*models.Permission, error) {
+	query := `
+		SELECT id, name, description, resource, action, created_at, updated_at FROM permissions
+		WHERE name = ?
+	`
+	return scanPermission(r.db.QueryRowContext(ctx, query, name))
+}
+
+func (r *permissionRepository) List(ctx context.Context) (*[]models.Permission, error) {
+	query := `
+		SELECT id, name, description, resource, action, created_at, updated_at FROM permissions
+		ORDER BY resource, action
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPermissions(rows)
+}
+
+func (r *permissionRepository) Update(ctx context.Context, id int64, permission *models.PermissionUpdate) (*models.Permission, error) {
+	query := `
+		UPDATE permissions SET description = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+	result, err := r.db.ExecContext(ctx, query, permission.Description, id)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *permissionRepository) Delete(ctx context.Context, id int64) error {
+	query := `
+		DELETE FROM permissions WHERE id = ?
+	`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanPermission(row *sql.Row) (*models.Permission, error) {
+	var permission models.Permission
+	err := row.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.Resource, &permission.Action, &permission.CreatedAt, &permission.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func scanPermissions(rows *sql.Rows) (*[]models.Permission, error) {
+	permissions := []models.Permission{}
+	for rows.Next() {
+		var permission models.Permission
+		err := rows.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.Resource, &permission.Action, &permission.CreatedAt, &permission.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	return &permissions, nil
+}
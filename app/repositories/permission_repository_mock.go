@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PermissionRepositoryMock struct {
+	mock.Mock
+}
+
+func NewPermissionRepositoryMock() *PermissionRepositoryMock {
+	return &PermissionRepositoryMock{}
+}
+
+func (m *PermissionRepositoryMock) Create(ctx context.Context, permission *models.PermissionCreate) (*models.Permission, error) {
+	args := m.Mock.Called(ctx, permission)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Permission), args.Error(1)
+}
+
+func (m *PermissionRepositoryMock) GetByID(ctx context.Context, id int64) (*models.Permission, error) {
+	args := m.Mock.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Permission), args.Error(1)
+}
+
+func (m *PermissionRepositoryMock) GetByName(ctx context.Context, name string) (*models.Permission, error) {
+	args := m.Mock.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Permission), args.Error(1)
+}
+
+func (m *PermissionRepositoryMock) List(ctx context.Context) (*[]models.Permission, error) {
+	args := m.Mock.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*[]models.Permission), args.Error(1)
+}
+
+func (m *PermissionRepositoryMock) Update(ctx context.Context, id int64, permission *models.PermissionUpdate) (*models.Permission, error) {
+	args := m.Mock.Called(ctx, id, permission)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Permission), args.Error(1)
+}
+
+func (m *PermissionRepositoryMock) Delete(ctx context.Context, id int64) error {
+	args := m.Mock.Called(ctx, id)
+	return args.Error(0)
+}
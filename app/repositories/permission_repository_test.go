@@ -0,0 +1,264 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var permissionColumns = []string{"id", "name", "description", "resource", "action", "created_at", "updated_at"}
+
+func TestPermissionRepository_Create(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPermissionRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name               string
+		permission         *models.PermissionCreate
+		mockSetup          func(sqlmock.Sqlmock)
+		expectedPermission *models.Permission
+		expectedError      error
+	}{
+		{
+			name:       "successful creation",
+			permission: &models.PermissionCreate{Resource: "user", Action: "write", Description: "Manage users"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO permissions").
+					WithArgs("user:write", "Manage users", "user", "write").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				rows := sqlmock.NewRows(permissionColumns).AddRow(1, "user:write", "Manage users", "user", "write", testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM permissions WHERE id = (.+)").
+					WithArgs(1).
+					WillReturnRows(rows)
+			},
+			expectedPermission: &models.Permission{
+				ID: 1, Name: "user:write", Description: "Manage users", Resource: "user", Action: "write", CreatedAt: testTime, UpdatedAt: testTime,
+			},
+			expectedError: nil,
+		},
+		{
+			name:       "database error",
+			permission: &models.PermissionCreate{Resource: "user", Action: "write", Description: "Manage users"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO permissions").
+					WithArgs("user:write", "Manage users", "user", "write").
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedPermission: nil,
+			expectedError:      sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			permission, err := repo.Create(context.Background(), testCase.permission)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPermission, permission)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPermissionRepository_GetByID(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPermissionRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name               string
+		mockSetup          func(sqlmock.Sqlmock)
+		expectedPermission *models.Permission
+		expectedError      error
+	}{
+		{
+			name: "permission found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(permissionColumns).AddRow(1, "user:read", "", "user", "read", testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM permissions WHERE id = (.+)").
+					WithArgs(1).
+					WillReturnRows(rows)
+			},
+			expectedPermission: &models.Permission{
+				ID: 1, Name: "user:read", Resource: "user", Action: "read", CreatedAt: testTime, UpdatedAt: testTime,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "permission not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM permissions WHERE id = (.+)").
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedPermission: nil,
+			expectedError:      sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			permission, err := repo.GetByID(context.Background(), 1)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPermission, permission)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPermissionRepository_List(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPermissionRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name                string
+		mockSetup           func(sqlmock.Sqlmock)
+		expectedPermissions *[]models.Permission
+		expectedError       error
+	}{
+		{
+			name: "successful list",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(permissionColumns).AddRow(1, "user:read", "", "user", "read", testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM permissions").WillReturnRows(rows)
+			},
+			expectedPermissions: &[]models.Permission{
+				{ID: 1, Name: "user:read", Resource: "user", Action: "read", CreatedAt: testTime, UpdatedAt: testTime},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "empty list",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM permissions").WillReturnRows(sqlmock.NewRows(permissionColumns))
+			},
+			expectedPermissions: &[]models.Permission{},
+			expectedError:       nil,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM permissions").WillReturnError(sql.ErrConnDone)
+			},
+			expectedPermissions: nil,
+			expectedError:       sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			permissions, err := repo.List(context.Background())
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPermissions, permissions)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPermissionRepository_Update(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPermissionRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name               string
+		mockSetup          func(sqlmock.Sqlmock)
+		expectedPermission *models.Permission
+		expectedError      error
+	}{
+		{
+			name: "successful update",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE permissions").
+					WithArgs("Read users", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				rows := sqlmock.NewRows(permissionColumns).AddRow(1, "user:read", "Read users", "user", "read", testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM permissions WHERE id = (.+)").
+					WithArgs(1).
+					WillReturnRows(rows)
+			},
+			expectedPermission: &models.Permission{
+				ID: 1, Name: "user:read", Description: "Read users", Resource: "user", Action: "read", CreatedAt: testTime, UpdatedAt: testTime,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "permission not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE permissions").
+					WithArgs("Read users", 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedPermission: nil,
+			expectedError:      sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			permission, err := repo.Update(context.Background(), 1, &models.PermissionUpdate{Description: "Read users"})
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPermission, permission)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPermissionRepository_Delete(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPermissionRepository(db)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "successful delete",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM permissions (.+)").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedError: nil,
+		},
+		{
+			name: "permission not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM permissions (.+)").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.Delete(context.Background(), 1)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
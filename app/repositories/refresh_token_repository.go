@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, refreshToken *models.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	Revoke(ctx context.Context, id int64) error
+	RevokeFamily(ctx context.Context, familyID string) error
+}
+
+type refreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, refreshToken *models.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		VALUES (?, ?, ?, ?)
+	`
+	result, err := r.db.ExecContext(ctx, query, refreshToken.UserID, refreshToken.FamilyID, refreshToken.TokenHash, refreshToken.ExpiresAt)
+	if err != nil {
+		return mapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	refreshToken.ID = id
+	return nil
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, created_at FROM refresh_tokens
+		WHERE token_hash = ?
+	`
+	var refreshToken models.RefreshToken
+	var revokedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&refreshToken.ID,
+		&refreshToken.UserID,
+		&refreshToken.FamilyID,
+		&refreshToken.TokenHash,
+		&refreshToken.ExpiresAt,
+		&revokedAt,
+		&refreshToken.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		refreshToken.RevokedAt = &revokedAt.Time
+	}
+	return &refreshToken, nil
+}
+
+// Revoke returns sql.ErrNoRows when the token was already revoked, which lets
+// callers detect two concurrent refreshes of the same token.
+func (r *refreshTokenRepository) Revoke(ctx context.Context, id int64) error {
+	query := `
+		UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND revoked_at IS NULL
+	`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	query := `
+		UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE family_id = ? AND revoked_at IS NULL
+	`
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	return err
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type RefreshTokenRepositoryMock struct {
+	mock.Mock
+}
+
+func NewRefreshTokenRepositoryMock() *RefreshTokenRepositoryMock {
+	return &RefreshTokenRepositoryMock{}
+}
+
+func (m *RefreshTokenRepositoryMock) Create(ctx context.Context, refreshToken *models.RefreshToken) error {
+	args := m.Mock.Called(ctx, refreshToken)
+	return args.Error(0)
+}
+
+func (m *RefreshTokenRepositoryMock) GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	args := m.Mock.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+
+func (m *RefreshTokenRepositoryMock) Revoke(ctx context.Context, id int64) error {
+	args := m.Mock.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *RefreshTokenRepositoryMock) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Mock.Called(ctx, familyID)
+	return args.Error(0)
+}
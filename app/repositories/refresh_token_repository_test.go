@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenRepository_Create(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRefreshTokenRepository(db)
+	expiresAt := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name          string
+		refreshToken  *models.RefreshToken
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedID    int64
+		expectedError error
+	}{
+		{
+			name:         "successful creation",
+			refreshToken: &models.RefreshToken{UserID: testUserID, FamilyID: "family", TokenHash: "hash", ExpiresAt: expiresAt},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO refresh_tokens").
+					WithArgs(testUserID, "family", "hash", expiresAt).
+					WillReturnResult(sqlmock.NewResult(5, 1))
+			},
+			expectedID:    5,
+			expectedError: nil,
+		},
+		{
+			name:         "database error",
+			refreshToken: &models.RefreshToken{UserID: testUserID, FamilyID: "family", TokenHash: "hash", ExpiresAt: expiresAt},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO refresh_tokens").
+					WithArgs(testUserID, "family", "hash", expiresAt).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedID:    0,
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.Create(context.Background(), testCase.refreshToken)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedID, testCase.refreshToken.ID)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenRepository_GetByHash(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRefreshTokenRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "family_id", "token_hash", "expires_at", "revoked_at", "created_at"}
+
+	testCaseList := []struct {
+		name                 string
+		mockSetup            func(sqlmock.Sqlmock)
+		expectedRefreshToken *models.RefreshToken
+		expectedError        error
+	}{
+		{
+			name: "active token",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).AddRow(1, testUserID, "family", "hash", testTime, nil, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM refresh_tokens WHERE token_hash = (.+)").
+					WithArgs("hash").
+					WillReturnRows(rows)
+			},
+			expectedRefreshToken: &models.RefreshToken{
+				ID: 1, UserID: testUserID, FamilyID: "family", TokenHash: "hash", ExpiresAt: testTime, CreatedAt: testTime,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "revoked token",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).AddRow(1, testUserID, "family", "hash", testTime, testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM refresh_tokens WHERE token_hash = (.+)").
+					WithArgs("hash").
+					WillReturnRows(rows)
+			},
+			expectedRefreshToken: &models.RefreshToken{
+				ID: 1, UserID: testUserID, FamilyID: "family", TokenHash: "hash", ExpiresAt: testTime, RevokedAt: &testTime, CreatedAt: testTime,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "token not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM refresh_tokens WHERE token_hash = (.+)").
+					WithArgs("hash").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedRefreshToken: nil,
+			expectedError:        sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			refreshToken, err := repo.GetByHash(context.Background(), "hash")
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedRefreshToken, refreshToken)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenRepository_Revoke(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRefreshTokenRepository(db)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "successful revoke",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE refresh_tokens SET revoked_at").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedError: nil,
+		},
+		{
+			name: "already revoked",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE refresh_tokens SET revoked_at").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: sql.ErrNoRows,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE refresh_tokens SET revoked_at").
+					WithArgs(1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.Revoke(context.Background(), 1)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenRepository_RevokeFamily(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRefreshTokenRepository(db)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "successful revoke",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE refresh_tokens SET revoked_at (.+) WHERE family_id = (.+)").
+					WithArgs("family").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			expectedError: nil,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE refresh_tokens SET revoked_at (.+) WHERE family_id = (.+)").
+					WithArgs("family").
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.RevokeFamily(context.Background(), "family")
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
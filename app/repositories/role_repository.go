@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+)
+
+type RoleRepository interface {
+	Create(ctx context.Context, role *models.RoleCreate) (*models.Role, error)
+	GetByID(ctx context.Context, id int64) (*models.Role, error)
+	GetByName(ctx context.Context, name string) (*models.Role, error)
+	List(ctx context.Context) (*[]models.Role, error)
+	Update(ctx context.Context, id int64, role *models.RoleUpdate) (*models.Role, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type roleRepository struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) Create(ctx context.Context, role *models.RoleCreate) (*models.Role, error) {
+	query := `
+		INSERT INTO roles (name, description)
+		VALUES (?, ?)
+	`
+	result, err := r.db.ExecContext(ctx, query, role.Name, role.Description)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *roleRepository) GetByID(ctx context.Context, id int64) (*models.Role, error) {
+	query := `
+		SELECT id, name, description, created_at, updated_at FROM roles
+		WHERE id = ?
+	`
+	return scanRole(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *roleRepository) GetByName(ctx context.Context, name string) (*models.Role, error) {
+	query := `
+		SELECT id, name, description, created_at, updated_at FROM roles
+		WHERE name = ?
+	`
+	return scanRole(r.db.QueryRowContext(ctx, query, name))
+}
+
+func (r *roleRepository) List(ctx context.Context) (*[]models.Role, error) {
+	query := `
+		SELECT id, name, description, created_at, updated_at FROM roles
+		ORDER BY name
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRoles(rows)
+}
+
+func (r *roleRepository) Update(ctx context.Context, id int64, role *models.RoleUpdate) (*models.Role, error) {
+	query := `
+		UPDATE roles SET name = ?, description = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+	result, err := r.db.ExecContext(ctx, query, role.Name, role.Description, id)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *roleRepository) Delete(ctx context.Context, id int64) error {
+	query := `
+		DELETE FROM roles WHERE id = ?
+	`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanRole(row *sql.Row) (*models.Role, error) {
+	var role models.Role
+	err := row.Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func scanRoles(rows *sql.Rows) (*[]models.Role, error) {
+	roles := []models.Role{}
+	for rows.Next() {
+		var role models.Role
+		err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return &roles, nil
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type RoleRepositoryMock struct {
+	mock.Mock
+}
+
+func NewRoleRepositoryMock() *RoleRepositoryMock {
+	return &RoleRepositoryMock{}
+}
+
+func (m *RoleRepositoryMock) Create(ctx context.Context, role *models.RoleCreate) (*models.Role, error) {
+	args := m.Mock.Called(ctx, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *RoleRepositoryMock) GetByID(ctx context.Context, id int64) (*models.Role, error) {
+	args := m.Mock.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *RoleRepositoryMock) GetByName(ctx context.Context, name string) (*models.Role, error) {
+	args := m.Mock.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *RoleRepositoryMock) List(ctx context.Context) (*[]models.Role, error) {
+	args := m.Mock.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*[]models.Role), args.Error(1)
+}
+
+func (m *RoleRepositoryMock) Update(ctx context.Context, id int64, role *models.RoleUpdate) (*models.Role, error) {
+	args := m.Mock.Called(ctx, id, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *RoleRepositoryMock) Delete(ctx context.Context, id int64) error {
+	args := m.Mock.Called(ctx, id)
+	return args.Error(0)
+}
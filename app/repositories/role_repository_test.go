@@ -0,0 +1,275 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var roleColumns = []string{"id", "name", "description", "created_at", "updated_at"}
+
+func TestRoleRepository_Create(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRoleRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name          string
+		role          *models.RoleCreate
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedRole  *models.Role
+		expectedError error
+	}{
+		{
+			name: "successful creation",
+			role: &models.RoleCreate{Name: "admin", Description: "Administrator"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO roles").
+					WithArgs("admin", "Administrator").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				rows := sqlmock.NewRows(roleColumns).AddRow(1, "admin", "Administrator", testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM roles WHERE id = (.+)").
+					WithArgs(1).
+					WillReturnRows(rows)
+			},
+			expectedRole:  &models.Role{ID: 1, Name: "admin", Description: "Administrator", CreatedAt: testTime, UpdatedAt: testTime},
+			expectedError: nil,
+		},
+		{
+			name: "database error",
+			role: &models.RoleCreate{Name: "admin", Description: "Administrator"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO roles").
+					WithArgs("admin", "Administrator").
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedRole:  nil,
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			role, err := repo.Create(context.Background(), testCase.role)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedRole, role)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRoleRepository_GetByName(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRoleRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedRole  *models.Role
+		expectedError error
+	}{
+		{
+			name: "role found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(roleColumns).AddRow(1, "admin", "Administrator", testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM roles WHERE name = (.+)").
+					WithArgs("admin").
+					WillReturnRows(rows)
+			},
+			expectedRole:  &models.Role{ID: 1, Name: "admin", Description: "Administrator", CreatedAt: testTime, UpdatedAt: testTime},
+			expectedError: nil,
+		},
+		{
+			name: "role not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM roles WHERE name = (.+)").
+					WithArgs("admin").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedRole:  nil,
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			role, err := repo.GetByName(context.Background(), "admin")
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedRole, role)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRoleRepository_List(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRoleRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedRoles *[]models.Role
+		expectedError error
+	}{
+		{
+			name: "successful list",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(roleColumns).AddRow(1, "admin", "Administrator", testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM roles").WillReturnRows(rows)
+			},
+			expectedRoles: &[]models.Role{{ID: 1, Name: "admin", Description: "Administrator", CreatedAt: testTime, UpdatedAt: testTime}},
+			expectedError: nil,
+		},
+		{
+			name: "empty list",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM roles").WillReturnRows(sqlmock.NewRows(roleColumns))
+			},
+			expectedRoles: &[]models.Role{},
+			expectedError: nil,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM roles").WillReturnError(sql.ErrConnDone)
+			},
+			expectedRoles: nil,
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			roles, err := repo.List(context.Background())
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedRoles, roles)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRoleRepository_Update(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRoleRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedRole  *models.Role
+		expectedError error
+	}{
+		{
+			name: "successful update",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE roles").
+					WithArgs("editor", "Editor", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				rows := sqlmock.NewRows(roleColumns).AddRow(1, "editor", "Editor", testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM roles WHERE id = (.+)").
+					WithArgs(1).
+					WillReturnRows(rows)
+			},
+			expectedRole:  &models.Role{ID: 1, Name: "editor", Description: "Editor", CreatedAt: testTime, UpdatedAt: testTime},
+			expectedError: nil,
+		},
+		{
+			name: "role not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE roles").
+					WithArgs("editor", "Editor", 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedRole:  nil,
+			expectedError: sql.ErrNoRows,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE roles").
+					WithArgs("editor", "Editor", 1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedRole:  nil,
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			role, err := repo.Update(context.Background(), 1, &models.RoleUpdate{Name: "editor", Description: "Editor"})
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedRole, role)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRoleRepository_Delete(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRoleRepository(db)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "successful delete",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM roles (.+)").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedError: nil,
+		},
+		{
+			name: "role not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM roles (.+)").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: sql.ErrNoRows,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM roles (.+)").
+					WithArgs(1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.Delete(context.Background(), 1)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
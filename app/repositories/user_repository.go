@@ -1,14 +1,35 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"golang-template/app/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type UserRepository interface {
-	Create(user *models.UserRegister) error
-	Update(user *models.UserUpdatePassword) error
-	List() (*[]models.User, error)
+	Create(ctx context.Context, user *models.UserRegister) (*models.User, error)
+	UpdatePassword(ctx context.Context, id string, passwordHash string) error
+	List(ctx context.Context, query *models.UserListQuery) (*models.UserList, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	GetByID(ctx context.Context, id string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	Patch(ctx context.Context, id string, patch *models.UserPatch) (*models.User, error)
+	SoftDelete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+var userSortColumns = map[string]string{
+	"id":        "id",
+	"username":  "username",
+	"email":     "email",
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
 }
 
 type userRepository struct {
@@ -20,51 +41,270 @@ func NewUserRepository(db *sql.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user *models.UserRegister) error {
+func (r *userRepository) Create(ctx context.Context, user *models.UserRegister) (*models.User, error) {
+	// Version 7 UUIDs start with the creation time, so sorting by id still
+	// lists users in the order they registered.
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
 
 	query := `
-		INSERT INTO users (username, email, password)
-		VALUES (?, ?, ?)
+		INSERT INTO users (id, username, email, password)
+		VALUES (?, ?, ?, ?)
 	`
-	_, err := r.db.Exec(query, user.Username, user.Email, user.Password)
+	_, err = r.db.ExecContext(ctx, query, id.String(), user.Username, user.Email, user.Password)
 	if err != nil {
-		return err
+		return nil, mapError(err)
 	}
 
-	return nil
+	return r.GetByID(ctx, id.String())
 }
 
-func (r *userRepository) Update(user *models.UserUpdatePassword) error {
+func (r *userRepository) UpdatePassword(ctx context.Context, id string, passwordHash string) error {
 	query := `
-		UPDATE users SET username = ?, email = ?, password = ?
-		WHERE username = ?
+		UPDATE users SET password = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL
 	`
-	_, err := r.db.Exec(query, user.Username, user.NewPassword, user.Username)
-	if err != nil {
-		return err
-	}
-	return nil
+	return execAffectingRow(ctx, r.db, query, passwordHash, id)
 }
 
-func (r *userRepository) List() (*[]models.User, error) {
+func (r *userRepository) List(ctx context.Context, query *models.UserListQuery) (*models.UserList, error) {
+	sortField, descending := strings.CutPrefix(query.Sort, "-")
+	sortColumn, ok := userSortColumns[sortField]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort field %q", query.Sort)
+	}
 
-	query := `
-		SELECT username, email, created_at, updated_at FROM users
-	`
-	rows, err := r.db.Query(query)
+	conditions, args := userListFilters(query)
+	conditions = append(conditions, "deleted_at IS NULL")
+
+	var total int64
+	countQuery := "SELECT COUNT(*) FROM users" + whereClause(conditions)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	direction, operator := "ASC", ">"
+	if descending {
+		direction, operator = "DESC", "<"
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeCursor(query.Cursor)
+		if err != nil || cursor.Sort != query.Sort {
+			return nil, ErrInvalidCursor
+		}
+		if sortColumn == "id" {
+			conditions = append(conditions, "id "+operator+" ?")
+			args = append(args, cursor.ID)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", sortColumn, operator))
+			args = append(args, cursor.Value, cursor.Value, cursor.ID)
+		}
+	}
+
+	// id breaks ties so the order, and therefore the cursor, is stable.
+	orderBy := fmt.Sprintf("%s %s", sortColumn, direction)
+	if sortColumn != "id" {
+		orderBy += ", id " + direction
+	}
+
+	// One extra row tells us whether there is a next page.
+	listQuery := fmt.Sprintf(`
+		SELECT id, username, email, verified_at, created_at, updated_at FROM users%s
+		ORDER BY %s
+		LIMIT ?`, whereClause(conditions), orderBy)
+	args = append(args, query.PageSize+1)
+	if query.Cursor == "" {
+		listQuery += " OFFSET ?"
+		args = append(args, (query.Page-1)*query.PageSize)
+	}
+
+	rows, err := r.db.QueryContext(ctx, listQuery, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []models.User
+	users := []models.User{}
 	for rows.Next() {
 		var user models.User
-		err = rows.Scan(&user.Username, &user.Email, &user.CreatedAt, &user.UpdatedAt)
+		var verifiedAt sql.NullTime
+		err = rows.Scan(&user.ID, &user.Username, &user.Email, &verifiedAt, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
+		if verifiedAt.Valid {
+			user.VerifiedAt = &verifiedAt.Time
+		}
 		users = append(users, user)
 	}
-	return &users, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	list := &models.UserList{Users: users, Page: query.Page, PageSize: query.PageSize, Total: total}
+	if len(users) > query.PageSize {
+		list.Users = users[:query.PageSize]
+		last := list.Users[query.PageSize-1]
+		list.NextCursor = encodeCursor(cursor{Sort: query.Sort, Value: userSortValue(&last, sortField), ID: last.ID})
+	}
+	return list, nil
+}
+
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	query := `
+		SELECT id, username, email, password, verified_at, created_at, updated_at FROM users
+		WHERE username = ? AND deleted_at IS NULL
+	`
+	return scanUser(r.db.QueryRowContext(ctx, query, username))
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	query := `
+		SELECT id, username, email, password, verified_at, created_at, updated_at FROM users
+		WHERE id = ? AND deleted_at IS NULL
+	`
+	return scanUser(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `
+		SELECT id, username, email, password, verified_at, created_at, updated_at FROM users
+		WHERE email = ? AND deleted_at IS NULL
+	`
+	return scanUser(r.db.QueryRowContext(ctx, query, email))
+}
+
+func (r *userRepository) Patch(ctx context.Context, id string, patch *models.UserPatch) (*models.User, error) {
+	var assignments []string
+	var args []any
+	if patch.Username != nil {
+		assignments = append(assignments, "username = ?")
+		args = append(args, *patch.Username)
+	}
+	if patch.Email != nil {
+		// A new address has to be verified again.
+		assignments = append(assignments, "email = ?", "verified_at = CASE WHEN email = ? THEN verified_at END")
+		args = append(args, *patch.Email, *patch.Email)
+	}
+
+	if len(assignments) > 0 {
+		query := fmt.Sprintf(`
+		UPDATE users SET %s, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL`, strings.Join(assignments, ", "))
+		args = append(args, id)
+		if err := execAffectingRow(ctx, r.db, query, args...); err != nil {
+			return nil, mapError(err)
+		}
+	}
+	return r.GetByID(ctx, id)
+}
+
+// SoftDelete hides the user from every query until it is restored or purged.
+func (r *userRepository) SoftDelete(ctx context.Context, id string) error {
+	query := `
+		UPDATE users SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL
+	`
+	return execAffectingRow(ctx, r.db, query, id)
+}
+
+// Restore returns sql.ErrNoRows when the user doesn't exist or isn't deleted,
+// and ErrDuplicate when another user took the username or email meanwhile.
+func (r *userRepository) Restore(ctx context.Context, id string) error {
+	query := `
+		UPDATE users SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NOT NULL
+	`
+	return mapError(execAffectingRow(ctx, r.db, query, id))
+}
+
+// PurgeDeleted removes users deleted before deletedBefore together with
+// their refresh tokens, role assignments, verification and password reset
+// tokens and two-factor data, and returns how many users were removed.
+// SQLite doesn't enforce the foreign keys, so nothing cascades.
+func (r *userRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	before := deletedBefore.UTC().Format(sqliteTimeFormat)
+	for _, query := range []string{
+		"DELETE FROM refresh_tokens WHERE user_id IN (SELECT id FROM users WHERE deleted_at < ?)",
+		"DELETE FROM user_roles WHERE user_id IN (SELECT id FROM users WHERE deleted_at < ?)",
+		"DELETE FROM email_verification_tokens WHERE user_id IN (SELECT id FROM users WHERE deleted_at < ?)",
+		"DELETE FROM password_reset_tokens WHERE user_id IN (SELECT id FROM users WHERE deleted_at < ?)",
+		"DELETE FROM user_totp WHERE user_id IN (SELECT id FROM users WHERE deleted_at < ?)",
+		"DELETE FROM mfa_recovery_codes WHERE user_id IN (SELECT id FROM users WHERE deleted_at < ?)",
+		"DELETE FROM mfa_challenges WHERE user_id IN (SELECT id FROM users WHERE deleted_at < ?)",
+	} {
+		if _, err := tx.ExecContext(ctx, query, before); err != nil {
+			return 0, err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM users WHERE deleted_at < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return purged, tx.Commit()
+}
+
+func scanUser(row *sql.Row) (*models.User, error) {
+	var user models.User
+	var verifiedAt sql.NullTime
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &verifiedAt, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if verifiedAt.Valid {
+		user.VerifiedAt = &verifiedAt.Time
+	}
+	return &user, nil
+}
+
+func userListFilters(query *models.UserListQuery) ([]string, []any) {
+	var conditions []string
+	var args []any
+
+	if query.Username != "" {
+		conditions = append(conditions, "username = ?")
+		args = append(args, query.Username)
+	}
+	if query.Email != "" {
+		conditions = append(conditions, "email = ?")
+		args = append(args, query.Email)
+	}
+	if query.Q != "" {
+		pattern := "%" + escapeLike(query.Q) + "%"
+		conditions = append(conditions, `(username LIKE ? ESCAPE '\' OR email LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+
+	return conditions, args
+}
+
+// userSortValue formats the sort column the way SQLite stores it, so the
+// cursor compares correctly against the column.
+func userSortValue(user *models.User, sortField string) string {
+	switch sortField {
+	case "username":
+		return user.Username
+	case "email":
+		return user.Email
+	case "createdAt":
+		return user.CreatedAt.UTC().Format(sqliteTimeFormat)
+	case "updatedAt":
+		return user.UpdatedAt.UTC().Format(sqliteTimeFormat)
+	default:
+		return user.ID
+	}
 }
@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"context"
 	"golang-template/app/models"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -14,20 +16,70 @@ func NewUserRepositoryMock() *UserRepositoryMock {
 	return &UserRepositoryMock{}
 }
 
-func (m *UserRepositoryMock) Create(user *models.UserRegister) error {
-	args := m.Mock.Called(user)
-	return args.Error(0)
+func (m *UserRepositoryMock) Create(ctx context.Context, user *models.UserRegister) (*models.User, error) {
+	args := m.Mock.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *UserRepositoryMock) Update(user *models.UserUpdatePassword) error {
-	args := m.Mock.Called(user)
+func (m *UserRepositoryMock) UpdatePassword(ctx context.Context, id string, passwordHash string) error {
+	args := m.Mock.Called(ctx, id, passwordHash)
 	return args.Error(0)
 }
 
-func (m *UserRepositoryMock) List() (*[]models.User, error) {
-	args := m.Mock.Called()
+func (m *UserRepositoryMock) List(ctx context.Context, query *models.UserListQuery) (*models.UserList, error) {
+	args := m.Mock.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserList), args.Error(1)
+}
+
+func (m *UserRepositoryMock) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	args := m.Mock.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *UserRepositoryMock) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	args := m.Mock.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *UserRepositoryMock) GetByID(ctx context.Context, id string) (*models.User, error) {
+	args := m.Mock.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *UserRepositoryMock) Patch(ctx context.Context, id string, patch *models.UserPatch) (*models.User, error) {
+	args := m.Mock.Called(ctx, id, patch)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*[]models.User), args.Error(1)
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *UserRepositoryMock) SoftDelete(ctx context.Context, id string) error {
+	args := m.Mock.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) Restore(ctx context.Context, id string) error {
+	args := m.Mock.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Mock.Called(ctx, deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
 }
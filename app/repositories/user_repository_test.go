@@ -1,12 +1,16 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"golang-template/app/models"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,11 +22,28 @@ func setupTestDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
 	return db, mock
 }
 
+var uniqueConstraintError = sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}
+
+const testUserID = "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01"
+
+// uuidV7Arg matches the id Create generates.
+type uuidV7Arg struct{}
+
+func (uuidV7Arg) Match(value driver.Value) bool {
+	s, ok := value.(string)
+	if !ok {
+		return false
+	}
+	id, err := uuid.Parse(s)
+	return err == nil && id.Version() == 7
+}
+
 func TestUserRepository_Create(t *testing.T) {
 	db, mock := setupTestDB(t)
 	defer db.Close()
 
 	repo := NewUserRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	testCaseList := []struct {
 		name          string
@@ -39,8 +60,12 @@ func TestUserRepository_Create(t *testing.T) {
 			},
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec("INSERT INTO users").
-					WithArgs("testuser", "test@example.com", "password123").
+					WithArgs(uuidV7Arg{}, "testuser", "test@example.com", "password123").
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT (.+) FROM users WHERE id = (.+)").
+					WithArgs(uuidV7Arg{}).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "password", "verified_at", "created_at", "updated_at"}).
+						AddRow(testUserID, "testuser", "test@example.com", "password123", nil, testTime, testTime))
 			},
 			expectedError: nil,
 		},
@@ -53,24 +78,42 @@ func TestUserRepository_Create(t *testing.T) {
 			},
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec("INSERT INTO users").
-					WithArgs("testuser", "test@example.com", "password123").
+					WithArgs(uuidV7Arg{}, "testuser", "test@example.com", "password123").
 					WillReturnError(sql.ErrConnDone)
 			},
 			expectedError: sql.ErrConnDone,
 		},
+		{
+			name: "duplicate username",
+			user: &models.UserRegister{
+				Username: "testuser",
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO users").
+					WithArgs(uuidV7Arg{}, "testuser", "test@example.com", "password123").
+					WillReturnError(uniqueConstraintError)
+			},
+			expectedError: ErrDuplicate.Wrap(uniqueConstraintError),
+		},
 	}
 
 	for _, testCase := range testCaseList {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockSetup(mock)
-			err := repo.Create(testCase.user)
+			user, err := repo.Create(context.Background(), testCase.user)
 			assert.Equal(t, testCase.expectedError, err)
+			if err == nil {
+				assert.Equal(t, "testuser", user.Username)
+				assert.Nil(t, user.VerifiedAt)
+			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
 
-func TestUserRepository_Update(t *testing.T) {
+func TestUserRepository_UpdatePassword(t *testing.T) {
 	db, mock := setupTestDB(t)
 	defer db.Close()
 
@@ -78,32 +121,32 @@ func TestUserRepository_Update(t *testing.T) {
 
 	testCaseList := []struct {
 		name          string
-		user          *models.UserUpdatePassword
 		mockSetup     func(sqlmock.Sqlmock)
 		expectedError error
 	}{
 		{
 			name: "successful update",
-			user: &models.UserUpdatePassword{
-				Username:    "testuser",
-				NewPassword: "newpassword123",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE users SET password = \?, updated_at = CURRENT_TIMESTAMP\s+WHERE id = \? AND deleted_at IS NULL`).
+					WithArgs("new-hash", testUserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
+			expectedError: nil,
+		},
+		{
+			name: "user not found",
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec("UPDATE users").
-					WithArgs("testuser", "newpassword123", "testuser").
-					WillReturnResult(sqlmock.NewResult(1, 1))
+					WithArgs("new-hash", testUserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
-			expectedError: nil,
+			expectedError: sql.ErrNoRows,
 		},
 		{
 			name: "database error",
-			user: &models.UserUpdatePassword{
-				Username:    "testuser",
-				NewPassword: "newpassword123",
-			},
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec("UPDATE users").
-					WithArgs("testuser", "newpassword123", "testuser").
+					WithArgs("new-hash", testUserID).
 					WillReturnError(sql.ErrConnDone)
 			},
 			expectedError: sql.ErrConnDone,
@@ -113,68 +156,439 @@ func TestUserRepository_Update(t *testing.T) {
 	for _, testCase := range testCaseList {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockSetup(mock)
-			err := repo.Update(testCase.user)
+			err := repo.UpdatePassword(context.Background(), testUserID, "new-hash")
 			assert.Equal(t, testCase.expectedError, err)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
-
 }
 
 func TestUserRepository_List(t *testing.T) {
 	db, mock := setupTestDB(t)
 	defer db.Close()
 
+	repo := NewUserRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "username", "email", "verified_at", "created_at", "updated_at"}
+	id1, id2, id3 := "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01", "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d02", "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d03"
+	createdAtCursor := encodeCursor(cursor{Sort: "-createdAt", Value: "2024-01-01 00:00:00", ID: id3})
+
+	testCaseList := []struct {
+		name               string
+		query              *models.UserListQuery
+		mockSetup          func(sqlmock.Sqlmock)
+		expectedIDs        []string
+		expectedTotal      int64
+		expectedNextCursor string
+		expectedError      error
+	}{
+		{
+			name:  "first page with filters",
+			query: &models.UserListQuery{Page: 1, PageSize: 2, Sort: "id", Email: "user@example.com", Q: "50%"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users WHERE email = \? AND \(username LIKE .+\) AND deleted_at IS NULL`).
+					WithArgs("user@example.com", `%50\%%`, `%50\%%`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+				mock.ExpectQuery(`ORDER BY id ASC\s+LIMIT \? OFFSET \?`).
+					WithArgs("user@example.com", `%50\%%`, `%50\%%`, 3, 0).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(id1, "user1", "user1@example.com", testTime, testTime, testTime).
+						AddRow(id2, "user2", "user2@example.com", testTime, testTime, testTime).
+						AddRow(id3, "user3", "user3@example.com", testTime, testTime, testTime))
+			},
+			expectedIDs:        []string{id1, id2},
+			expectedTotal:      5,
+			expectedNextCursor: encodeCursor(cursor{Sort: "id", Value: id2, ID: id2}),
+			expectedError:      nil,
+		},
+		{
+			name:  "last page by cursor",
+			query: &models.UserListQuery{Page: 1, PageSize: 2, Sort: "-createdAt", Cursor: createdAtCursor},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users WHERE deleted_at IS NULL`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+				mock.ExpectQuery(`WHERE deleted_at IS NULL AND \(created_at < \? OR \(created_at = \? AND id < \?\)\)\s+ORDER BY created_at DESC, id DESC\s+LIMIT \?$`).
+					WithArgs("2024-01-01 00:00:00", "2024-01-01 00:00:00", id3, 3).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(id2, "user2", "user2@example.com", testTime, testTime, testTime))
+			},
+			expectedIDs:        []string{id2},
+			expectedTotal:      4,
+			expectedNextCursor: "",
+			expectedError:      nil,
+		},
+		{
+			name:  "malformed cursor",
+			query: &models.UserListQuery{Page: 1, PageSize: 2, Sort: "id", Cursor: "not-a-cursor"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+			},
+			expectedError: ErrInvalidCursor,
+		},
+		{
+			name:  "cursor from another sort",
+			query: &models.UserListQuery{Page: 1, PageSize: 2, Sort: "id", Cursor: createdAtCursor},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+			},
+			expectedError: ErrInvalidCursor,
+		},
+		{
+			name:  "database error",
+			query: &models.UserListQuery{Page: 1, PageSize: 2, Sort: "id"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnError(sql.ErrConnDone)
+			},
+			expectedError: sql.ErrConnDone,
+		},
+		{
+			name:  "row scan error",
+			query: &models.UserListQuery{Page: 1, PageSize: 2, Sort: "id"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("SELECT id, username, email, verified_at, created_at, updated_at FROM users").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(id1, nil, "user1@example.com", nil, testTime, testTime))
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			list, err := repo.List(context.Background(), testCase.query)
+			if testCase.expectedError == assert.AnError {
+				assert.Error(t, err)
+			} else {
+				assert.Equal(t, testCase.expectedError, err)
+			}
+			if err == nil {
+				var ids []string
+				for _, user := range list.Users {
+					ids = append(ids, user.ID)
+				}
+				assert.Equal(t, testCase.expectedIDs, ids)
+				assert.Equal(t, testCase.expectedTotal, list.Total)
+				assert.Equal(t, testCase.expectedNextCursor, list.NextCursor)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_GetByUsername(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
 	repo := NewUserRepository(db)
 	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	testCaseList := []struct {
 		name          string
+		username      string
 		mockSetup     func(sqlmock.Sqlmock)
+		expectedUser  *models.User
 		expectedError error
 	}{
 		{
-			name: "successful list",
+			name:     "user found",
+			username: "user1",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"username", "email", "created_at", "updated_at"}).
-					AddRow("user1", "user1@example.com", testTime, testTime)
-				mock.ExpectQuery("SELECT username, email, created_at, updated_at FROM users").
+				rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "verified_at", "created_at", "updated_at"}).
+					AddRow(testUserID, "user1", "user1@example.com", "hash", testTime, testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM users WHERE username = (.+) AND deleted_at IS NULL").
+					WithArgs("user1").
 					WillReturnRows(rows)
 			},
-
+			expectedUser: &models.User{
+				ID:         testUserID,
+				Username:   "user1",
+				Email:      "user1@example.com",
+				Password:   "hash",
+				VerifiedAt: &testTime,
+				CreatedAt:  testTime,
+				UpdatedAt:  testTime,
+			},
 			expectedError: nil,
 		},
 		{
-			name: "database error",
+			name:     "user not found",
+			username: "missing",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT username, email, created_at, updated_at FROM users").
-					WillReturnError(sql.ErrConnDone)
+				mock.ExpectQuery("SELECT (.+) FROM users WHERE username = (.+) AND deleted_at IS NULL").
+					WithArgs("missing").
+					WillReturnError(sql.ErrNoRows)
 			},
-			expectedError: sql.ErrConnDone,
+			expectedUser:  nil,
+			expectedError: sql.ErrNoRows,
 		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			user, err := repo.GetByUsername(context.Background(), testCase.username)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedUser, user)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_GetByID(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name          string
+		id            string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedUser  *models.User
+		expectedError error
+	}{
 		{
-			name: "row scan error",
+			name: "user found",
+			id:   testUserID,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"username", "email", "created_at", "updated_at"}).
-					AddRow("user1", "user1@example.com", testTime, testTime)
-
-				rows.AddRow(nil, "user1@example.com", testTime, testTime)
-				mock.ExpectQuery("SELECT").
+				rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "verified_at", "created_at", "updated_at"}).
+					AddRow(testUserID, "user1", "user1@example.com", "hash", testTime, testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM users WHERE id = (.+) AND deleted_at IS NULL").
+					WithArgs(testUserID).
 					WillReturnRows(rows)
 			},
-			expectedError: assert.AnError,
+			expectedUser: &models.User{
+				ID:         testUserID,
+				Username:   "user1",
+				Email:      "user1@example.com",
+				Password:   "hash",
+				VerifiedAt: &testTime,
+				CreatedAt:  testTime,
+				UpdatedAt:  testTime,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "user not found",
+			id:   "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d02",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM users WHERE id = (.+) AND deleted_at IS NULL").
+					WithArgs("0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d02").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedUser:  nil,
+			expectedError: sql.ErrNoRows,
 		},
 	}
 
 	for _, testCase := range testCaseList {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockSetup(mock)
-			_, err := repo.List()
-			if testCase.expectedError == assert.AnError {
-				assert.Error(t, err)
+			user, err := repo.GetByID(context.Background(), testCase.id)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedUser, user)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_Patch(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	username, email := "user2", "user2@example.com"
+	userRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "username", "email", "password", "verified_at", "created_at", "updated_at"}).
+			AddRow(testUserID, username, email, "hash", nil, testTime, testTime)
+	}
+
+	testCaseList := []struct {
+		name          string
+		patch         *models.UserPatch
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name:  "both fields",
+			patch: &models.UserPatch{Username: &username, Email: &email},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE users SET username = \?, email = \?, verified_at = CASE WHEN email = \? THEN verified_at END, updated_at = CURRENT_TIMESTAMP\s+WHERE id = \? AND deleted_at IS NULL`).
+					WithArgs(username, email, email, testUserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT (.+) FROM users WHERE id = (.+)").WithArgs(testUserID).WillReturnRows(userRows())
+			},
+		},
+		{
+			name:  "nothing to change",
+			patch: &models.UserPatch{},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM users WHERE id = (.+)").WithArgs(testUserID).WillReturnRows(userRows())
+			},
+		},
+		{
+			name:  "user not found",
+			patch: &models.UserPatch{Email: &email},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE users SET email = \?`).
+					WithArgs(email, email, testUserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: sql.ErrNoRows,
+		},
+		{
+			name:  "duplicate email",
+			patch: &models.UserPatch{Email: &email},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE users SET email = \?`).
+					WithArgs(email, email, testUserID).
+					WillReturnError(uniqueConstraintError)
+			},
+			expectedError: ErrDuplicate.Wrap(uniqueConstraintError),
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			user, err := repo.Patch(context.Background(), testUserID, testCase.patch)
+			assert.Equal(t, testCase.expectedError, err)
+			if err == nil {
+				assert.Equal(t, email, user.Email)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_SoftDeleteAndRestore(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	testCaseList := []struct {
+		name          string
+		call          func() error
+		query         string
+		rowsAffected  int64
+		execError     error
+		expectedError error
+	}{
+		{
+			name:         "soft delete",
+			call:         func() error { return repo.SoftDelete(context.Background(), testUserID) },
+			query:        `UPDATE users SET deleted_at = CURRENT_TIMESTAMP\s+WHERE id = \? AND deleted_at IS NULL`,
+			rowsAffected: 1,
+		},
+		{
+			name:          "soft delete of a deleted user",
+			call:          func() error { return repo.SoftDelete(context.Background(), testUserID) },
+			query:         `UPDATE users SET deleted_at = CURRENT_TIMESTAMP`,
+			rowsAffected:  0,
+			expectedError: sql.ErrNoRows,
+		},
+		{
+			name:         "restore",
+			call:         func() error { return repo.Restore(context.Background(), testUserID) },
+			query:        `UPDATE users SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP\s+WHERE id = \? AND deleted_at IS NOT NULL`,
+			rowsAffected: 1,
+		},
+		{
+			name:          "restore of a user that isn't deleted",
+			call:          func() error { return repo.Restore(context.Background(), testUserID) },
+			query:         `UPDATE users SET deleted_at = NULL`,
+			rowsAffected:  0,
+			expectedError: sql.ErrNoRows,
+		},
+		{
+			name:          "restore of a user whose username was taken",
+			call:          func() error { return repo.Restore(context.Background(), testUserID) },
+			query:         `UPDATE users SET deleted_at = NULL`,
+			execError:     uniqueConstraintError,
+			expectedError: ErrDuplicate.Wrap(uniqueConstraintError),
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			expectation := mock.ExpectExec(testCase.query).WithArgs(testUserID)
+			if testCase.execError != nil {
+				expectation.WillReturnError(testCase.execError)
 			} else {
-				assert.Equal(t, testCase.expectedError, err)
+				expectation.WillReturnResult(sqlmock.NewResult(0, testCase.rowsAffected))
 			}
+			assert.Equal(t, testCase.expectedError, testCase.call())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_PurgeDeleted(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	deletedBefore := time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+
+	testCaseList := []struct {
+		name           string
+		mockSetup      func(sqlmock.Sqlmock)
+		expectedPurged int64
+		expectedError  error
+	}{
+		{
+			name: "purges users with their tokens and roles",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`DELETE FROM refresh_tokens WHERE user_id IN \(SELECT id FROM users WHERE deleted_at < \?\)`).
+					WithArgs("2024-01-01 10:00:00").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(`DELETE FROM user_roles WHERE user_id IN \(SELECT id FROM users WHERE deleted_at < \?\)`).
+					WithArgs("2024-01-01 10:00:00").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM email_verification_tokens WHERE user_id IN \(SELECT id FROM users WHERE deleted_at < \?\)`).
+					WithArgs("2024-01-01 10:00:00").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`DELETE FROM password_reset_tokens WHERE user_id IN \(SELECT id FROM users WHERE deleted_at < \?\)`).
+					WithArgs("2024-01-01 10:00:00").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM user_totp WHERE user_id IN \(SELECT id FROM users WHERE deleted_at < \?\)`).
+					WithArgs("2024-01-01 10:00:00").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM mfa_recovery_codes WHERE user_id IN \(SELECT id FROM users WHERE deleted_at < \?\)`).
+					WithArgs("2024-01-01 10:00:00").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM mfa_challenges WHERE user_id IN \(SELECT id FROM users WHERE deleted_at < \?\)`).
+					WithArgs("2024-01-01 10:00:00").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM users WHERE deleted_at < \?`).
+					WithArgs("2024-01-01 10:00:00").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			expectedPurged: 2,
+		},
+		{
+			name: "database error rolls back",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`DELETE FROM refresh_tokens`).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			purged, err := repo.PurgeDeleted(context.Background(), deletedBefore)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPurged, purged)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+)
+
+type UserRoleRepository interface {
+	AssignRole(ctx context.Context, userID string, roleID int64) error
+	RemoveRole(ctx context.Context, userID string, roleID int64) error
+	ListRolesByUser(ctx context.Context, userID string) (*[]models.Role, error)
+	AssignPermission(ctx context.Context, roleID int64, permissionID int64) error
+	RemovePermission(ctx context.Context, roleID int64, permissionID int64) error
+	ListPermissionsByRole(ctx context.Context, roleID int64) (*[]models.Permission, error)
+	ListPermissionsByUser(ctx context.Context, userID string) (*[]models.Permission, error)
+}
+
+type userRoleRepository struct {
+	db *sql.DB
+}
+
+func NewUserRoleRepository(db *sql.DB) UserRoleRepository {
+	return &userRoleRepository{db: db}
+}
+
+func (r *userRoleRepository) AssignRole(ctx context.Context, userID string, roleID int64) error {
+	query := `
+		INSERT OR IGNORE INTO user_roles (user_id, role_id)
+		VALUES (?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, roleID)
+	return mapError(err)
+}
+
+func (r *userRoleRepository) RemoveRole(ctx context.Context, userID string, roleID int64) error {
+	query := `
+		DELETE FROM user_roles WHERE user_id = ? AND role_id = ?
+	`
+	return execAffectingRow(ctx, r.db, query, userID, roleID)
+}
+
+func (r *userRoleRepository) ListRolesByUser(ctx context.Context, userID string) (*[]models.Role, error) {
+	query := `
+		SELECT r.id, r.name, r.description, r.created_at, r.updated_at FROM roles r
+		INNER JOIN user_roles ur ON r.id = ur.role_id
+		WHERE ur.user_id = ?
+		ORDER BY r.name
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRoles(rows)
+}
+
+func (r *userRoleRepository) AssignPermission(ctx context.Context, roleID int64, permissionID int64) error {
+	query := `
+		INSERT OR IGNORE INTO role_permissions (role_id, permission_id)
+		VALUES (?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query, roleID, permissionID)
+	return mapError(err)
+}
+
+func (r *userRoleRepository) RemovePermission(ctx context.Context, roleID int64, permissionID int64) error {
+	query := `
+		DELETE FROM role_permissions WHERE role_id = ? AND permission_id = ?
+	`
+	return execAffectingRow(ctx, r.db, query, roleID, permissionID)
+}
+
+func (r *userRoleRepository) ListPermissionsByRole(ctx context.Context, roleID int64) (*[]models.Permission, error) {
+	query := `
+		SELECT p.id, p.name, p.description, p.resource, p.action, p.created_at, p.updated_at FROM permissions p
+		INNER JOIN role_permissions rp ON p.id = rp.permission_id
+		WHERE rp.role_id = ?
+		ORDER BY p.resource, p.action
+	`
+	rows, err := r.db.QueryContext(ctx, query, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPermissions(rows)
+}
+
+func (r *userRoleRepository) ListPermissionsByUser(ctx context.Context, userID string) (*[]models.Permission, error) {
+	query := `
+		SELECT DISTINCT p.id, p.name, p.description, p.resource, p.action, p.created_at, p.updated_at FROM permissions p
+		INNER JOIN role_permissions rp ON p.id = rp.permission_id
+		INNER JOIN user_roles ur ON rp.role_id = ur.role_id
+		WHERE ur.user_id = ?
+		ORDER BY p.resource, p.action
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPermissions(rows)
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func execAffectingRow(ctx context.Context, db execer, query string, args ...any) error {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
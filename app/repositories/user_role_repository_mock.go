@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type UserRoleRepositoryMock struct {
+	mock.Mock
+}
+
+func NewUserRoleRepositoryMock() *UserRoleRepositoryMock {
+	return &UserRoleRepositoryMock{}
+}
+
+func (m *UserRoleRepositoryMock) AssignRole(ctx context.Context, userID string, roleID int64) error {
+	args := m.Mock.Called(ctx, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *UserRoleRepositoryMock) RemoveRole(ctx context.Context, userID string, roleID int64) error {
+	args := m.Mock.Called(ctx, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *UserRoleRepositoryMock) ListRolesByUser(ctx context.Context, userID string) (*[]models.Role, error) {
+	args := m.Mock.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*[]models.Role), args.Error(1)
+}
+
+func (m *UserRoleRepositoryMock) AssignPermission(ctx context.Context, roleID int64, permissionID int64) error {
+	args := m.Mock.Called(ctx, roleID, permissionID)
+	return args.Error(0)
+}
+
+func (m *UserRoleRepositoryMock) RemovePermission(ctx context.Context, roleID int64, permissionID int64) error {
+	args := m.Mock.Called(ctx, roleID, permissionID)
+	return args.Error(0)
+}
+
+func (m *UserRoleRepositoryMock) ListPermissionsByRole(ctx context.Context, roleID int64) (*[]models.Permission, error) {
+	args := m.Mock.Called(ctx, roleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*[]models.Permission), args.Error(1)
+}
+
+func (m *UserRoleRepositoryMock) ListPermissionsByUser(ctx context.Context, userID string) (*[]models.Permission, error) {
+	args := m.Mock.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*[]models.Permission), args.Error(1)
+}
@@ -0,0 +1,302 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRoleRepository_AssignRole(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRoleRepository(db)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "successful assignment",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT OR IGNORE INTO user_roles").
+					WithArgs(testUserID, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedError: nil,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT OR IGNORE INTO user_roles").
+					WithArgs(testUserID, 2).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.AssignRole(context.Background(), testUserID, 2)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRoleRepository_RemoveRole(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRoleRepository(db)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "successful removal",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM user_roles (.+)").
+					WithArgs(testUserID, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedError: nil,
+		},
+		{
+			name: "assignment not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM user_roles (.+)").
+					WithArgs(testUserID, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.RemoveRole(context.Background(), testUserID, 2)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRoleRepository_ListRolesByUser(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRoleRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedRoles *[]models.Role
+		expectedError error
+	}{
+		{
+			name: "successful list",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(roleColumns).AddRow(2, "admin", "Administrator", testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM roles r INNER JOIN user_roles ur (.+)").
+					WithArgs(testUserID).
+					WillReturnRows(rows)
+			},
+			expectedRoles: &[]models.Role{{ID: 2, Name: "admin", Description: "Administrator", CreatedAt: testTime, UpdatedAt: testTime}},
+			expectedError: nil,
+		},
+		{
+			name: "no roles",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM roles r INNER JOIN user_roles ur (.+)").
+					WithArgs(testUserID).
+					WillReturnRows(sqlmock.NewRows(roleColumns))
+			},
+			expectedRoles: &[]models.Role{},
+			expectedError: nil,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM roles r INNER JOIN user_roles ur (.+)").
+					WithArgs(testUserID).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedRoles: nil,
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			roles, err := repo.ListRolesByUser(context.Background(), testUserID)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedRoles, roles)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRoleRepository_AssignPermission(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRoleRepository(db)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "successful assignment",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT OR IGNORE INTO role_permissions").
+					WithArgs(2, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedError: nil,
+		},
+		{
+			name: "database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT OR IGNORE INTO role_permissions").
+					WithArgs(2, 3).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.AssignPermission(context.Background(), 2, 3)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRoleRepository_RemovePermission(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRoleRepository(db)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "successful removal",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM role_permissions (.+)").
+					WithArgs(2, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedError: nil,
+		},
+		{
+			name: "assignment not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM role_permissions (.+)").
+					WithArgs(2, 3).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			err := repo.RemovePermission(context.Background(), 2, 3)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRoleRepository_ListPermissions(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRoleRepository(db)
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectedPermissions := &[]models.Permission{
+		{ID: 3, Name: "user:read", Resource: "user", Action: "read", CreatedAt: testTime, UpdatedAt: testTime},
+	}
+
+	testCaseList := []struct {
+		name                string
+		list                func() (*[]models.Permission, error)
+		mockSetup           func(sqlmock.Sqlmock)
+		expectedPermissions *[]models.Permission
+		expectedError       error
+	}{
+		{
+			name: "by role",
+			list: func() (*[]models.Permission, error) { return repo.ListPermissionsByRole(context.Background(), 2) },
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(permissionColumns).AddRow(3, "user:read", "", "user", "read", testTime, testTime)
+				mock.ExpectQuery("SELECT (.+) FROM permissions p INNER JOIN role_permissions rp (.+)").
+					WithArgs(2).
+					WillReturnRows(rows)
+			},
+			expectedPermissions: expectedPermissions,
+			expectedError:       nil,
+		},
+		{
+			name: "by user",
+			list: func() (*[]models.Permission, error) {
+				return repo.ListPermissionsByUser(context.Background(), testUserID)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(permissionColumns).AddRow(3, "user:read", "", "user", "read", testTime, testTime)
+				mock.ExpectQuery("SELECT DISTINCT (.+) FROM permissions p INNER JOIN role_permissions rp (.+) INNER JOIN user_roles ur (.+)").
+					WithArgs(testUserID).
+					WillReturnRows(rows)
+			},
+			expectedPermissions: expectedPermissions,
+			expectedError:       nil,
+		},
+		{
+			name: "database error",
+			list: func() (*[]models.Permission, error) {
+				return repo.ListPermissionsByUser(context.Background(), testUserID)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT DISTINCT (.+) FROM permissions p (.+)").
+					WithArgs(testUserID).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedPermissions: nil,
+			expectedError:       sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockSetup(mock)
+			permissions, err := testCase.list()
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPermissions, permissions)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,14 +1,28 @@
 package app
 
+import "net/http"
+
 const (
 	StatusSuccess = "success"
 	StatusError   = "error"
 )
 
 type Response struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Data    any    `json:"data,omitempty"`
+	Status  string  `json:"status"`
+	Code    string  `json:"code,omitempty"`
+	Message string  `json:"message"`
+	Data    any     `json:"data,omitempty"`
+	Paging  *Paging `json:"paging,omitempty"`
+	Errors  any     `json:"errors,omitempty"`
+}
+
+// Paging describes a page of a list response. Page is only set for offset
+// pagination; NextCursor is empty on the last page.
+type Paging struct {
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"pageSize"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 func NewResponse(message string, data any) Response {
@@ -19,9 +33,46 @@ func NewResponse(message string, data any) Response {
 	}
 }
 
+func NewPagedResponse(message string, data any, paging Paging) Response {
+	return Response{
+		Status:  StatusSuccess,
+		Message: message,
+		Data:    data,
+		Paging:  &paging,
+	}
+}
+
 func NewResponseError(err error) Response {
+	appErr := AsError(err)
 	return Response{
 		Status:  StatusError,
-		Message: err.Error(),
+		Code:    appErr.Code,
+		Message: appErr.Message,
+		Errors:  appErr.Details,
+	}
+}
+
+// Problem is an RFC 7807 problem details body, sent as
+// application/problem+json to clients that ask for it.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	Errors   any    `json:"errors,omitempty"`
+}
+
+func NewProblem(err error, instance string) Problem {
+	appErr := AsError(err)
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(appErr.Status()),
+		Status:   appErr.Status(),
+		Detail:   appErr.Message,
+		Instance: instance,
+		Code:     appErr.Code,
+		Errors:   appErr.Details,
 	}
 }
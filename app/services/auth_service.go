@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"golang-template/logger"
+	"golang-template/token"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRefreshToken = app.NewUnauthorizedError("invalid_refresh_token", "invalid or expired refresh token")
+	ErrRefreshTokenReused  = app.NewUnauthorizedError("refresh_token_reused", "refresh token reuse detected")
+)
+
+type AuthService interface {
+	Login(ctx context.Context, login *models.UserLogin, ip string) (*models.LoginResponse, error)
+	VerifyMFA(ctx context.Context, request *models.MFAVerifyRequest, ip string) (*models.MFAVerifyResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+}
+
+type authService struct {
+	userService            UserService
+	userRoleService        UserRoleService
+	mfaService             MFAService
+	loginAttemptService    LoginAttemptService
+	refreshTokenRepository repositories.RefreshTokenRepository
+	tokenManager           token.Manager
+}
+
+func NewAuthService(
+	userService UserService,
+	userRoleService UserRoleService,
+	mfaService MFAService,
+	loginAttemptService LoginAttemptService,
+	refreshTokenRepository repositories.RefreshTokenRepository,
+	tokenManager token.Manager,
+) AuthService {
+	return &authService{
+		userService:            userService,
+		userRoleService:        userRoleService,
+		mfaService:             mfaService,
+		loginAttemptService:    loginAttemptService,
+		refreshTokenRepository: refreshTokenRepository,
+		tokenManager:           tokenManager,
+	}
+}
+
+// Login checks the password. Users with two-factor authentication get a
+// challenge instead of tokens, which VerifyMFA exchanges for the tokens.
+// Users whose roles require it but who haven't enabled it get a new secret to
+// enroll along with the challenge, so they never get tokens without a second
+// factor. The user's failed logins are only cleared once tokens are issued.
+func (s *authService) Login(ctx context.Context, login *models.UserLogin, ip string) (*models.LoginResponse, error) {
+	ctx, span := tracer.Start(ctx, "authService.Login")
+	defer span.End()
+
+	user, err := s.userService.Authenticate(ctx, login.Username, login.Password, ip)
+	if err != nil {
+		return nil, err
+	}
+	if user.VerifiedAt == nil {
+		return nil, ErrEmailNotVerified
+	}
+
+	mfaEnabled, err := s.mfaService.Enabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	var enrollment *models.MFAEnrollment
+	if !mfaEnabled {
+		mfaRequired, err := s.mfaRequired(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if mfaRequired {
+			enrollment, err = s.mfaService.Enroll(ctx, user.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if mfaEnabled || enrollment != nil {
+		challengeToken, expiresIn, err := s.mfaService.CreateChallenge(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &models.LoginResponse{
+			MFARequired:        true,
+			ChallengeToken:     challengeToken,
+			ChallengeExpiresIn: int64(expiresIn.Seconds()),
+			MFAEnrollment:      enrollment,
+		}, nil
+	}
+
+	tokenPair, err := s.issueTokens(ctx, user, uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+	s.loginAttemptService.RecordSuccess(ctx, user, ip)
+	return &models.LoginResponse{TokenPair: *tokenPair}, nil
+}
+
+func (s *authService) VerifyMFA(ctx context.Context, request *models.MFAVerifyRequest, ip string) (*models.MFAVerifyResponse, error) {
+	ctx, span := tracer.Start(ctx, "authService.VerifyMFA")
+	defer span.End()
+
+	userID, recoveryCodes, err := s.mfaService.CompleteChallenge(ctx, request.ChallengeToken, request.OTP, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.GetByID(ctx, userID)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tokenPair, err := s.issueTokens(ctx, user, uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+	s.loginAttemptService.RecordSuccess(ctx, user, ip)
+
+	response := &models.MFAVerifyResponse{TokenPair: *tokenPair}
+	if recoveryCodes != nil {
+		response.RecoveryCodes = recoveryCodes.RecoveryCodes
+	}
+	return response, nil
+}
+
+// Refresh rotates the refresh token. Presenting a token that was already
+// rotated means it leaked, so the whole session family is revoked.
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	ctx, span := tracer.Start(ctx, "authService.Refresh")
+	defer span.End()
+
+	stored, err := s.refreshTokenRepository.GetByHash(ctx, token.HashRefreshToken(refreshToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, stored.FamilyID)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	err = s.refreshTokenRepository.Revoke(ctx, stored.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, s.revokeReusedFamily(ctx, stored.FamilyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.GetByID(ctx, stored.UserID)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, stored.FamilyID)
+}
+
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	ctx, span := tracer.Start(ctx, "authService.Logout")
+	defer span.End()
+
+	stored, err := s.refreshTokenRepository.GetByHash(ctx, token.HashRefreshToken(refreshToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepository.RevokeFamily(ctx, stored.FamilyID)
+}
+
+func (s *authService) revokeReusedFamily(ctx context.Context, familyID string) error {
+	logger.FromContext(ctx).With(logger.Fields{"family_id": familyID}).Warn("Refresh token reused, revoking family")
+	if err := s.refreshTokenRepository.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+func (s *authService) mfaRequired(ctx context.Context, user *models.User) (bool, error) {
+	roles, err := s.userRoleService.ListUserRoles(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	names := []string{}
+	for _, role := range *roles {
+		names = append(names, role.Name)
+	}
+	return s.mfaService.Required(names), nil
+}
+
+func (s *authService) identity(ctx context.Context, user *models.User) (token.Identity, error) {
+	identity := token.Identity{UserID: user.ID, Username: user.Username}
+
+	roles, err := s.userRoleService.ListUserRoles(ctx, user.ID)
+	if err != nil {
+		return identity, err
+	}
+	for _, role := range *roles {
+		identity.Roles = append(identity.Roles, role.Name)
+	}
+
+	permissions, err := s.userRoleService.ListUserPermissions(ctx, user.ID)
+	if err != nil {
+		return identity, err
+	}
+	for _, permission := range *permissions {
+		identity.Permissions = append(identity.Permissions, permission.Name)
+	}
+
+	return identity, nil
+}
+
+func (s *authService) issueTokens(ctx context.Context, user *models.User, familyID string) (*models.TokenPair, error) {
+	identity, err := s.identity(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.tokenManager.GenerateAccessToken(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := s.tokenManager.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.refreshTokenRepository.Create(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: token.HashRefreshToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.tokenManager.RefreshTokenTTL()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.tokenManager.AccessTokenTTL().Seconds()),
+	}, nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type AuthServiceMock struct {
+	mock.Mock
+}
+
+func NewAuthServiceMock() *AuthServiceMock {
+	return &AuthServiceMock{}
+}
+
+func (m *AuthServiceMock) Login(ctx context.Context, login *models.UserLogin, ip string) (*models.LoginResponse, error) {
+	args := m.Mock.Called(ctx, login, ip)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginResponse), args.Error(1)
+}
+
+func (m *AuthServiceMock) VerifyMFA(ctx context.Context, request *models.MFAVerifyRequest, ip string) (*models.MFAVerifyResponse, error) {
+	args := m.Mock.Called(ctx, request, ip)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MFAVerifyResponse), args.Error(1)
+}
+
+func (m *AuthServiceMock) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	args := m.Mock.Called(ctx, refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TokenPair), args.Error(1)
+}
+
+func (m *AuthServiceMock) Logout(ctx context.Context, refreshToken string) error {
+	args := m.Mock.Called(ctx, refreshToken)
+	return args.Error(0)
+}
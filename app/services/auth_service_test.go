@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"golang-template/logger"
+	"golang-template/token"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupIdentityMocks(u *UserRoleServiceMock, m *token.ManagerMock) {
+	u.On("ListUserRoles", mock.Anything, testUserID).Return(&[]models.Role{{ID: 2, Name: "admin"}}, nil)
+	u.On("ListUserPermissions", mock.Anything, testUserID).Return(&[]models.Permission{{ID: 3, Name: "user:read"}}, nil)
+	m.On("GenerateAccessToken", token.Identity{
+		UserID:      testUserID,
+		Username:    "testuser",
+		Roles:       []string{"admin"},
+		Permissions: []string{"user:read"},
+	}).Return("access-token", nil)
+	m.On("GenerateRefreshToken").Return("refresh-token", nil)
+	m.On("AccessTokenTTL").Return(15 * time.Minute)
+	m.On("RefreshTokenTTL").Return(24 * time.Hour)
+}
+
+func TestAuthService_Login(t *testing.T) {
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	user := &models.User{ID: testUserID, Username: "testuser", VerifiedAt: &verifiedAt}
+
+	testCaseList := []struct {
+		name          string
+		login         *models.UserLogin
+		mockSetup     func(*UserServiceMock, *UserRoleServiceMock, *MFAServiceMock, *LoginAttemptServiceMock, *repositories.RefreshTokenRepositoryMock, *token.ManagerMock)
+		expected      *models.LoginResponse
+		expectedError error
+	}{
+		{
+			name:  "successful login",
+			login: &models.UserLogin{Username: "testuser", Password: "password123"},
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, mf *MFAServiceMock, l *LoginAttemptServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				u.On("Authenticate", mock.Anything, "testuser", "password123", "10.0.0.1").Return(user, nil)
+				mf.On("Enabled", mock.Anything, testUserID).Return(false, nil)
+				setupIdentityMocks(ur, m)
+				mf.On("Required", []string{"admin"}).Return(false)
+				r.On("Create", mock.Anything, mock.MatchedBy(func(refreshToken *models.RefreshToken) bool {
+					return refreshToken.UserID == testUserID &&
+						refreshToken.TokenHash == token.HashRefreshToken("refresh-token") &&
+						refreshToken.FamilyID != ""
+				})).Return(nil)
+				l.On("RecordSuccess", mock.Anything, user, "10.0.0.1")
+			},
+			expected: &models.LoginResponse{TokenPair: models.TokenPair{
+				AccessToken:  "access-token",
+				RefreshToken: "refresh-token",
+				TokenType:    "Bearer",
+				ExpiresIn:    900,
+			}},
+			expectedError: nil,
+		},
+		{
+			name:  "two-factor challenge",
+			login: &models.UserLogin{Username: "testuser", Password: "password123"},
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, mf *MFAServiceMock, l *LoginAttemptServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				u.On("Authenticate", mock.Anything, "testuser", "password123", "10.0.0.1").Return(user, nil)
+				mf.On("Enabled", mock.Anything, testUserID).Return(true, nil)
+				mf.On("CreateChallenge", mock.Anything, testUserID).Return("challenge-token", 5*time.Minute, nil)
+			},
+			expected: &models.LoginResponse{
+				MFARequired:        true,
+				ChallengeToken:     "challenge-token",
+				ChallengeExpiresIn: 300,
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "required two-factor enrollment",
+			login: &models.UserLogin{Username: "testuser", Password: "password123"},
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, mf *MFAServiceMock, l *LoginAttemptServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				u.On("Authenticate", mock.Anything, "testuser", "password123", "10.0.0.1").Return(user, nil)
+				mf.On("Enabled", mock.Anything, testUserID).Return(false, nil)
+				ur.On("ListUserRoles", mock.Anything, testUserID).Return(&[]models.Role{{ID: 2, Name: "admin"}}, nil)
+				mf.On("Required", []string{"admin"}).Return(true)
+				mf.On("Enroll", mock.Anything, testUserID).Return(&models.MFAEnrollment{Secret: "SECRET"}, nil)
+				mf.On("CreateChallenge", mock.Anything, testUserID).Return("challenge-token", 5*time.Minute, nil)
+			},
+			expected: &models.LoginResponse{
+				MFARequired:        true,
+				ChallengeToken:     "challenge-token",
+				ChallengeExpiresIn: 300,
+				MFAEnrollment:      &models.MFAEnrollment{Secret: "SECRET"},
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "invalid credentials",
+			login: &models.UserLogin{Username: "testuser", Password: "wrong"},
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, mf *MFAServiceMock, l *LoginAttemptServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				u.On("Authenticate", mock.Anything, "testuser", "wrong", "10.0.0.1").Return(nil, ErrInvalidCredentials)
+			},
+			expected:      nil,
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:  "unverified email",
+			login: &models.UserLogin{Username: "testuser", Password: "password123"},
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, mf *MFAServiceMock, l *LoginAttemptServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				u.On("Authenticate", mock.Anything, "testuser", "password123", "10.0.0.1").Return(&models.User{ID: testUserID, Username: "testuser"}, nil)
+			},
+			expected:      nil,
+			expectedError: ErrEmailNotVerified,
+		},
+		{
+			name:  "repository error",
+			login: &models.UserLogin{Username: "testuser", Password: "password123"},
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, mf *MFAServiceMock, l *LoginAttemptServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				u.On("Authenticate", mock.Anything, "testuser", "password123", "10.0.0.1").Return(user, nil)
+				mf.On("Enabled", mock.Anything, testUserID).Return(false, nil)
+				setupIdentityMocks(ur, m)
+				mf.On("Required", []string{"admin"}).Return(false)
+				r.On("Create", mock.Anything, mock.Anything).Return(assert.AnError)
+			},
+			expected:      nil,
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			userServiceMock := NewUserServiceMock()
+			userRoleServiceMock := NewUserRoleServiceMock()
+			mfaServiceMock := NewMFAServiceMock()
+			loginAttemptMock := NewLoginAttemptServiceMock()
+			refreshTokenRepoMock := repositories.NewRefreshTokenRepositoryMock()
+			tokenManagerMock := token.NewManagerMock()
+			testCase.mockSetup(userServiceMock, userRoleServiceMock, mfaServiceMock, loginAttemptMock, refreshTokenRepoMock, tokenManagerMock)
+
+			service := NewAuthService(userServiceMock, userRoleServiceMock, mfaServiceMock, loginAttemptMock, refreshTokenRepoMock, tokenManagerMock)
+			response, err := service.Login(context.Background(), testCase.login, "10.0.0.1")
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expected, response)
+			userServiceMock.AssertExpectations(t)
+			userRoleServiceMock.AssertExpectations(t)
+			mfaServiceMock.AssertExpectations(t)
+			loginAttemptMock.AssertExpectations(t)
+			refreshTokenRepoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	user := &models.User{ID: testUserID, Username: "testuser"}
+	tokenHash := token.HashRefreshToken("old-refresh-token")
+	revokedAt := time.Now().Add(-time.Minute)
+
+	activeToken := func() *models.RefreshToken {
+		return &models.RefreshToken{ID: 7, UserID: testUserID, FamilyID: "family", TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour)}
+	}
+
+	testCaseList := []struct {
+		name            string
+		mockSetup       func(*UserServiceMock, *UserRoleServiceMock, *repositories.RefreshTokenRepositoryMock, *token.ManagerMock)
+		expectedPair    *models.TokenPair
+		expectedError   error
+		expectedWarning string
+	}{
+		{
+			name: "successful rotation",
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(activeToken(), nil)
+				r.On("Revoke", mock.Anything, int64(7)).Return(nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				setupIdentityMocks(ur, m)
+				r.On("Create", mock.Anything, mock.MatchedBy(func(refreshToken *models.RefreshToken) bool {
+					return refreshToken.FamilyID == "family"
+				})).Return(nil)
+			},
+			expectedPair: &models.TokenPair{
+				AccessToken:  "access-token",
+				RefreshToken: "refresh-token",
+				TokenType:    "Bearer",
+				ExpiresIn:    900,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "unknown token",
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(nil, sql.ErrNoRows)
+			},
+			expectedPair:  nil,
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "expired token",
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				expired := activeToken()
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				r.On("GetByHash", mock.Anything, tokenHash).Return(expired, nil)
+			},
+			expectedPair:  nil,
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "reused token revokes family",
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				revoked := activeToken()
+				revoked.RevokedAt = &revokedAt
+				r.On("GetByHash", mock.Anything, tokenHash).Return(revoked, nil)
+				r.On("RevokeFamily", mock.Anything, "family").Return(nil)
+			},
+			expectedPair:    nil,
+			expectedError:   ErrRefreshTokenReused,
+			expectedWarning: "Refresh token reused, revoking family",
+		},
+		{
+			name: "concurrent rotation revokes family",
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(activeToken(), nil)
+				r.On("Revoke", mock.Anything, int64(7)).Return(sql.ErrNoRows)
+				r.On("RevokeFamily", mock.Anything, "family").Return(nil)
+			},
+			expectedPair:    nil,
+			expectedError:   ErrRefreshTokenReused,
+			expectedWarning: "Refresh token reused, revoking family",
+		},
+		{
+			name: "user no longer exists",
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(activeToken(), nil)
+				r.On("Revoke", mock.Anything, int64(7)).Return(nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(nil, ErrUserNotFound)
+			},
+			expectedPair:  nil,
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "repository error",
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(nil, assert.AnError)
+			},
+			expectedPair:  nil,
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			userServiceMock := NewUserServiceMock()
+			userRoleServiceMock := NewUserRoleServiceMock()
+			refreshTokenRepoMock := repositories.NewRefreshTokenRepositoryMock()
+			tokenManagerMock := token.NewManagerMock()
+			loggerMock := logger.NewLoggerMock()
+			testCase.mockSetup(userServiceMock, userRoleServiceMock, refreshTokenRepoMock, tokenManagerMock)
+			if testCase.expectedWarning != "" {
+				loggerMock.On("With", logger.Fields{"family_id": "family"}).Return(loggerMock)
+				loggerMock.On("Warn", testCase.expectedWarning)
+			}
+			ctx := logger.WithContext(context.Background(), loggerMock)
+
+			service := NewAuthService(userServiceMock, userRoleServiceMock, NewMFAServiceMock(), NewLoginAttemptServiceMock(), refreshTokenRepoMock, tokenManagerMock)
+			pair, err := service.Refresh(ctx, "old-refresh-token")
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPair, pair)
+			userServiceMock.AssertExpectations(t)
+			userRoleServiceMock.AssertExpectations(t)
+			refreshTokenRepoMock.AssertExpectations(t)
+			loggerMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	tokenHash := token.HashRefreshToken("refresh-token")
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.RefreshTokenRepositoryMock)
+		expectedError error
+	}{
+		{
+			name: "successful logout",
+			mockSetup: func(r *repositories.RefreshTokenRepositoryMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(&models.RefreshToken{ID: 1, FamilyID: "family"}, nil)
+				r.On("RevokeFamily", mock.Anything, "family").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "unknown token",
+			mockSetup: func(r *repositories.RefreshTokenRepositoryMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "repository error",
+			mockSetup: func(r *repositories.RefreshTokenRepositoryMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(&models.RefreshToken{ID: 1, FamilyID: "family"}, nil)
+				r.On("RevokeFamily", mock.Anything, "family").Return(assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			refreshTokenRepoMock := repositories.NewRefreshTokenRepositoryMock()
+			testCase.mockSetup(refreshTokenRepoMock)
+
+			service := NewAuthService(NewUserServiceMock(), NewUserRoleServiceMock(), NewMFAServiceMock(), NewLoginAttemptServiceMock(), refreshTokenRepoMock, token.NewManagerMock())
+			err := service.Logout(context.Background(), "refresh-token")
+
+			assert.Equal(t, testCase.expectedError, err)
+			refreshTokenRepoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_VerifyMFA(t *testing.T) {
+	user := &models.User{ID: testUserID, Username: "testuser"}
+	request := &models.MFAVerifyRequest{ChallengeToken: "challenge-token", OTP: "123456"}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*UserServiceMock, *UserRoleServiceMock, *MFAServiceMock, *LoginAttemptServiceMock, *repositories.RefreshTokenRepositoryMock, *token.ManagerMock)
+		expected      *models.MFAVerifyResponse
+		expectedError error
+	}{
+		{
+			name: "successful verification",
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, mf *MFAServiceMock, l *LoginAttemptServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				mf.On("CompleteChallenge", mock.Anything, "challenge-token", "123456", "10.0.0.1").Return(testUserID, nil, nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				setupIdentityMocks(ur, m)
+				r.On("Create", mock.Anything, mock.MatchedBy(func(refreshToken *models.RefreshToken) bool {
+					return refreshToken.UserID == testUserID && refreshToken.FamilyID != ""
+				})).Return(nil)
+				l.On("RecordSuccess", mock.Anything, user, "10.0.0.1")
+			},
+			expected: &models.MFAVerifyResponse{TokenPair: models.TokenPair{
+				AccessToken:  "access-token",
+				RefreshToken: "refresh-token",
+				TokenType:    "Bearer",
+				ExpiresIn:    900,
+			}},
+		},
+		{
+			name: "verification enabling two-factor authentication",
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, mf *MFAServiceMock, l *LoginAttemptServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				recoveryCodes := &models.MFARecoveryCodes{RecoveryCodes: []string{"abcd-efgh"}}
+				mf.On("CompleteChallenge", mock.Anything, "challenge-token", "123456", "10.0.0.1").Return(testUserID, recoveryCodes, nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				setupIdentityMocks(ur, m)
+				r.On("Create", mock.Anything, mock.Anything).Return(nil)
+				l.On("RecordSuccess", mock.Anything, user, "10.0.0.1")
+			},
+			expected: &models.MFAVerifyResponse{
+				TokenPair: models.TokenPair{
+					AccessToken:  "access-token",
+					RefreshToken: "refresh-token",
+					TokenType:    "Bearer",
+					ExpiresIn:    900,
+				},
+				RecoveryCodes: []string{"abcd-efgh"},
+			},
+		},
+		{
+			name: "invalid code",
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, mf *MFAServiceMock, l *LoginAttemptServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				mf.On("CompleteChallenge", mock.Anything, "challenge-token", "123456", "10.0.0.1").Return("", nil, ErrInvalidMFACode)
+			},
+			expectedError: ErrInvalidMFACode,
+		},
+		{
+			name: "user no longer exists",
+			mockSetup: func(u *UserServiceMock, ur *UserRoleServiceMock, mf *MFAServiceMock, l *LoginAttemptServiceMock, r *repositories.RefreshTokenRepositoryMock, m *token.ManagerMock) {
+				mf.On("CompleteChallenge", mock.Anything, "challenge-token", "123456", "10.0.0.1").Return(testUserID, nil, nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(nil, ErrUserNotFound)
+			},
+			expectedError: ErrInvalidMFAChallenge,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			userServiceMock := NewUserServiceMock()
+			userRoleServiceMock := NewUserRoleServiceMock()
+			mfaServiceMock := NewMFAServiceMock()
+			loginAttemptMock := NewLoginAttemptServiceMock()
+			refreshTokenRepoMock := repositories.NewRefreshTokenRepositoryMock()
+			tokenManagerMock := token.NewManagerMock()
+			testCase.mockSetup(userServiceMock, userRoleServiceMock, mfaServiceMock, loginAttemptMock, refreshTokenRepoMock, tokenManagerMock)
+
+			service := NewAuthService(userServiceMock, userRoleServiceMock, mfaServiceMock, loginAttemptMock, refreshTokenRepoMock, tokenManagerMock)
+			response, err := service.VerifyMFA(context.Background(), request, "10.0.0.1")
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expected, response)
+			userServiceMock.AssertExpectations(t)
+			mfaServiceMock.AssertExpectations(t)
+			loginAttemptMock.AssertExpectations(t)
+			refreshTokenRepoMock.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"golang-template/mailer"
+	"golang-template/token"
+	"time"
+)
+
+var (
+	ErrInvalidVerificationToken = app.NewBadRequestError("invalid_verification_token", "invalid or expired verification token")
+	ErrEmailNotVerified         = app.NewForbiddenError("email_not_verified", "email address is not verified")
+)
+
+type EmailVerificationOptions struct {
+	TokenTTL time.Duration
+	// LinkURL is the page that receives the token as its token query
+	// parameter. Without it the mail contains the bare token.
+	LinkURL string
+}
+
+type EmailVerificationService interface {
+	Send(ctx context.Context, user *models.User) error
+	Verify(ctx context.Context, verificationToken string) error
+	Resend(ctx context.Context, email string) error
+}
+
+type emailVerificationService struct {
+	userRepository              repositories.UserRepository
+	emailVerificationRepository repositories.EmailVerificationRepository
+	mailer                      mailer.Mailer
+	options                     EmailVerificationOptions
+}
+
+func NewEmailVerificationService(
+	userRepository repositories.UserRepository,
+	emailVerificationRepository repositories.EmailVerificationRepository,
+	mailer mailer.Mailer,
+	options EmailVerificationOptions,
+) EmailVerificationService {
+	return &emailVerificationService{
+		userRepository:              userRepository,
+		emailVerificationRepository: emailVerificationRepository,
+		mailer:                      mailer,
+		options:                     options,
+	}
+}
+
+// Send mails a new verification token to the user's address. Earlier tokens
+// stay valid until one of them is used or they expire.
+func (s *emailVerificationService) Send(ctx context.Context, user *models.User) error {
+	ctx, span := tracer.Start(ctx, "emailVerificationService.Send")
+	defer span.End()
+
+	verificationToken, err := token.GenerateOpaque()
+	if err != nil {
+		return err
+	}
+
+	err = s.emailVerificationRepository.Create(ctx, &models.EmailVerificationToken{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: token.HashOpaque(verificationToken),
+		ExpiresAt: time.Now().Add(s.options.TokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body:    tokenMailBody(user.Username, "verify your email address", s.options.LinkURL, verificationToken, s.options.TokenTTL),
+	})
+}
+
+func (s *emailVerificationService) Verify(ctx context.Context, verificationToken string) error {
+	ctx, span := tracer.Start(ctx, "emailVerificationService.Verify")
+	defer span.End()
+
+	stored, err := s.emailVerificationRepository.GetByHash(ctx, token.HashOpaque(verificationToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return err
+	}
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return ErrInvalidVerificationToken
+	}
+
+	err = s.emailVerificationRepository.MarkVerified(ctx, stored.UserID, stored.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidVerificationToken
+	}
+	return err
+}
+
+// Resend sends a new token to an unverified user. Unknown and already
+// verified addresses succeed silently so the endpoint can't be used to find
+// out which addresses are registered.
+func (s *emailVerificationService) Resend(ctx context.Context, email string) error {
+	ctx, span := tracer.Start(ctx, "emailVerificationService.Resend")
+	defer span.End()
+
+	user, err := s.userRepository.GetByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.VerifiedAt != nil {
+		return nil
+	}
+	return s.Send(ctx, user)
+}
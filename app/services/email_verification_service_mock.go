@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type EmailVerificationServiceMock struct {
+	mock.Mock
+}
+
+func NewEmailVerificationServiceMock() *EmailVerificationServiceMock {
+	return &EmailVerificationServiceMock{}
+}
+
+func (m *EmailVerificationServiceMock) Send(ctx context.Context, user *models.User) error {
+	args := m.Mock.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *EmailVerificationServiceMock) Verify(ctx context.Context, verificationToken string) error {
+	args := m.Mock.Called(ctx, verificationToken)
+	return args.Error(0)
+}
+
+func (m *EmailVerificationServiceMock) Resend(ctx context.Context, email string) error {
+	args := m.Mock.Called(ctx, email)
+	return args.Error(0)
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"golang-template/mailer"
+	"golang-template/token"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEmailVerificationService_Send(t *testing.T) {
+	user := &models.User{ID: testUserID, Username: "testuser", Email: "test@example.com"}
+
+	testCaseList := []struct {
+		name          string
+		linkURL       string
+		mailError     error
+		expectedLink  string
+		expectedError error
+	}{
+		{name: "link", linkURL: "https://app.example.com/verify?lang=en", expectedLink: "https://app.example.com/verify?lang=en&token="},
+		{name: "bare token", linkURL: "", expectedLink: ""},
+		{name: "mail error", mailError: assert.AnError, expectedError: assert.AnError},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			userRepoMock := repositories.NewUserRepositoryMock()
+			verificationRepoMock := repositories.NewEmailVerificationRepositoryMock()
+			mailerMock := mailer.NewMailerMock()
+
+			var tokenHash string
+			verificationRepoMock.On("Create", mock.Anything, mock.MatchedBy(func(verificationToken *models.EmailVerificationToken) bool {
+				tokenHash = verificationToken.TokenHash
+				return verificationToken.UserID == testUserID && verificationToken.Email == "test@example.com" && time.Until(verificationToken.ExpiresAt) > 23*time.Hour
+			})).Return(nil)
+			var message mailer.Message
+			mailerMock.On("Send", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				message = args.Get(1).(mailer.Message)
+			}).Return(testCase.mailError)
+
+			service := NewEmailVerificationService(userRepoMock, verificationRepoMock, mailerMock, EmailVerificationOptions{
+				TokenTTL: 24 * time.Hour,
+				LinkURL:  testCase.linkURL,
+			})
+			err := service.Send(context.Background(), user)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, "test@example.com", message.To)
+			assert.Contains(t, message.Body, testCase.expectedLink)
+			// The mail carries the token whose hash was stored.
+			found := false
+			for _, word := range strings.Fields(strings.ReplaceAll(message.Body, "token=", "token= ")) {
+				if token.HashOpaque(word) == tokenHash {
+					found = true
+				}
+			}
+			assert.True(t, found)
+			verificationRepoMock.AssertExpectations(t)
+			mailerMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEmailVerificationService_Verify(t *testing.T) {
+	tokenHash := token.HashOpaque("verification-token")
+	usedAt := time.Now().Add(-time.Minute)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.EmailVerificationRepositoryMock)
+		expectedError error
+	}{
+		{
+			name: "successful verification",
+			mockSetup: func(r *repositories.EmailVerificationRepositoryMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(&models.EmailVerificationToken{UserID: testUserID, Email: "old@example.com", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+				r.On("MarkVerified", mock.Anything, testUserID, "old@example.com").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "address changed since sending",
+			mockSetup: func(r *repositories.EmailVerificationRepositoryMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(&models.EmailVerificationToken{UserID: testUserID, Email: "old@example.com", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+				r.On("MarkVerified", mock.Anything, testUserID, "old@example.com").Return(sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidVerificationToken,
+		},
+		{
+			name: "unknown token",
+			mockSetup: func(r *repositories.EmailVerificationRepositoryMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidVerificationToken,
+		},
+		{
+			name: "expired token",
+			mockSetup: func(r *repositories.EmailVerificationRepositoryMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(&models.EmailVerificationToken{UserID: testUserID, ExpiresAt: time.Now().Add(-time.Hour)}, nil)
+			},
+			expectedError: ErrInvalidVerificationToken,
+		},
+		{
+			name: "used token",
+			mockSetup: func(r *repositories.EmailVerificationRepositoryMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(&models.EmailVerificationToken{UserID: testUserID, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}, nil)
+			},
+			expectedError: ErrInvalidVerificationToken,
+		},
+		{
+			name: "used concurrently",
+			mockSetup: func(r *repositories.EmailVerificationRepositoryMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(&models.EmailVerificationToken{UserID: testUserID, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+				r.On("MarkVerified", mock.Anything, testUserID, "").Return(sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidVerificationToken,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			verificationRepoMock := repositories.NewEmailVerificationRepositoryMock()
+			testCase.mockSetup(verificationRepoMock)
+
+			service := NewEmailVerificationService(repositories.NewUserRepositoryMock(), verificationRepoMock, mailer.NewMailerMock(), EmailVerificationOptions{TokenTTL: time.Hour})
+			err := service.Verify(context.Background(), "verification-token")
+
+			assert.Equal(t, testCase.expectedError, err)
+			verificationRepoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEmailVerificationService_Resend(t *testing.T) {
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.UserRepositoryMock, *repositories.EmailVerificationRepositoryMock, *mailer.MailerMock)
+		expectedError error
+	}{
+		{
+			name: "unverified user",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.EmailVerificationRepositoryMock, m *mailer.MailerMock) {
+				u.On("GetByEmail", mock.Anything, "test@example.com").Return(&models.User{ID: testUserID, Email: "test@example.com"}, nil)
+				r.On("Create", mock.Anything, mock.Anything).Return(nil)
+				m.On("Send", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "verified user is ignored",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.EmailVerificationRepositoryMock, m *mailer.MailerMock) {
+				u.On("GetByEmail", mock.Anything, "test@example.com").Return(&models.User{ID: testUserID, VerifiedAt: &verifiedAt}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "unknown email is ignored",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.EmailVerificationRepositoryMock, m *mailer.MailerMock) {
+				u.On("GetByEmail", mock.Anything, "test@example.com").Return(nil, sql.ErrNoRows)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "repository error",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.EmailVerificationRepositoryMock, m *mailer.MailerMock) {
+				u.On("GetByEmail", mock.Anything, "test@example.com").Return(nil, assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			userRepoMock := repositories.NewUserRepositoryMock()
+			verificationRepoMock := repositories.NewEmailVerificationRepositoryMock()
+			mailerMock := mailer.NewMailerMock()
+			testCase.mockSetup(userRepoMock, verificationRepoMock, mailerMock)
+
+			service := NewEmailVerificationService(userRepoMock, verificationRepoMock, mailerMock, EmailVerificationOptions{TokenTTL: time.Hour})
+			err := service.Resend(context.Background(), "test@example.com")
+
+			assert.Equal(t, testCase.expectedError, err)
+			userRepoMock.AssertExpectations(t)
+			verificationRepoMock.AssertExpectations(t)
+			mailerMock.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"golang-template/audit"
+	"golang-template/logger"
+	"math"
+	"time"
+)
+
+var ErrLoginLocked = app.NewTooManyRequestsError("login_locked", "too many failed login attempts, try again later")
+
+const (
+	loginScopeUser = "user"
+	loginScopeIP   = "ip"
+)
+
+// LockoutOptions configures when failed logins lock an account or a client
+// IP. Every lockout of the same key lasts twice as long as the previous one,
+// from BaseDuration up to MaxDuration, until no login failed for ResetAfter.
+type LockoutOptions struct {
+	MaxFailures   int
+	IPMaxFailures int
+	BaseDuration  time.Duration
+	MaxDuration   time.Duration
+	ResetAfter    time.Duration
+}
+
+type LoginAttemptService interface {
+	Check(ctx context.Context, username string, ip string) error
+	RecordFailure(ctx context.Context, username string, ip string, reason string)
+	RecordSuccess(ctx context.Context, user *models.User, ip string)
+	Unlock(ctx context.Context, username string) error
+	Cleanup(ctx context.Context) (int64, error)
+}
+
+type loginAttemptService struct {
+	loginAttemptRepository repositories.LoginAttemptRepository
+	auditRecorder          audit.Recorder
+	options                LockoutOptions
+	now                    func() time.Time
+}
+
+func NewLoginAttemptService(
+	loginAttemptRepository repositories.LoginAttemptRepository,
+	auditRecorder audit.Recorder,
+	options LockoutOptions,
+) LoginAttemptService {
+	return &loginAttemptService{
+		loginAttemptRepository: loginAttemptRepository,
+		auditRecorder:          auditRecorder,
+		options:                options,
+		now:                    time.Now,
+	}
+}
+
+// Check returns ErrLoginLocked, with the seconds until the lockout ends in its
+// details, while the username or the IP is locked. Accounts are tracked by
+// the username that was tried, so unknown usernames lock the same way and
+// the response doesn't reveal which ones exist.
+func (s *loginAttemptService) Check(ctx context.Context, username string, ip string) error {
+	ctx, span := tracer.Start(ctx, "loginAttemptService.Check")
+	defer span.End()
+
+	now := s.now()
+	var lockedUntil time.Time
+	for _, scope := range []struct{ name, key string }{{loginScopeUser, username}, {loginScopeIP, ip}} {
+		attempt, err := s.loginAttemptRepository.Get(ctx, scope.name, scope.key)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			s.warn(ctx, err)
+			continue
+		}
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(lockedUntil) {
+			lockedUntil = *attempt.LockedUntil
+		}
+	}
+	if !lockedUntil.After(now) {
+		return nil
+	}
+
+	s.auditRecorder.Record(ctx, audit.Event{Type: audit.LoginFailed, Fields: logger.Fields{
+		"username": username,
+		"ip":       ip,
+		"reason":   "locked",
+	}})
+	retryAfter := int(math.Ceil(lockedUntil.Sub(now).Seconds()))
+	return ErrLoginLocked.WithDetails(models.LoginLockout{RetryAfter: retryAfter})
+}
+
+// RecordFailure counts a failed login against the username and the IP and
+// locks whichever reached its limit. Tracking errors are only logged so a
+// broken store doesn't block logins.
+func (s *loginAttemptService) RecordFailure(ctx context.Context, username string, ip string, reason string) {
+	ctx, span := tracer.Start(ctx, "loginAttemptService.RecordFailure")
+	defer span.End()
+
+	s.auditRecorder.Record(ctx, audit.Event{Type: audit.LoginFailed, Fields: logger.Fields{
+		"username": username,
+		"ip":       ip,
+		"reason":   reason,
+	}})
+
+	now := s.now()
+	for _, scope := range []struct {
+		name, key, field string
+		maxFailures      int
+	}{
+		{loginScopeUser, username, "username", s.options.MaxFailures},
+		{loginScopeIP, ip, "ip", s.options.IPMaxFailures},
+	} {
+		locked := false
+		attempt, err := s.loginAttemptRepository.Update(ctx, scope.name, scope.key, func(attempt *models.LoginAttempt) {
+			if now.Sub(attempt.LastFailedAt) >= s.options.ResetAfter {
+				attempt.Failures, attempt.Lockouts = 0, 0
+			}
+			attempt.Failures++
+			attempt.LastFailedAt = now
+			if attempt.Failures >= scope.maxFailures {
+				attempt.Failures = 0
+				attempt.Lockouts++
+				lockedUntil := now.Add(s.lockoutDuration(attempt.Lockouts))
+				attempt.LockedUntil = &lockedUntil
+				locked = true
+			}
+		})
+		if err != nil {
+			s.warn(ctx, err)
+			continue
+		}
+		if locked {
+			s.auditRecorder.Record(ctx, audit.Event{Type: audit.LoginLocked, Fields: logger.Fields{
+				scope.field:    scope.key,
+				"lockouts":     attempt.Lockouts,
+				"locked_until": attempt.LockedUntil.UTC().Format(time.RFC3339),
+			}})
+		}
+	}
+}
+
+// RecordSuccess clears the failures of the account. Those of the IP are kept,
+// one valid account must not let a client keep guessing others.
+func (s *loginAttemptService) RecordSuccess(ctx context.Context, user *models.User, ip string) {
+	ctx, span := tracer.Start(ctx, "loginAttemptService.RecordSuccess")
+	defer span.End()
+
+	s.auditRecorder.Record(ctx, audit.Event{Type: audit.LoginSucceeded, Fields: logger.Fields{
+		"user_id":  user.ID,
+		"username": user.Username,
+		"ip":       ip,
+	}})
+
+	err := s.loginAttemptRepository.Delete(ctx, loginScopeUser, user.Username)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.warn(ctx, err)
+	}
+}
+
+// Unlock lifts the lockout of the username and forgets its failures.
+func (s *loginAttemptService) Unlock(ctx context.Context, username string) error {
+	ctx, span := tracer.Start(ctx, "loginAttemptService.Unlock")
+	defer span.End()
+
+	err := s.loginAttemptRepository.Delete(ctx, loginScopeUser, username)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	s.auditRecorder.Record(ctx, audit.Event{Type: audit.AccountUnlocked, Fields: logger.Fields{"username": username}})
+	return nil
+}
+
+// Cleanup removes the attempts that would be reset by the next failure
+// anyway.
+func (s *loginAttemptService) Cleanup(ctx context.Context) (int64, error) {
+	ctx, span := tracer.Start(ctx, "loginAttemptService.Cleanup")
+	defer span.End()
+
+	deleted, err := s.loginAttemptRepository.DeleteStale(ctx, s.now().Add(-s.options.ResetAfter))
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		logger.FromContext(ctx).With(logger.Fields{"count": deleted}).Info("Deleted stale login attempts")
+	}
+	return deleted, nil
+}
+
+func (s *loginAttemptService) lockoutDuration(lockouts int) time.Duration {
+	duration := s.options.BaseDuration
+	for i := 1; i < lockouts && duration < s.options.MaxDuration; i++ {
+		duration *= 2
+	}
+	return min(duration, s.options.MaxDuration)
+}
+
+func (s *loginAttemptService) warn(ctx context.Context, err error) {
+	logger.FromContext(ctx).With(logger.Fields{"error": err.Error()}).Warn("Login attempt tracking failed")
+}
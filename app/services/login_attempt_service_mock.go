@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LoginAttemptServiceMock struct {
+	mock.Mock
+}
+
+func NewLoginAttemptServiceMock() *LoginAttemptServiceMock {
+	return &LoginAttemptServiceMock{}
+}
+
+func (m *LoginAttemptServiceMock) Check(ctx context.Context, username string, ip string) error {
+	args := m.Mock.Called(ctx, username, ip)
+	return args.Error(0)
+}
+
+func (m *LoginAttemptServiceMock) RecordFailure(ctx context.Context, username string, ip string, reason string) {
+	m.Mock.Called(ctx, username, ip, reason)
+}
+
+func (m *LoginAttemptServiceMock) RecordSuccess(ctx context.Context, user *models.User, ip string) {
+	m.Mock.Called(ctx, user, ip)
+}
+
+func (m *LoginAttemptServiceMock) Unlock(ctx context.Context, username string) error {
+	args := m.Mock.Called(ctx, username)
+	return args.Error(0)
+}
+
+func (m *LoginAttemptServiceMock) Cleanup(ctx context.Context) (int64, error) {
+	args := m.Mock.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
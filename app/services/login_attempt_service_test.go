@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"golang-template/audit"
+	"golang-template/logger"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testLockoutOptions = LockoutOptions{
+	MaxFailures:   3,
+	IPMaxFailures: 10,
+	BaseDuration:  time.Minute,
+	MaxDuration:   10 * time.Minute,
+	ResetAfter:    24 * time.Hour,
+}
+
+func newTestLoginAttemptService(repo repositories.LoginAttemptRepository, recorder audit.Recorder, now time.Time) *loginAttemptService {
+	service := NewLoginAttemptService(repo, recorder, testLockoutOptions).(*loginAttemptService)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func TestLoginAttemptService_Check(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	lockedUntil := now.Add(90 * time.Second)
+	expired := now.Add(-time.Second)
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.LoginAttemptRepositoryMock, *audit.RecorderMock)
+		expectedError error
+	}{
+		{
+			name: "no failures",
+			mockSetup: func(m *repositories.LoginAttemptRepositoryMock, a *audit.RecorderMock) {
+				m.On("Get", mock.Anything, "user", "alice").Return(nil, sql.ErrNoRows)
+				m.On("Get", mock.Anything, "ip", "10.0.0.1").Return(nil, sql.ErrNoRows)
+			},
+		},
+		{
+			name: "expired lockout",
+			mockSetup: func(m *repositories.LoginAttemptRepositoryMock, a *audit.RecorderMock) {
+				m.On("Get", mock.Anything, "user", "alice").Return(&models.LoginAttempt{Lockouts: 1, LockedUntil: &expired}, nil)
+				m.On("Get", mock.Anything, "ip", "10.0.0.1").Return(nil, sql.ErrNoRows)
+			},
+		},
+		{
+			name: "locked ip",
+			mockSetup: func(m *repositories.LoginAttemptRepositoryMock, a *audit.RecorderMock) {
+				m.On("Get", mock.Anything, "user", "alice").Return(nil, sql.ErrNoRows)
+				m.On("Get", mock.Anything, "ip", "10.0.0.1").Return(&models.LoginAttempt{Lockouts: 1, LockedUntil: &lockedUntil}, nil)
+				a.On("Record", mock.Anything, audit.Event{Type: audit.LoginFailed, Fields: logger.Fields{"username": "alice", "ip": "10.0.0.1", "reason": "locked"}})
+			},
+			expectedError: ErrLoginLocked.WithDetails(models.LoginLockout{RetryAfter: 90}),
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewLoginAttemptRepositoryMock()
+			recorderMock := audit.NewRecorderMock()
+			testCase.mockSetup(repoMock, recorderMock)
+
+			service := newTestLoginAttemptService(repoMock, recorderMock, now)
+			err := service.Check(context.Background(), "alice", "10.0.0.1")
+
+			assert.Equal(t, testCase.expectedError, err)
+			repoMock.AssertExpectations(t)
+			recorderMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoginAttemptService_Check_StoreError(t *testing.T) {
+	repoMock := repositories.NewLoginAttemptRepositoryMock()
+	repoMock.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+	loggerMock := logger.NewLoggerMock()
+	loggerMock.On("With", mock.Anything).Return(loggerMock)
+	loggerMock.On("Warn", "Login attempt tracking failed")
+	ctx := logger.WithContext(context.Background(), loggerMock)
+
+	service := newTestLoginAttemptService(repoMock, audit.NewRecorderMock(), time.Now())
+	err := service.Check(ctx, "alice", "10.0.0.1")
+
+	assert.NoError(t, err)
+	loggerMock.AssertExpectations(t)
+}
+
+func TestLoginAttemptService_RecordFailure(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Minute)
+	failedEvent := audit.Event{Type: audit.LoginFailed, Fields: logger.Fields{"username": "alice", "ip": "10.0.0.1", "reason": "invalid_password"}}
+
+	testCaseList := []struct {
+		name           string
+		userAttempt    *models.LoginAttempt
+		expectedLocked time.Duration
+	}{
+		{name: "first failure", userAttempt: nil},
+		{name: "below the limit", userAttempt: &models.LoginAttempt{Failures: 1, LastFailedAt: recent}},
+		{name: "first lockout", userAttempt: &models.LoginAttempt{Failures: 2, LastFailedAt: recent}, expectedLocked: time.Minute},
+		{name: "third lockout doubles twice", userAttempt: &models.LoginAttempt{Failures: 2, Lockouts: 2, LastFailedAt: recent}, expectedLocked: 4 * time.Minute},
+		{name: "lockout capped", userAttempt: &models.LoginAttempt{Failures: 2, Lockouts: 9, LastFailedAt: recent}, expectedLocked: 10 * time.Minute},
+		{name: "old failures reset", userAttempt: &models.LoginAttempt{Failures: 2, Lockouts: 3, LastFailedAt: now.Add(-25 * time.Hour)}},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewLoginAttemptRepositoryMock()
+			repoMock.On("Update", mock.Anything, "user", "alice", mock.Anything).Return(testCase.userAttempt, nil)
+			repoMock.On("Update", mock.Anything, "ip", "10.0.0.1", mock.Anything).Return(nil, nil)
+			recorderMock := audit.NewRecorderMock()
+			recorderMock.On("Record", mock.Anything, failedEvent)
+			if testCase.expectedLocked > 0 {
+				recorderMock.On("Record", mock.Anything, mock.MatchedBy(func(event audit.Event) bool {
+					return event.Type == audit.LoginLocked &&
+						event.Fields["username"] == "alice" &&
+						event.Fields["locked_until"] == now.Add(testCase.expectedLocked).Format(time.RFC3339)
+				}))
+			}
+
+			service := newTestLoginAttemptService(repoMock, recorderMock, now)
+			service.RecordFailure(context.Background(), "alice", "10.0.0.1", "invalid_password")
+
+			repoMock.AssertExpectations(t)
+			recorderMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoginAttemptService_RecordFailure_StoreError(t *testing.T) {
+	repoMock := repositories.NewLoginAttemptRepositoryMock()
+	repoMock.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+	recorderMock := audit.NewRecorderMock()
+	recorderMock.On("Record", mock.Anything, mock.Anything)
+	loggerMock := logger.NewLoggerMock()
+	loggerMock.On("With", mock.Anything).Return(loggerMock)
+	loggerMock.On("Warn", "Login attempt tracking failed")
+	ctx := logger.WithContext(context.Background(), loggerMock)
+
+	service := newTestLoginAttemptService(repoMock, recorderMock, time.Now())
+	service.RecordFailure(ctx, "alice", "10.0.0.1", "unknown_user")
+
+	repoMock.AssertNumberOfCalls(t, "Update", 2)
+	loggerMock.AssertExpectations(t)
+}
+
+func TestLoginAttemptService_RecordSuccess(t *testing.T) {
+	repoMock := repositories.NewLoginAttemptRepositoryMock()
+	repoMock.On("Delete", mock.Anything, "user", "alice").Return(sql.ErrNoRows)
+	recorderMock := audit.NewRecorderMock()
+	recorderMock.On("Record", mock.Anything, audit.Event{Type: audit.LoginSucceeded, Fields: logger.Fields{"user_id": testUserID, "username": "alice", "ip": "10.0.0.1"}})
+
+	service := newTestLoginAttemptService(repoMock, recorderMock, time.Now())
+	service.RecordSuccess(context.Background(), &models.User{ID: testUserID, Username: "alice"}, "10.0.0.1")
+
+	repoMock.AssertExpectations(t)
+	recorderMock.AssertExpectations(t)
+}
+
+func TestLoginAttemptService_Unlock(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		repoError     error
+		expectedError error
+	}{
+		{name: "locked account", repoError: nil},
+		{name: "account without failures", repoError: sql.ErrNoRows},
+		{name: "repository error", repoError: assert.AnError, expectedError: assert.AnError},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewLoginAttemptRepositoryMock()
+			repoMock.On("Delete", mock.Anything, "user", "alice").Return(testCase.repoError)
+			recorderMock := audit.NewRecorderMock()
+			if testCase.expectedError == nil {
+				recorderMock.On("Record", mock.Anything, audit.Event{Type: audit.AccountUnlocked, Fields: logger.Fields{"username": "alice"}})
+			}
+
+			service := newTestLoginAttemptService(repoMock, recorderMock, time.Now())
+			err := service.Unlock(context.Background(), "alice")
+
+			assert.Equal(t, testCase.expectedError, err)
+			repoMock.AssertExpectations(t)
+			recorderMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoginAttemptService_Cleanup(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	repoMock := repositories.NewLoginAttemptRepositoryMock()
+	repoMock.On("DeleteStale", mock.Anything, now.Add(-24*time.Hour)).Return(int64(4), nil)
+	loggerMock := logger.NewLoggerMock()
+	loggerMock.On("With", logger.Fields{"count": int64(4)}).Return(loggerMock)
+	loggerMock.On("Info", "Deleted stale login attempts")
+	ctx := logger.WithContext(context.Background(), loggerMock)
+
+	service := newTestLoginAttemptService(repoMock, audit.NewRecorderMock(), now)
+	deleted, err := service.Cleanup(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+	repoMock.AssertExpectations(t)
+	loggerMock.AssertExpectations(t)
+}
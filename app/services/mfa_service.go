@@ -0,0 +1,423 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"golang-template/audit"
+	"golang-template/hasher"
+	"golang-template/logger"
+	"golang-template/token"
+	"golang-template/totp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMFAAlreadyEnabled   = app.NewConflictError("mfa_already_enabled", "two-factor authentication is already enabled")
+	ErrMFANotEnrolled      = app.NewBadRequestError("mfa_not_enrolled", "two-factor enrollment has not been started")
+	ErrMFANotEnabled       = app.NewBadRequestError("mfa_not_enabled", "two-factor authentication is not enabled")
+	ErrInvalidMFACode      = app.NewBadRequestError("invalid_mfa_code", "invalid two-factor code")
+	ErrInvalidMFAChallenge = app.NewUnauthorizedError("invalid_mfa_challenge", "invalid or expired two-factor challenge")
+)
+
+const (
+	mfaRecoveryCodeCount = 10
+	// A challenge allows mfaMaxChallengeAttempts codes, so guessing needs
+	// the password again for every few tries.
+	mfaMaxChallengeAttempts = 5
+	// mfaSkew accepts the codes of the previous and next period to allow for
+	// clock drift.
+	mfaSkew       = 1
+	mfaQRCodeSize = 256
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type MFAOptions struct {
+	// Issuer names the account in authenticator apps.
+	Issuer       string
+	ChallengeTTL time.Duration
+	// RequiredRoles must enable two-factor authentication to log in.
+	RequiredRoles []string
+}
+
+type MFAService interface {
+	Enroll(ctx context.Context, userID string) (*models.MFAEnrollment, error)
+	Confirm(ctx context.Context, userID string, code string, ip string) (*models.MFARecoveryCodes, error)
+	Disable(ctx context.Context, userID string, password string, code string, ip string) error
+	Enabled(ctx context.Context, userID string) (bool, error)
+	Required(roles []string) bool
+	CreateChallenge(ctx context.Context, userID string) (string, time.Duration, error)
+	CompleteChallenge(ctx context.Context, challengeToken string, code string, ip string) (string, *models.MFARecoveryCodes, error)
+	Cleanup(ctx context.Context) (int64, error)
+}
+
+type mfaService struct {
+	userRepository      repositories.UserRepository
+	mfaRepository       repositories.MFARepository
+	passwordHasher      hasher.Hasher
+	loginAttemptService LoginAttemptService
+	auditRecorder       audit.Recorder
+	options             MFAOptions
+	now                 func() time.Time
+}
+
+func NewMFAService(
+	userRepository repositories.UserRepository,
+	mfaRepository repositories.MFARepository,
+	passwordHasher hasher.Hasher,
+	loginAttemptService LoginAttemptService,
+	auditRecorder audit.Recorder,
+	options MFAOptions,
+) MFAService {
+	return &mfaService{
+		userRepository:      userRepository,
+		mfaRepository:       mfaRepository,
+		passwordHasher:      passwordHasher,
+		loginAttemptService: loginAttemptService,
+		auditRecorder:       auditRecorder,
+		options:             options,
+		now:                 time.Now,
+	}
+}
+
+// Enroll generates a new secret for the user to add to an authenticator app.
+// It only takes effect after Confirm, so enrolling again before that replaces
+// the secret.
+func (s *mfaService) Enroll(ctx context.Context, userID string) (*models.MFAEnrollment, error) {
+	ctx, span := tracer.Start(ctx, "mfaService.Enroll")
+	defer span.End()
+
+	user, err := s.userRepository.GetByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	err = s.mfaRepository.SaveTOTP(ctx, &models.UserTOTP{UserID: userID, Secret: secret})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	uri := totp.URI(s.options.Issuer, user.Username, secret, totp.DefaultOptions)
+	qrCode, err := totp.QRCode(uri, mfaQRCodeSize)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MFAEnrollment{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCodePNG:  base64.StdEncoding.EncodeToString(qrCode),
+	}, nil
+}
+
+// Confirm enables two-factor authentication once the user entered a code of
+// the enrolled secret, and returns the recovery codes. Wrong codes count as
+// failed logins of the user from ip.
+func (s *mfaService) Confirm(ctx context.Context, userID string, code string, ip string) (*models.MFARecoveryCodes, error) {
+	ctx, span := tracer.Start(ctx, "mfaService.Confirm")
+	defer span.End()
+
+	user, err := s.userRepository.GetByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loginAttemptService.Check(ctx, user.Username, ip); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.mfaRepository.GetTOTP(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMFANotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stored.ConfirmedAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	recoveryCodes, err := s.enable(ctx, stored, code)
+	if errors.Is(err, ErrInvalidMFACode) {
+		s.loginAttemptService.RecordFailure(ctx, user.Username, ip, "invalid_mfa_code")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// Disable turns two-factor authentication off. It asks for the password and
+// a code so a stolen session alone can't remove the second factor, and counts
+// wrong ones as failed logins of the user from ip so they can't be guessed.
+func (s *mfaService) Disable(ctx context.Context, userID string, password string, code string, ip string) error {
+	ctx, span := tracer.Start(ctx, "mfaService.Disable")
+	defer span.End()
+
+	user, err := s.userRepository.GetByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.loginAttemptService.Check(ctx, user.Username, ip); err != nil {
+		return err
+	}
+	if err := s.passwordHasher.Verify(user.Password, password); err != nil {
+		if errors.Is(err, hasher.ErrMismatchedHash) || errors.Is(err, hasher.ErrUnknownHashFormat) {
+			s.loginAttemptService.RecordFailure(ctx, user.Username, ip, "invalid_password")
+			return ErrIncorrectPassword
+		}
+		return err
+	}
+
+	stored, err := s.mfaRepository.GetTOTP(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrMFANotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	if stored.ConfirmedAt == nil {
+		return ErrMFANotEnabled
+	}
+	err = s.useCode(ctx, stored, code)
+	if errors.Is(err, ErrInvalidMFACode) {
+		s.loginAttemptService.RecordFailure(ctx, user.Username, ip, "invalid_mfa_code")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.mfaRepository.Disable(ctx, userID); err != nil {
+		return err
+	}
+	s.auditRecorder.Record(ctx, audit.Event{Type: audit.MFADisabled, Fields: logger.Fields{"user_id": userID}})
+	return nil
+}
+
+// Required reports whether a user with roles must use two-factor
+// authentication.
+func (s *mfaService) Required(roles []string) bool {
+	for _, role := range roles {
+		if slices.Contains(s.options.RequiredRoles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *mfaService) Enabled(ctx context.Context, userID string) (bool, error) {
+	stored, err := s.mfaRepository.GetTOTP(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return stored.ConfirmedAt != nil, nil
+}
+
+// CreateChallenge starts the second step of a login after the password was
+// checked. It returns the token to complete it with and how long it is valid.
+func (s *mfaService) CreateChallenge(ctx context.Context, userID string) (string, time.Duration, error) {
+	ctx, span := tracer.Start(ctx, "mfaService.CreateChallenge")
+	defer span.End()
+
+	challengeToken, err := token.GenerateOpaque()
+	if err != nil {
+		return "", 0, err
+	}
+
+	err = s.mfaRepository.CreateChallenge(ctx, &models.MFAChallenge{
+		UserID:    userID,
+		TokenHash: token.HashOpaque(challengeToken),
+		ExpiresAt: s.now().Add(s.options.ChallengeTTL),
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return challengeToken, s.options.ChallengeTTL, nil
+}
+
+// CompleteChallenge checks the code, from the app or a recovery code, for a
+// challenge and returns the id of the user to sign in. When the user only
+// enrolled during the login, a code of the new secret enables two-factor
+// authentication and the recovery codes are returned as well. Wrong codes
+// count as failed logins of the user from ip, so logging in again for a new
+// challenge doesn't give unlimited guesses.
+func (s *mfaService) CompleteChallenge(ctx context.Context, challengeToken string, code string, ip string) (string, *models.MFARecoveryCodes, error) {
+	ctx, span := tracer.Start(ctx, "mfaService.CompleteChallenge")
+	defer span.End()
+
+	challenge, err := s.mfaRepository.GetChallengeByHash(ctx, token.HashOpaque(challengeToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if challenge.UsedAt != nil || challenge.Attempts >= mfaMaxChallengeAttempts || s.now().After(challenge.ExpiresAt) {
+		return "", nil, ErrInvalidMFAChallenge
+	}
+
+	user, err := s.userRepository.GetByID(ctx, challenge.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if err := s.loginAttemptService.Check(ctx, user.Username, ip); err != nil {
+		return "", nil, err
+	}
+
+	stored, err := s.mfaRepository.GetTOTP(ctx, challenge.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The attempt is counted before the code is checked, so parallel
+	// requests can't check more than mfaMaxChallengeAttempts codes.
+	err = s.mfaRepository.RecordChallengeAttempt(ctx, challenge.ID, mfaMaxChallengeAttempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	var recoveryCodes *models.MFARecoveryCodes
+	if stored.ConfirmedAt == nil {
+		recoveryCodes, err = s.enable(ctx, stored, code)
+	} else {
+		err = s.useCode(ctx, stored, code)
+	}
+	if errors.Is(err, ErrInvalidMFACode) {
+		s.auditRecorder.Record(ctx, audit.Event{Type: audit.MFAFailed, Fields: logger.Fields{"user_id": challenge.UserID}})
+		s.loginAttemptService.RecordFailure(ctx, user.Username, ip, "invalid_mfa_code")
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	err = s.mfaRepository.UseChallenge(ctx, challenge.ID, mfaMaxChallengeAttempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	s.auditRecorder.Record(ctx, audit.Event{Type: audit.MFAVerified, Fields: logger.Fields{"user_id": challenge.UserID}})
+	return challenge.UserID, recoveryCodes, nil
+}
+
+// Cleanup removes challenges that can no longer be completed.
+func (s *mfaService) Cleanup(ctx context.Context) (int64, error) {
+	ctx, span := tracer.Start(ctx, "mfaService.Cleanup")
+	defer span.End()
+
+	deleted, err := s.mfaRepository.DeleteExpiredChallenges(ctx, s.now())
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		logger.FromContext(ctx).With(logger.Fields{"count": deleted}).Info("Deleted expired MFA challenges")
+	}
+	return deleted, nil
+}
+
+// enable confirms the unconfirmed secret with a code of it and returns new
+// recovery codes.
+func (s *mfaService) enable(ctx context.Context, stored *models.UserTOTP, code string) (*models.MFARecoveryCodes, error) {
+	step, ok := totp.Validate(stored.Secret, code, s.now(), mfaSkew, totp.DefaultOptions)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = s.mfaRepository.Enable(ctx, stored.UserID, step, hashes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditRecorder.Record(ctx, audit.Event{Type: audit.MFAEnabled, Fields: logger.Fields{"user_id": stored.UserID}})
+	return &models.MFARecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// useCode accepts a code of the app that wasn't used before, or else an
+// unused recovery code, and returns ErrInvalidMFACode for anything else.
+func (s *mfaService) useCode(ctx context.Context, stored *models.UserTOTP, code string) error {
+	if step, ok := totp.Validate(stored.Secret, code, s.now(), mfaSkew, totp.DefaultOptions); ok {
+		err := s.mfaRepository.UseStep(ctx, stored.UserID, step)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidMFACode
+		}
+		return err
+	}
+
+	err := s.mfaRepository.UseRecoveryCode(ctx, stored.UserID, hashRecoveryCode(code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidMFACode
+	}
+	if err != nil {
+		return err
+	}
+	s.auditRecorder.Record(ctx, audit.Event{Type: audit.MFARecoveryCodeUsed, Fields: logger.Fields{"user_id": stored.UserID}})
+	return nil
+}
+
+// generateRecoveryCodes returns codes formatted like "abcd-efgh" and their
+// hashes.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, mfaRecoveryCodeCount)
+	hashes := make([]string, mfaRecoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		encoded := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))
+		codes[i] = encoded[:4] + "-" + encoded[4:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode ignores case, spaces and dashes so codes can be typed the
+// way they are read.
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	return token.HashOpaque(normalized)
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"golang-template/app/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MFAServiceMock struct {
+	mock.Mock
+}
+
+func NewMFAServiceMock() *MFAServiceMock {
+	return &MFAServiceMock{}
+}
+
+func (m *MFAServiceMock) Enroll(ctx context.Context, userID string) (*models.MFAEnrollment, error) {
+	args := m.Mock.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MFAEnrollment), args.Error(1)
+}
+
+func (m *MFAServiceMock) Confirm(ctx context.Context, userID string, code string, ip string) (*models.MFARecoveryCodes, error) {
+	args := m.Mock.Called(ctx, userID, code, ip)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MFARecoveryCodes), args.Error(1)
+}
+
+func (m *MFAServiceMock) Disable(ctx context.Context, userID string, password string, code string, ip string) error {
+	args := m.Mock.Called(ctx, userID, password, code, ip)
+	return args.Error(0)
+}
+
+func (m *MFAServiceMock) Enabled(ctx context.Context, userID string) (bool, error) {
+	args := m.Mock.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MFAServiceMock) Required(roles []string) bool {
+	args := m.Mock.Called(roles)
+	return args.Bool(0)
+}
+
+func (m *MFAServiceMock) CreateChallenge(ctx context.Context, userID string) (string, time.Duration, error) {
+	args := m.Mock.Called(ctx, userID)
+	return args.String(0), args.Get(1).(time.Duration), args.Error(2)
+}
+
+func (m *MFAServiceMock) CompleteChallenge(ctx context.Context, challengeToken string, code string, ip string) (string, *models.MFARecoveryCodes, error) {
+	args := m.Mock.Called(ctx, challengeToken, code, ip)
+	if args.Get(1) == nil {
+		return args.String(0), nil, args.Error(2)
+	}
+	return args.String(0), args.Get(1).(*models.MFARecoveryCodes), args.Error(2)
+}
+
+func (m *MFAServiceMock) Cleanup(ctx context.Context) (int64, error) {
+	args := m.Mock.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,553 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"golang-template/audit"
+	"golang-template/hasher"
+	"golang-template/logger"
+	"golang-template/token"
+	"golang-template/totp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+var testMFAOptions = MFAOptions{Issuer: "Example", ChallengeTTL: 5 * time.Minute}
+
+func newTestMFAService(u repositories.UserRepository, r repositories.MFARepository, h hasher.Hasher, l LoginAttemptService, a audit.Recorder, now time.Time) *mfaService {
+	service := NewMFAService(u, r, h, l, a, testMFAOptions).(*mfaService)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func testTOTPCode(t *testing.T, now time.Time) (string, int64) {
+	step := totp.Step(now, totp.DefaultOptions)
+	code, err := totp.Code(testTOTPSecret, step, totp.DefaultOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code, step
+}
+
+func TestMFAService_Enroll(t *testing.T) {
+	user := &models.User{ID: testUserID, Username: "alice"}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.UserRepositoryMock, *repositories.MFARepositoryMock)
+		expectedError error
+	}{
+		{
+			name: "new secret",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				r.On("SaveTOTP", mock.Anything, mock.MatchedBy(func(userTOTP *models.UserTOTP) bool {
+					return userTOTP.UserID == testUserID && len(userTOTP.Secret) == 32
+				})).Return(nil)
+			},
+		},
+		{
+			name: "already enabled",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				r.On("SaveTOTP", mock.Anything, mock.Anything).Return(sql.ErrNoRows)
+			},
+			expectedError: ErrMFAAlreadyEnabled,
+		},
+		{
+			name: "user not found",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			userRepoMock := repositories.NewUserRepositoryMock()
+			mfaRepoMock := repositories.NewMFARepositoryMock()
+			testCase.mockSetup(userRepoMock, mfaRepoMock)
+
+			service := newTestMFAService(userRepoMock, mfaRepoMock, hasher.NewHasherMock(), NewLoginAttemptServiceMock(), audit.NewRecorderMock(), time.Now())
+			enrollment, err := service.Enroll(context.Background(), testUserID)
+
+			assert.Equal(t, testCase.expectedError, err)
+			if testCase.expectedError == nil {
+				assert.Equal(t, totp.URI("Example", "alice", enrollment.Secret, totp.DefaultOptions), enrollment.OTPAuthURI)
+				png, err := base64.StdEncoding.DecodeString(enrollment.QRCodePNG)
+				assert.NoError(t, err)
+				assert.True(t, strings.HasPrefix(string(png), "\x89PNG"))
+			}
+			userRepoMock.AssertExpectations(t)
+			mfaRepoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMFAService_Confirm(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	code, step := testTOTPCode(t, now)
+	confirmedAt := now.Add(-time.Hour)
+	user := &models.User{ID: testUserID, Username: "alice"}
+
+	testCaseList := []struct {
+		name          string
+		code          string
+		mockSetup     func(*repositories.UserRepositoryMock, *repositories.MFARepositoryMock, *LoginAttemptServiceMock, *audit.RecorderMock)
+		expectedError error
+	}{
+		{
+			name: "valid code",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(&models.UserTOTP{UserID: testUserID, Secret: testTOTPSecret}, nil)
+				r.On("Enable", mock.Anything, testUserID, step, mock.MatchedBy(func(hashes []string) bool {
+					return len(hashes) == mfaRecoveryCodeCount
+				})).Return(nil)
+				a.On("Record", mock.Anything, audit.Event{Type: audit.MFAEnabled, Fields: logger.Fields{"user_id": testUserID}})
+			},
+		},
+		{
+			name: "invalid code",
+			code: "000000",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(&models.UserTOTP{UserID: testUserID, Secret: testTOTPSecret}, nil)
+				l.On("RecordFailure", mock.Anything, "alice", "127.0.0.1", "invalid_mfa_code")
+			},
+			expectedError: ErrInvalidMFACode,
+		},
+		{
+			name: "locked",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(ErrLoginLocked)
+			},
+			expectedError: ErrLoginLocked,
+		},
+		{
+			name: "not enrolled",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrMFANotEnrolled,
+		},
+		{
+			name: "already enabled",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(&models.UserTOTP{UserID: testUserID, Secret: testTOTPSecret, ConfirmedAt: &confirmedAt}, nil)
+			},
+			expectedError: ErrMFAAlreadyEnabled,
+		},
+		{
+			name: "user not found",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			userRepoMock := repositories.NewUserRepositoryMock()
+			mfaRepoMock := repositories.NewMFARepositoryMock()
+			loginAttemptMock := NewLoginAttemptServiceMock()
+			recorderMock := audit.NewRecorderMock()
+			testCase.mockSetup(userRepoMock, mfaRepoMock, loginAttemptMock, recorderMock)
+
+			service := newTestMFAService(userRepoMock, mfaRepoMock, hasher.NewHasherMock(), loginAttemptMock, recorderMock, now)
+			recoveryCodes, err := service.Confirm(context.Background(), testUserID, testCase.code, "127.0.0.1")
+
+			assert.Equal(t, testCase.expectedError, err)
+			if testCase.expectedError == nil {
+				assert.Len(t, recoveryCodes.RecoveryCodes, mfaRecoveryCodeCount)
+				assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, recoveryCodes.RecoveryCodes[0])
+			}
+			userRepoMock.AssertExpectations(t)
+			mfaRepoMock.AssertExpectations(t)
+			loginAttemptMock.AssertExpectations(t)
+			recorderMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMFAService_Disable(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	code, step := testTOTPCode(t, now)
+	confirmedAt := now.Add(-time.Hour)
+	user := &models.User{ID: testUserID, Username: "alice", Password: "hash"}
+	enabled := &models.UserTOTP{UserID: testUserID, Secret: testTOTPSecret, ConfirmedAt: &confirmedAt}
+
+	testCaseList := []struct {
+		name          string
+		password      string
+		code          string
+		mockSetup     func(*repositories.UserRepositoryMock, *repositories.MFARepositoryMock, *hasher.HasherMock, *LoginAttemptServiceMock, *audit.RecorderMock)
+		expectedError error
+	}{
+		{
+			name:     "valid password and code",
+			password: "Passw0rd1",
+			code:     code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				h.On("Verify", "hash", "Passw0rd1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(enabled, nil)
+				r.On("UseStep", mock.Anything, testUserID, step).Return(nil)
+				r.On("Disable", mock.Anything, testUserID).Return(nil)
+				a.On("Record", mock.Anything, audit.Event{Type: audit.MFADisabled, Fields: logger.Fields{"user_id": testUserID}})
+			},
+		},
+		{
+			name:     "incorrect password",
+			password: "wrong",
+			code:     code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				h.On("Verify", "hash", "wrong").Return(hasher.ErrMismatchedHash)
+				l.On("RecordFailure", mock.Anything, "alice", "127.0.0.1", "invalid_password")
+			},
+			expectedError: ErrIncorrectPassword,
+		},
+		{
+			name:     "invalid code",
+			password: "Passw0rd1",
+			code:     "000000",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				h.On("Verify", "hash", "Passw0rd1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(enabled, nil)
+				r.On("UseRecoveryCode", mock.Anything, testUserID, mock.Anything).Return(sql.ErrNoRows)
+				l.On("RecordFailure", mock.Anything, "alice", "127.0.0.1", "invalid_mfa_code")
+			},
+			expectedError: ErrInvalidMFACode,
+		},
+		{
+			name:     "replayed code",
+			password: "Passw0rd1",
+			code:     code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				h.On("Verify", "hash", "Passw0rd1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(enabled, nil)
+				r.On("UseStep", mock.Anything, testUserID, step).Return(sql.ErrNoRows)
+				l.On("RecordFailure", mock.Anything, "alice", "127.0.0.1", "invalid_mfa_code")
+			},
+			expectedError: ErrInvalidMFACode,
+		},
+		{
+			name:     "locked",
+			password: "Passw0rd1",
+			code:     code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(ErrLoginLocked)
+			},
+			expectedError: ErrLoginLocked,
+		},
+		{
+			name:     "not enabled",
+			password: "Passw0rd1",
+			code:     code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				h.On("Verify", "hash", "Passw0rd1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(&models.UserTOTP{UserID: testUserID, Secret: testTOTPSecret}, nil)
+			},
+			expectedError: ErrMFANotEnabled,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			userRepoMock := repositories.NewUserRepositoryMock()
+			mfaRepoMock := repositories.NewMFARepositoryMock()
+			hasherMock := hasher.NewHasherMock()
+			loginAttemptMock := NewLoginAttemptServiceMock()
+			recorderMock := audit.NewRecorderMock()
+			testCase.mockSetup(userRepoMock, mfaRepoMock, hasherMock, loginAttemptMock, recorderMock)
+
+			service := newTestMFAService(userRepoMock, mfaRepoMock, hasherMock, loginAttemptMock, recorderMock, now)
+			err := service.Disable(context.Background(), testUserID, testCase.password, testCase.code, "127.0.0.1")
+
+			assert.Equal(t, testCase.expectedError, err)
+			userRepoMock.AssertExpectations(t)
+			mfaRepoMock.AssertExpectations(t)
+			hasherMock.AssertExpectations(t)
+			loginAttemptMock.AssertExpectations(t)
+			recorderMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMFAService_Required(t *testing.T) {
+	service := NewMFAService(nil, nil, nil, nil, nil, MFAOptions{RequiredRoles: []string{"admin"}})
+
+	assert.True(t, service.Required([]string{"editor", "admin"}))
+	assert.False(t, service.Required([]string{"editor"}))
+	assert.False(t, service.Required(nil))
+}
+
+func TestMFAService_CreateChallenge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	mfaRepoMock := repositories.NewMFARepositoryMock()
+	var stored *models.MFAChallenge
+	mfaRepoMock.On("CreateChallenge", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*models.MFAChallenge)
+	}).Return(nil)
+
+	service := newTestMFAService(repositories.NewUserRepositoryMock(), mfaRepoMock, hasher.NewHasherMock(), NewLoginAttemptServiceMock(), audit.NewRecorderMock(), now)
+	challengeToken, expiresIn, err := service.CreateChallenge(context.Background(), testUserID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, expiresIn)
+	assert.Equal(t, testUserID, stored.UserID)
+	assert.Equal(t, token.HashOpaque(challengeToken), stored.TokenHash)
+	assert.Equal(t, now.Add(5*time.Minute), stored.ExpiresAt)
+}
+
+func TestMFAService_CompleteChallenge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	code, step := testTOTPCode(t, now)
+	confirmedAt := now.Add(-time.Hour)
+	tokenHash := token.HashOpaque("challenge-token")
+	enabled := &models.UserTOTP{UserID: testUserID, Secret: testTOTPSecret, ConfirmedAt: &confirmedAt}
+	userFields := logger.Fields{"user_id": testUserID}
+	user := &models.User{ID: testUserID, Username: "alice"}
+
+	challenge := func() *models.MFAChallenge {
+		return &models.MFAChallenge{ID: 4, UserID: testUserID, TokenHash: tokenHash, ExpiresAt: now.Add(time.Minute)}
+	}
+
+	testCaseList := []struct {
+		name           string
+		code           string
+		mockSetup      func(*repositories.UserRepositoryMock, *repositories.MFARepositoryMock, *LoginAttemptServiceMock, *audit.RecorderMock)
+		expectedUserID string
+		// expectedRecoveryCodes is set when the challenge enabled two-factor
+		// authentication.
+		expectedRecoveryCodes bool
+		expectedError         error
+	}{
+		{
+			name: "totp code",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(challenge(), nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(enabled, nil)
+				r.On("RecordChallengeAttempt", mock.Anything, int64(4), mfaMaxChallengeAttempts).Return(nil)
+				r.On("UseStep", mock.Anything, testUserID, step).Return(nil)
+				r.On("UseChallenge", mock.Anything, int64(4), mfaMaxChallengeAttempts).Return(nil)
+				a.On("Record", mock.Anything, audit.Event{Type: audit.MFAVerified, Fields: userFields})
+			},
+			expectedUserID: testUserID,
+		},
+		{
+			name: "recovery code",
+			code: "ABCD-EFGH",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(challenge(), nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(enabled, nil)
+				r.On("RecordChallengeAttempt", mock.Anything, int64(4), mfaMaxChallengeAttempts).Return(nil)
+				r.On("UseRecoveryCode", mock.Anything, testUserID, token.HashOpaque("abcdefgh")).Return(nil)
+				r.On("UseChallenge", mock.Anything, int64(4), mfaMaxChallengeAttempts).Return(nil)
+				a.On("Record", mock.Anything, audit.Event{Type: audit.MFARecoveryCodeUsed, Fields: userFields})
+				a.On("Record", mock.Anything, audit.Event{Type: audit.MFAVerified, Fields: userFields})
+			},
+			expectedUserID: testUserID,
+		},
+		{
+			name: "enrollment during login",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(challenge(), nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(&models.UserTOTP{UserID: testUserID, Secret: testTOTPSecret}, nil)
+				r.On("RecordChallengeAttempt", mock.Anything, int64(4), mfaMaxChallengeAttempts).Return(nil)
+				r.On("Enable", mock.Anything, testUserID, step, mock.Anything).Return(nil)
+				r.On("UseChallenge", mock.Anything, int64(4), mfaMaxChallengeAttempts).Return(nil)
+				a.On("Record", mock.Anything, audit.Event{Type: audit.MFAEnabled, Fields: userFields})
+				a.On("Record", mock.Anything, audit.Event{Type: audit.MFAVerified, Fields: userFields})
+			},
+			expectedUserID:        testUserID,
+			expectedRecoveryCodes: true,
+		},
+		{
+			name: "enrollment during login with a recovery code",
+			code: "ABCD-EFGH",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(challenge(), nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(&models.UserTOTP{UserID: testUserID, Secret: testTOTPSecret}, nil)
+				r.On("RecordChallengeAttempt", mock.Anything, int64(4), mfaMaxChallengeAttempts).Return(nil)
+				a.On("Record", mock.Anything, audit.Event{Type: audit.MFAFailed, Fields: userFields})
+				l.On("RecordFailure", mock.Anything, "alice", "127.0.0.1", "invalid_mfa_code")
+			},
+			expectedError: ErrInvalidMFACode,
+		},
+		{
+			name: "invalid code",
+			code: "000000",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(challenge(), nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(enabled, nil)
+				r.On("RecordChallengeAttempt", mock.Anything, int64(4), mfaMaxChallengeAttempts).Return(nil)
+				r.On("UseRecoveryCode", mock.Anything, testUserID, mock.Anything).Return(sql.ErrNoRows)
+				a.On("Record", mock.Anything, audit.Event{Type: audit.MFAFailed, Fields: userFields})
+				l.On("RecordFailure", mock.Anything, "alice", "127.0.0.1", "invalid_mfa_code")
+			},
+			expectedError: ErrInvalidMFACode,
+		},
+		{
+			name: "locked",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(challenge(), nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(ErrLoginLocked)
+			},
+			expectedError: ErrLoginLocked,
+		},
+		{
+			name: "user no longer exists",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(challenge(), nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidMFAChallenge,
+		},
+		{
+			name: "unknown challenge",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidMFAChallenge,
+		},
+		{
+			name: "expired challenge",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				expired := challenge()
+				expired.ExpiresAt = now.Add(-time.Second)
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(expired, nil)
+			},
+			expectedError: ErrInvalidMFAChallenge,
+		},
+		{
+			name: "too many attempts",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				exhausted := challenge()
+				exhausted.Attempts = mfaMaxChallengeAttempts
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(exhausted, nil)
+			},
+			expectedError: ErrInvalidMFAChallenge,
+		},
+		{
+			name: "attempts used up concurrently",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(challenge(), nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(enabled, nil)
+				r.On("RecordChallengeAttempt", mock.Anything, int64(4), mfaMaxChallengeAttempts).Return(sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidMFAChallenge,
+		},
+		{
+			name: "challenge used concurrently",
+			code: code,
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.MFARepositoryMock, l *LoginAttemptServiceMock, a *audit.RecorderMock) {
+				r.On("GetChallengeByHash", mock.Anything, tokenHash).Return(challenge(), nil)
+				u.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+				l.On("Check", mock.Anything, "alice", "127.0.0.1").Return(nil)
+				r.On("GetTOTP", mock.Anything, testUserID).Return(enabled, nil)
+				r.On("RecordChallengeAttempt", mock.Anything, int64(4), mfaMaxChallengeAttempts).Return(nil)
+				r.On("UseStep", mock.Anything, testUserID, step).Return(nil)
+				r.On("UseChallenge", mock.Anything, int64(4), mfaMaxChallengeAttempts).Return(sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidMFAChallenge,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			userRepoMock := repositories.NewUserRepositoryMock()
+			mfaRepoMock := repositories.NewMFARepositoryMock()
+			loginAttemptMock := NewLoginAttemptServiceMock()
+			recorderMock := audit.NewRecorderMock()
+			testCase.mockSetup(userRepoMock, mfaRepoMock, loginAttemptMock, recorderMock)
+
+			service := newTestMFAService(userRepoMock, mfaRepoMock, hasher.NewHasherMock(), loginAttemptMock, recorderMock, now)
+			userID, recoveryCodes, err := service.CompleteChallenge(context.Background(), "challenge-token", testCase.code, "127.0.0.1")
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedUserID, userID)
+			if testCase.expectedRecoveryCodes {
+				assert.Len(t, recoveryCodes.RecoveryCodes, mfaRecoveryCodeCount)
+			} else {
+				assert.Nil(t, recoveryCodes)
+			}
+			userRepoMock.AssertExpectations(t)
+			mfaRepoMock.AssertExpectations(t)
+			loginAttemptMock.AssertExpectations(t)
+			recorderMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMFAService_Cleanup(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	mfaRepoMock := repositories.NewMFARepositoryMock()
+	mfaRepoMock.On("DeleteExpiredChallenges", mock.Anything, now).Return(int64(3), nil)
+	loggerMock := logger.NewLoggerMock()
+	loggerMock.On("With", logger.Fields{"count": int64(3)}).Return(loggerMock)
+	loggerMock.On("Info", "Deleted expired MFA challenges")
+	ctx := logger.WithContext(context.Background(), loggerMock)
+
+	service := newTestMFAService(repositories.NewUserRepositoryMock(), mfaRepoMock, hasher.NewHasherMock(), NewLoginAttemptServiceMock(), audit.NewRecorderMock(), now)
+	deleted, err := service.Cleanup(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	mfaRepoMock.AssertExpectations(t)
+	loggerMock.AssertExpectations(t)
+}
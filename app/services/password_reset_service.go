@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"golang-template/hasher"
+	"golang-template/logger"
+	"golang-template/mailer"
+	"golang-template/token"
+	"sync"
+	"time"
+)
+
+var ErrInvalidResetToken = app.NewBadRequestError("invalid_reset_token", "invalid or expired password reset token")
+
+// passwordResetMailTimeout bounds a reset mail sent after the request that
+// asked for it has been answered.
+const passwordResetMailTimeout = 30 * time.Second
+
+type PasswordResetOptions struct {
+	TokenTTL time.Duration
+	// LinkURL is the page that receives the token as its token query
+	// parameter. Without it the mail contains the bare token.
+	LinkURL string
+}
+
+type PasswordResetService interface {
+	Forgot(ctx context.Context, email string) error
+	Reset(ctx context.Context, resetToken string, newPassword string) error
+	// Wait blocks until the reset mails still being sent are done.
+	Wait(ctx context.Context) error
+}
+
+type passwordResetService struct {
+	userRepository          repositories.UserRepository
+	passwordResetRepository repositories.PasswordResetRepository
+	passwordHasher          hasher.Hasher
+	mailer                  mailer.Mailer
+	options                 PasswordResetOptions
+	mails                   sync.WaitGroup
+}
+
+func NewPasswordResetService(
+	userRepository repositories.UserRepository,
+	passwordResetRepository repositories.PasswordResetRepository,
+	passwordHasher hasher.Hasher,
+	mailer mailer.Mailer,
+	options PasswordResetOptions,
+) PasswordResetService {
+	return &passwordResetService{
+		userRepository:          userRepository,
+		passwordResetRepository: passwordResetRepository,
+		passwordHasher:          passwordHasher,
+		mailer:                  mailer,
+		options:                 options,
+	}
+}
+
+// Forgot mails a reset token to the user with the address. Unknown addresses
+// succeed silently so the endpoint can't be used to find out which addresses
+// are registered. The mail is sent in the background, as waiting for it would
+// make registered addresses answer noticeably slower.
+func (s *passwordResetService) Forgot(ctx context.Context, email string) error {
+	ctx, span := tracer.Start(ctx, "passwordResetService.Forgot")
+	defer span.End()
+
+	user, err := s.userRepository.GetByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	resetToken, err := token.GenerateOpaque()
+	if err != nil {
+		return err
+	}
+
+	err = s.passwordResetRepository.Create(ctx, &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: token.HashOpaque(resetToken),
+		ExpiresAt: time.Now().Add(s.options.TokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.send(ctx, user, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body:    tokenMailBody(user.Username, "reset your password", s.options.LinkURL, resetToken, s.options.TokenTTL),
+	})
+	return nil
+}
+
+// send mails message without waiting for it. The request context is
+// detached, so the mail isn't cancelled once the response is written, but
+// keeps its logger and trace. Failures are only logged, the user can ask
+// for another token.
+func (s *passwordResetService) send(ctx context.Context, user *models.User, message mailer.Message) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), passwordResetMailTimeout)
+	s.mails.Add(1)
+	go func() {
+		defer s.mails.Done()
+		defer cancel()
+		if err := s.mailer.Send(ctx, message); err != nil {
+			logger.FromContext(ctx).With(logger.Fields{"user_id": user.ID, "error": err.Error()}).Warn("Password reset email failed")
+		}
+	}()
+}
+
+func (s *passwordResetService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.mails.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Reset sets the new password and signs the user out of every session.
+func (s *passwordResetService) Reset(ctx context.Context, resetToken string, newPassword string) error {
+	ctx, span := tracer.Start(ctx, "passwordResetService.Reset")
+	defer span.End()
+
+	stored, err := s.passwordResetRepository.GetByHash(ctx, token.HashOpaque(resetToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	hash, err := s.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	err = s.passwordResetRepository.Reset(ctx, stored, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).With(logger.Fields{"user_id": stored.UserID}).Info("Password reset, sessions revoked")
+	return nil
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PasswordResetServiceMock struct {
+	mock.Mock
+}
+
+func NewPasswordResetServiceMock() *PasswordResetServiceMock {
+	return &PasswordResetServiceMock{}
+}
+
+func (m *PasswordResetServiceMock) Forgot(ctx context.Context, email string) error {
+	args := m.Mock.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *PasswordResetServiceMock) Reset(ctx context.Context, resetToken string, newPassword string) error {
+	args := m.Mock.Called(ctx, resetToken, newPassword)
+	return args.Error(0)
+}
+
+func (m *PasswordResetServiceMock) Wait(ctx context.Context) error {
+	args := m.Mock.Called(ctx)
+	return args.Error(0)
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"golang-template/hasher"
+	"golang-template/logger"
+	"golang-template/mailer"
+	"golang-template/token"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPasswordResetService_Forgot(t *testing.T) {
+	user := &models.User{ID: testUserID, Username: "testuser", Email: "test@example.com"}
+
+	testCaseList := []struct {
+		name            string
+		mockSetup       func(*repositories.UserRepositoryMock, *repositories.PasswordResetRepositoryMock, *mailer.MailerMock)
+		expectedError   error
+		expectedWarning string
+	}{
+		{
+			name: "mails a token",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.PasswordResetRepositoryMock, m *mailer.MailerMock) {
+				u.On("GetByEmail", mock.Anything, "test@example.com").Return(user, nil)
+				var tokenHash string
+				r.On("Create", mock.Anything, mock.MatchedBy(func(resetToken *models.PasswordResetToken) bool {
+					tokenHash = resetToken.TokenHash
+					return resetToken.UserID == testUserID && time.Until(resetToken.ExpiresAt) > 59*time.Minute
+				})).Return(nil)
+				m.On("Send", mock.Anything, mock.MatchedBy(func(message mailer.Message) bool {
+					rawToken := message.Body[strings.Index(message.Body, "token=")+len("token="):]
+					rawToken = strings.Fields(rawToken)[0]
+					return message.To == "test@example.com" && token.HashOpaque(rawToken) == tokenHash
+				})).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "unknown email is ignored",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.PasswordResetRepositoryMock, m *mailer.MailerMock) {
+				u.On("GetByEmail", mock.Anything, "test@example.com").Return(nil, sql.ErrNoRows)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "mail error",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.PasswordResetRepositoryMock, m *mailer.MailerMock) {
+				u.On("GetByEmail", mock.Anything, "test@example.com").Return(user, nil)
+				r.On("Create", mock.Anything, mock.Anything).Return(nil)
+				m.On("Send", mock.Anything, mock.Anything).Return(assert.AnError)
+			},
+			expectedError:   nil,
+			expectedWarning: "Password reset email failed",
+		},
+		{
+			name: "create error",
+			mockSetup: func(u *repositories.UserRepositoryMock, r *repositories.PasswordResetRepositoryMock, m *mailer.MailerMock) {
+				u.On("GetByEmail", mock.Anything, "test@example.com").Return(user, nil)
+				r.On("Create", mock.Anything, mock.Anything).Return(assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			userRepoMock := repositories.NewUserRepositoryMock()
+			resetRepoMock := repositories.NewPasswordResetRepositoryMock()
+			mailerMock := mailer.NewMailerMock()
+			loggerMock := logger.NewLoggerMock()
+			testCase.mockSetup(userRepoMock, resetRepoMock, mailerMock)
+			if testCase.expectedWarning != "" {
+				loggerMock.On("With", mock.Anything).Return(loggerMock)
+				loggerMock.On("Warn", testCase.expectedWarning)
+			}
+			ctx := logger.WithContext(context.Background(), loggerMock)
+
+			service := NewPasswordResetService(userRepoMock, resetRepoMock, hasher.NewHasherMock(), mailerMock, PasswordResetOptions{
+				TokenTTL: time.Hour,
+				LinkURL:  "https://app.example.com/reset-password",
+			})
+			err := service.Forgot(ctx, "test@example.com")
+			assert.NoError(t, service.Wait(context.Background()))
+
+			assert.Equal(t, testCase.expectedError, err)
+			userRepoMock.AssertExpectations(t)
+			resetRepoMock.AssertExpectations(t)
+			mailerMock.AssertExpectations(t)
+			loggerMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPasswordResetService_Reset(t *testing.T) {
+	tokenHash := token.HashOpaque("reset-token")
+	usedAt := time.Now().Add(-time.Minute)
+	activeToken := &models.PasswordResetToken{ID: 4, UserID: testUserID, ExpiresAt: time.Now().Add(time.Hour)}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.PasswordResetRepositoryMock, *hasher.HasherMock)
+		expectedError error
+	}{
+		{
+			name: "successful reset",
+			mockSetup: func(r *repositories.PasswordResetRepositoryMock, h *hasher.HasherMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(activeToken, nil)
+				h.On("Hash", "NewPassw0rd").Return("new-hash", nil)
+				r.On("Reset", mock.Anything, activeToken, "new-hash").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "unknown token",
+			mockSetup: func(r *repositories.PasswordResetRepositoryMock, h *hasher.HasherMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidResetToken,
+		},
+		{
+			name: "expired token",
+			mockSetup: func(r *repositories.PasswordResetRepositoryMock, h *hasher.HasherMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(&models.PasswordResetToken{UserID: testUserID, ExpiresAt: time.Now().Add(-time.Second)}, nil)
+			},
+			expectedError: ErrInvalidResetToken,
+		},
+		{
+			name: "used token",
+			mockSetup: func(r *repositories.PasswordResetRepositoryMock, h *hasher.HasherMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(&models.PasswordResetToken{UserID: testUserID, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}, nil)
+			},
+			expectedError: ErrInvalidResetToken,
+		},
+		{
+			name: "used concurrently",
+			mockSetup: func(r *repositories.PasswordResetRepositoryMock, h *hasher.HasherMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(activeToken, nil)
+				h.On("Hash", "NewPassw0rd").Return("new-hash", nil)
+				r.On("Reset", mock.Anything, activeToken, "new-hash").Return(sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidResetToken,
+		},
+		{
+			name: "repository error",
+			mockSetup: func(r *repositories.PasswordResetRepositoryMock, h *hasher.HasherMock) {
+				r.On("GetByHash", mock.Anything, tokenHash).Return(activeToken, nil)
+				h.On("Hash", "NewPassw0rd").Return("new-hash", nil)
+				r.On("Reset", mock.Anything, activeToken, "new-hash").Return(assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			resetRepoMock := repositories.NewPasswordResetRepositoryMock()
+			hasherMock := hasher.NewHasherMock()
+			testCase.mockSetup(resetRepoMock, hasherMock)
+
+			service := NewPasswordResetService(repositories.NewUserRepositoryMock(), resetRepoMock, hasherMock, mailer.NewMailerMock(), PasswordResetOptions{TokenTTL: time.Hour})
+			err := service.Reset(context.Background(), "reset-token", "NewPassw0rd")
+
+			assert.Equal(t, testCase.expectedError, err)
+			resetRepoMock.AssertExpectations(t)
+			hasherMock.AssertExpectations(t)
+		})
+	}
+}
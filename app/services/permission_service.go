@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+)
+
+var (
+	ErrPermissionNotFound      = app.NewNotFoundError("permission_not_found", "permission not found")
+	ErrPermissionAlreadyExists = app.NewConflictError("permission_already_exists", "permission already exists")
+)
+
+type PermissionService interface {
+	Create(ctx context.Context, permission *models.PermissionCreate) (*models.Permission, error)
+	GetByID(ctx context.Context, id int64) (*models.Permission, error)
+	List(ctx context.Context) (*[]models.Permission, error)
+	Update(ctx context.Context, id int64, permission *models.PermissionUpdate) (*models.Permission, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type permissionService struct {
+	permissionRepository repositories.PermissionRepository
+}
+
+func NewPermissionService(permissionRepository repositories.PermissionRepository) PermissionService {
+	return &permissionService{permissionRepository: permissionRepository}
+}
+
+func (s *permissionService) Create(ctx context.Context, permission *models.PermissionCreate) (*models.Permission, error) {
+	_, err := s.permissionRepository.GetByName(ctx, models.PermissionName(permission.Resource, permission.Action))
+	if err == nil {
+		return nil, ErrPermissionAlreadyExists
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	created, err := s.permissionRepository.Create(ctx, permission)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return nil, ErrPermissionAlreadyExists
+	}
+	return created, err
+}
+
+func (s *permissionService) GetByID(ctx context.Context, id int64) (*models.Permission, error) {
+	permission, err := s.permissionRepository.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPermissionNotFound
+	}
+	return permission, err
+}
+
+func (s *permissionService) List(ctx context.Context) (*[]models.Permission, error) {
+	return s.permissionRepository.List(ctx)
+}
+
+func (s *permissionService) Update(ctx context.Context, id int64, permission *models.PermissionUpdate) (*models.Permission, error) {
+	updated, err := s.permissionRepository.Update(ctx, id, permission)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPermissionNotFound
+	}
+	return updated, err
+}
+
+func (s *permissionService) Delete(ctx context.Context, id int64) error {
+	err := s.permissionRepository.Delete(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPermissionNotFound
+	}
+	return err
+}
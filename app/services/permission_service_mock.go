@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PermissionServiceMock struct {
+	mock.Mock
+}
+
+func NewPermissionServiceMock() *PermissionServiceMock {
+	return &PermissionServiceMock{}
+}
+
+func (m *PermissionServiceMock) Create(ctx context.Context, permission *models.PermissionCreate) (*models.Permission, error) {
+	args := m.Mock.Called(ctx, permission)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Permission), args.Error(1)
+}
+
+func (m *PermissionServiceMock) GetByID(ctx context.Context, id int64) (*models.Permission, error) {
+	args := m.Mock.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Permission), args.Error(1)
+}
+
+func (m *PermissionServiceMock) List(ctx context.Context) (*[]models.Permission, error) {
+	args := m.Mock.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*[]models.Permission), args.Error(1)
+}
+
+func (m *PermissionServiceMock) Update(ctx context.Context, id int64, permission *models.PermissionUpdate) (*models.Permission, error) {
+	args := m.Mock.Called(ctx, id, permission)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Permission), args.Error(1)
+}
+
+func (m *PermissionServiceMock) Delete(ctx context.Context, id int64) error {
+	args := m.Mock.Called(ctx, id)
+	return args.Error(0)
+}
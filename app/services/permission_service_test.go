@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPermissionService_Create(t *testing.T) {
+	data := &models.PermissionCreate{Resource: "user", Action: "write"}
+	permission := &models.Permission{ID: 1, Name: "user:write", Resource: "user", Action: "write"}
+
+	testCaseList := []struct {
+		name               string
+		mockSetup          func(*repositories.PermissionRepositoryMock)
+		expectedPermission *models.Permission
+		expectedError      error
+	}{
+		{
+			name: "successful creation",
+			mockSetup: func(m *repositories.PermissionRepositoryMock) {
+				m.On("GetByName", mock.Anything, "user:write").Return(nil, sql.ErrNoRows)
+				m.On("Create", mock.Anything, data).Return(permission, nil)
+			},
+			expectedPermission: permission,
+			expectedError:      nil,
+		},
+		{
+			name: "duplicate permission",
+			mockSetup: func(m *repositories.PermissionRepositoryMock) {
+				m.On("GetByName", mock.Anything, "user:write").Return(permission, nil)
+			},
+			expectedPermission: nil,
+			expectedError:      ErrPermissionAlreadyExists,
+		},
+		{
+			name: "repository error",
+			mockSetup: func(m *repositories.PermissionRepositoryMock) {
+				m.On("GetByName", mock.Anything, "user:write").Return(nil, sql.ErrNoRows)
+				m.On("Create", mock.Anything, data).Return(nil, assert.AnError)
+			},
+			expectedPermission: nil,
+			expectedError:      assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewPermissionRepositoryMock()
+			testCase.mockSetup(repoMock)
+
+			service := NewPermissionService(repoMock)
+			permission, err := service.Create(context.Background(), data)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPermission, permission)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPermissionService_GetByID(t *testing.T) {
+	permission := &models.Permission{ID: 1, Name: "user:write"}
+
+	testCaseList := []struct {
+		name               string
+		mockSetup          func(*repositories.PermissionRepositoryMock)
+		expectedPermission *models.Permission
+		expectedError      error
+	}{
+		{
+			name: "permission found",
+			mockSetup: func(m *repositories.PermissionRepositoryMock) {
+				m.On("GetByID", mock.Anything, int64(1)).Return(permission, nil)
+			},
+			expectedPermission: permission,
+			expectedError:      nil,
+		},
+		{
+			name: "permission not found",
+			mockSetup: func(m *repositories.PermissionRepositoryMock) {
+				m.On("GetByID", mock.Anything, int64(1)).Return(nil, sql.ErrNoRows)
+			},
+			expectedPermission: nil,
+			expectedError:      ErrPermissionNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewPermissionRepositoryMock()
+			testCase.mockSetup(repoMock)
+
+			service := NewPermissionService(repoMock)
+			permission, err := service.GetByID(context.Background(), 1)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPermission, permission)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPermissionService_List(t *testing.T) {
+	permissions := &[]models.Permission{{ID: 1, Name: "user:write"}}
+
+	testCaseList := []struct {
+		name                string
+		mockSetup           func(*repositories.PermissionRepositoryMock)
+		expectedPermissions *[]models.Permission
+		expectedError       error
+	}{
+		{
+			name: "successful list",
+			mockSetup: func(m *repositories.PermissionRepositoryMock) {
+				m.On("List", mock.Anything).Return(permissions, nil)
+			},
+			expectedPermissions: permissions,
+			expectedError:       nil,
+		},
+		{
+			name: "repository error",
+			mockSetup: func(m *repositories.PermissionRepositoryMock) {
+				m.On("List", mock.Anything).Return(nil, assert.AnError)
+			},
+			expectedPermissions: nil,
+			expectedError:       assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewPermissionRepositoryMock()
+			testCase.mockSetup(repoMock)
+
+			service := NewPermissionService(repoMock)
+			permissions, err := service.List(context.Background())
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPermissions, permissions)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPermissionService_Update(t *testing.T) {
+	update := &models.PermissionUpdate{Description: "Manage users"}
+	updated := &models.Permission{ID: 1, Name: "user:write", Description: "Manage users"}
+
+	testCaseList := []struct {
+		name               string
+		mockSetup          func(*repositories.PermissionRepositoryMock)
+		expectedPermission *models.Permission
+		expectedError      error
+	}{
+		{
+			name: "successful update",
+			mockSetup: func(m *repositories.PermissionRepositoryMock) {
+				m.On("Update", mock.Anything, int64(1), update).Return(updated, nil)
+			},
+			expectedPermission: updated,
+			expectedError:      nil,
+		},
+		{
+			name: "permission not found",
+			mockSetup: func(m *repositories.PermissionRepositoryMock) {
+				m.On("Update", mock.Anything, int64(1), update).Return(nil, sql.ErrNoRows)
+			},
+			expectedPermission: nil,
+			expectedError:      ErrPermissionNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewPermissionRepositoryMock()
+			testCase.mockSetup(repoMock)
+
+			service := NewPermissionService(repoMock)
+			permission, err := service.Update(context.Background(), 1, update)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPermission, permission)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPermissionService_Delete(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.PermissionRepositoryMock)
+		expectedError error
+	}{
+		{
+			name: "successful delete",
+			mockSetup: func(m *repositories.PermissionRepositoryMock) {
+				m.On("Delete", mock.Anything, int64(1)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "permission not found",
+			mockSetup: func(m *repositories.PermissionRepositoryMock) {
+				m.On("Delete", mock.Anything, int64(1)).Return(sql.ErrNoRows)
+			},
+			expectedError: ErrPermissionNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewPermissionRepositoryMock()
+			testCase.mockSetup(repoMock)
+
+			service := NewPermissionService(repoMock)
+			err := service.Delete(context.Background(), 1)
+
+			assert.Equal(t, testCase.expectedError, err)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
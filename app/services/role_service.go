@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+)
+
+var (
+	ErrRoleNotFound      = app.NewNotFoundError("role_not_found", "role not found")
+	ErrRoleAlreadyExists = app.NewConflictError("role_already_exists", "role already exists")
+)
+
+type RoleService interface {
+	Create(ctx context.Context, role *models.RoleCreate) (*models.Role, error)
+	GetByID(ctx context.Context, id int64) (*models.Role, error)
+	List(ctx context.Context) (*[]models.Role, error)
+	Update(ctx context.Context, id int64, role *models.RoleUpdate) (*models.Role, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type roleService struct {
+	roleRepository repositories.RoleRepository
+}
+
+func NewRoleService(roleRepository repositories.RoleRepository) RoleService {
+	return &roleService{roleRepository: roleRepository}
+}
+
+func (s *roleService) Create(ctx context.Context, role *models.RoleCreate) (*models.Role, error) {
+	_, err := s.roleRepository.GetByName(ctx, role.Name)
+	if err == nil {
+		return nil, ErrRoleAlreadyExists
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	created, err := s.roleRepository.Create(ctx, role)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return nil, ErrRoleAlreadyExists
+	}
+	return created, err
+}
+
+func (s *roleService) GetByID(ctx context.Context, id int64) (*models.Role, error) {
+	role, err := s.roleRepository.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRoleNotFound
+	}
+	return role, err
+}
+
+func (s *roleService) List(ctx context.Context) (*[]models.Role, error) {
+	return s.roleRepository.List(ctx)
+}
+
+func (s *roleService) Update(ctx context.Context, id int64, role *models.RoleUpdate) (*models.Role, error) {
+	existing, err := s.roleRepository.GetByName(ctx, role.Name)
+	if err == nil && existing.ID != id {
+		return nil, ErrRoleAlreadyExists
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	updated, err := s.roleRepository.Update(ctx, id, role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRoleNotFound
+	}
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return nil, ErrRoleAlreadyExists
+	}
+	return updated, err
+}
+
+func (s *roleService) Delete(ctx context.Context, id int64) error {
+	err := s.roleRepository.Delete(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRoleNotFound
+	}
+	return err
+}
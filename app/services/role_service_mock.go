@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type RoleServiceMock struct {
+	mock.Mock
+}
+
+func NewRoleServiceMock() *RoleServiceMock {
+	return &RoleServiceMock{}
+}
+
+func (m *RoleServiceMock) Create(ctx context.Context, role *models.RoleCreate) (*models.Role, error) {
+	args := m.Mock.Called(ctx, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *RoleServiceMock) GetByID(ctx context.Context, id int64) (*models.Role, error) {
+	args := m.Mock.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *RoleServiceMock) List(ctx context.Context) (*[]models.Role, error) {
+	args := m.Mock.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*[]models.Role), args.Error(1)
+}
+
+func (m *RoleServiceMock) Update(ctx context.Context, id int64, role *models.RoleUpdate) (*models.Role, error) {
+	args := m.Mock.Called(ctx, id, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *RoleServiceMock) Delete(ctx context.Context, id int64) error {
+	args := m.Mock.Called(ctx, id)
+	return args.Error(0)
+}
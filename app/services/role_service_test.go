@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRoleService_Create(t *testing.T) {
+	role := &models.Role{ID: 1, Name: "admin"}
+
+	testCaseList := []struct {
+		name          string
+		data          *models.RoleCreate
+		mockSetup     func(*repositories.RoleRepositoryMock)
+		expectedRole  *models.Role
+		expectedError error
+	}{
+		{
+			name: "successful creation",
+			data: &models.RoleCreate{Name: "admin"},
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("GetByName", mock.Anything, "admin").Return(nil, sql.ErrNoRows)
+				m.On("Create", mock.Anything, &models.RoleCreate{Name: "admin"}).Return(role, nil)
+			},
+			expectedRole:  role,
+			expectedError: nil,
+		},
+		{
+			name: "duplicate name",
+			data: &models.RoleCreate{Name: "admin"},
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("GetByName", mock.Anything, "admin").Return(role, nil)
+			},
+			expectedRole:  nil,
+			expectedError: ErrRoleAlreadyExists,
+		},
+		{
+			name: "duplicate inserted concurrently",
+			data: &models.RoleCreate{Name: "admin"},
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("GetByName", mock.Anything, "admin").Return(nil, sql.ErrNoRows)
+				m.On("Create", mock.Anything, mock.Anything).Return(nil, repositories.ErrDuplicate.Wrap(assert.AnError))
+			},
+			expectedRole:  nil,
+			expectedError: ErrRoleAlreadyExists,
+		},
+		{
+			name: "repository error",
+			data: &models.RoleCreate{Name: "admin"},
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("GetByName", mock.Anything, "admin").Return(nil, assert.AnError)
+			},
+			expectedRole:  nil,
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewRoleRepositoryMock()
+			testCase.mockSetup(repoMock)
+
+			service := NewRoleService(repoMock)
+			role, err := service.Create(context.Background(), testCase.data)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedRole, role)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoleService_GetByID(t *testing.T) {
+	role := &models.Role{ID: 1, Name: "admin"}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.RoleRepositoryMock)
+		expectedRole  *models.Role
+		expectedError error
+	}{
+		{
+			name: "role found",
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("GetByID", mock.Anything, int64(1)).Return(role, nil)
+			},
+			expectedRole:  role,
+			expectedError: nil,
+		},
+		{
+			name: "role not found",
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("GetByID", mock.Anything, int64(1)).Return(nil, sql.ErrNoRows)
+			},
+			expectedRole:  nil,
+			expectedError: ErrRoleNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewRoleRepositoryMock()
+			testCase.mockSetup(repoMock)
+
+			service := NewRoleService(repoMock)
+			role, err := service.GetByID(context.Background(), 1)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedRole, role)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoleService_List(t *testing.T) {
+	roles := &[]models.Role{{ID: 1, Name: "admin"}}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.RoleRepositoryMock)
+		expectedRoles *[]models.Role
+		expectedError error
+	}{
+		{
+			name: "successful list",
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("List", mock.Anything).Return(roles, nil)
+			},
+			expectedRoles: roles,
+			expectedError: nil,
+		},
+		{
+			name: "repository error",
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("List", mock.Anything).Return(nil, assert.AnError)
+			},
+			expectedRoles: nil,
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewRoleRepositoryMock()
+			testCase.mockSetup(repoMock)
+
+			service := NewRoleService(repoMock)
+			roles, err := service.List(context.Background())
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedRoles, roles)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoleService_Update(t *testing.T) {
+	update := &models.RoleUpdate{Name: "editor"}
+	updated := &models.Role{ID: 1, Name: "editor"}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.RoleRepositoryMock)
+		expectedRole  *models.Role
+		expectedError error
+	}{
+		{
+			name: "successful update",
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("GetByName", mock.Anything, "editor").Return(nil, sql.ErrNoRows)
+				m.On("Update", mock.Anything, int64(1), update).Return(updated, nil)
+			},
+			expectedRole:  updated,
+			expectedError: nil,
+		},
+		{
+			name: "same name on same role",
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("GetByName", mock.Anything, "editor").Return(updated, nil)
+				m.On("Update", mock.Anything, int64(1), update).Return(updated, nil)
+			},
+			expectedRole:  updated,
+			expectedError: nil,
+		},
+		{
+			name: "name taken by another role",
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("GetByName", mock.Anything, "editor").Return(&models.Role{ID: 2, Name: "editor"}, nil)
+			},
+			expectedRole:  nil,
+			expectedError: ErrRoleAlreadyExists,
+		},
+		{
+			name: "role not found",
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("GetByName", mock.Anything, "editor").Return(nil, sql.ErrNoRows)
+				m.On("Update", mock.Anything, int64(1), update).Return(nil, sql.ErrNoRows)
+			},
+			expectedRole:  nil,
+			expectedError: ErrRoleNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewRoleRepositoryMock()
+			testCase.mockSetup(repoMock)
+
+			service := NewRoleService(repoMock)
+			role, err := service.Update(context.Background(), 1, update)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedRole, role)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoleService_Delete(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.RoleRepositoryMock)
+		expectedError error
+	}{
+		{
+			name: "successful delete",
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("Delete", mock.Anything, int64(1)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "role not found",
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("Delete", mock.Anything, int64(1)).Return(sql.ErrNoRows)
+			},
+			expectedError: ErrRoleNotFound,
+		},
+		{
+			name: "repository error",
+			mockSetup: func(m *repositories.RoleRepositoryMock) {
+				m.On("Delete", mock.Anything, int64(1)).Return(assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewRoleRepositoryMock()
+			testCase.mockSetup(repoMock)
+
+			service := NewRoleService(repoMock)
+			err := service.Delete(context.Background(), 1)
+
+			assert.Equal(t, testCase.expectedError, err)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
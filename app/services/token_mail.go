@@ -0,0 +1,31 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// tokenMailBody writes the mail that delivers a one-time token for action,
+// e.g. "verify your email address". With a linkURL the token is passed to
+// that page as its token query parameter, otherwise the bare token is sent.
+func tokenMailBody(username string, action string, linkURL string, rawToken string, ttl time.Duration) string {
+	// 24h0m0s reads better as 24h.
+	validFor := strings.TrimSuffix(ttl.Round(time.Minute).String(), "0s")
+	if strings.HasSuffix(validFor, "h0m") {
+		validFor = strings.TrimSuffix(validFor, "0m")
+	}
+	if linkURL == "" {
+		return fmt.Sprintf("Hi %s,\n\nuse this token to %s:\n\n%s\n\nIt is valid for %s.\n", username, action, rawToken, validFor)
+	}
+
+	link, err := url.Parse(linkURL)
+	if err != nil {
+		link = &url.URL{Path: linkURL}
+	}
+	query := link.Query()
+	query.Set("token", rawToken)
+	link.RawQuery = query.Encode()
+	return fmt.Sprintf("Hi %s,\n\nopen this link to %s:\n\n%s\n\nIt is valid for %s.\n", username, action, link.String(), validFor)
+}
@@ -0,0 +1,8 @@
+package services
+
+import "go.opentelemetry.io/otel"
+
+// tracer starts spans around service calls that do more than a single query,
+// such as password hashing or token rotation, so their cost shows up between
+// the HTTP and database spans.
+var tracer = otel.Tracer("golang-template/app/services")
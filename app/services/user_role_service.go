@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-template/app"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+)
+
+var (
+	ErrRoleNotAssigned       = app.NewNotFoundError("role_not_assigned", "role is not assigned to user")
+	ErrPermissionNotAssigned = app.NewNotFoundError("permission_not_assigned", "permission is not assigned to role")
+)
+
+type UserRoleService interface {
+	AssignRole(ctx context.Context, userID string, roleID int64) error
+	RemoveRole(ctx context.Context, userID string, roleID int64) error
+	ListUserRoles(ctx context.Context, userID string) (*[]models.Role, error)
+	ListUserPermissions(ctx context.Context, userID string) (*[]models.Permission, error)
+	AssignPermission(ctx context.Context, roleID int64, permissionID int64) error
+	RemovePermission(ctx context.Context, roleID int64, permissionID int64) error
+	ListRolePermissions(ctx context.Context, roleID int64) (*[]models.Permission, error)
+}
+
+type userRoleService struct {
+	userRoleRepository   repositories.UserRoleRepository
+	userRepository       repositories.UserRepository
+	roleRepository       repositories.RoleRepository
+	permissionRepository repositories.PermissionRepository
+}
+
+func NewUserRoleService(
+	userRoleRepository repositories.UserRoleRepository,
+	userRepository repositories.UserRepository,
+	roleRepository repositories.RoleRepository,
+	permissionRepository repositories.PermissionRepository,
+) UserRoleService {
+	return &userRoleService{
+		userRoleRepository:   userRoleRepository,
+		userRepository:       userRepository,
+		roleRepository:       roleRepository,
+		permissionRepository: permissionRepository,
+	}
+}
+
+func (s *userRoleService) AssignRole(ctx context.Context, userID string, roleID int64) error {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.ensureRoleExists(ctx, roleID); err != nil {
+		return err
+	}
+
+	return s.userRoleRepository.AssignRole(ctx, userID, roleID)
+}
+
+func (s *userRoleService) RemoveRole(ctx context.Context, userID string, roleID int64) error {
+	err := s.userRoleRepository.RemoveRole(ctx, userID, roleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRoleNotAssigned
+	}
+	return err
+}
+
+func (s *userRoleService) ListUserRoles(ctx context.Context, userID string) (*[]models.Role, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.userRoleRepository.ListRolesByUser(ctx, userID)
+}
+
+func (s *userRoleService) ListUserPermissions(ctx context.Context, userID string) (*[]models.Permission, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.userRoleRepository.ListPermissionsByUser(ctx, userID)
+}
+
+func (s *userRoleService) AssignPermission(ctx context.Context, roleID int64, permissionID int64) error {
+	if err := s.ensureRoleExists(ctx, roleID); err != nil {
+		return err
+	}
+
+	_, err := s.permissionRepository.GetByID(ctx, permissionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPermissionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.userRoleRepository.AssignPermission(ctx, roleID, permissionID)
+}
+
+func (s *userRoleService) RemovePermission(ctx context.Context, roleID int64, permissionID int64) error {
+	err := s.userRoleRepository.RemovePermission(ctx, roleID, permissionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPermissionNotAssigned
+	}
+	return err
+}
+
+func (s *userRoleService) ListRolePermissions(ctx context.Context, roleID int64) (*[]models.Permission, error) {
+	if err := s.ensureRoleExists(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	return s.userRoleRepository.ListPermissionsByRole(ctx, roleID)
+}
+
+func (s *userRoleService) ensureUserExists(ctx context.Context, userID string) error {
+	_, err := s.userRepository.GetByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+func (s *userRoleService) ensureRoleExists(ctx context.Context, roleID int64) error {
+	_, err := s.roleRepository.GetByID(ctx, roleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRoleNotFound
+	}
+	return err
+}
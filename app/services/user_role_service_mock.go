@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"golang-template/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type UserRoleServiceMock struct {
+	mock.Mock
+}
+
+func NewUserRoleServiceMock() *UserRoleServiceMock {
+	return &UserRoleServiceMock{}
+}
+
+func (m *UserRoleServiceMock) AssignRole(ctx context.Context, userID string, roleID int64) error {
+	args := m.Mock.Called(ctx, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *UserRoleServiceMock) RemoveRole(ctx context.Context, userID string, roleID int64) error {
+	args := m.Mock.Called(ctx, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *UserRoleServiceMock) ListUserRoles(ctx context.Context, userID string) (*[]models.Role, error) {
+	args := m.Mock.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*[]models.Role), args.Error(1)
+}
+
+func (m *UserRoleServiceMock) ListUserPermissions(ctx context.Context, userID string) (*[]models.Permission, error) {
+	args := m.Mock.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*[]models.Permission), args.Error(1)
+}
+
+func (m *UserRoleServiceMock) AssignPermission(ctx context.Context, roleID int64, permissionID int64) error {
+	args := m.Mock.Called(ctx, roleID, permissionID)
+	return args.Error(0)
+}
+
+func (m *UserRoleServiceMock) RemovePermission(ctx context.Context, roleID int64, permissionID int64) error {
+	args := m.Mock.Called(ctx, roleID, permissionID)
+	return args.Error(0)
+}
+
+func (m *UserRoleServiceMock) ListRolePermissions(ctx context.Context, roleID int64) (*[]models.Permission, error) {
+	args := m.Mock.Called(ctx, roleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*[]models.Permission), args.Error(1)
+}
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"golang-template/app/models"
+	"golang-template/app/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type userRoleRepositoryMocks struct {
+	userRole   *repositories.UserRoleRepositoryMock
+	user       *repositories.UserRepositoryMock
+	role       *repositories.RoleRepositoryMock
+	permission *repositories.PermissionRepositoryMock
+}
+
+func newUserRoleRepositoryMocks() *userRoleRepositoryMocks {
+	return &userRoleRepositoryMocks{
+		userRole:   repositories.NewUserRoleRepositoryMock(),
+		user:       repositories.NewUserRepositoryMock(),
+		role:       repositories.NewRoleRepositoryMock(),
+		permission: repositories.NewPermissionRepositoryMock(),
+	}
+}
+
+func (m *userRoleRepositoryMocks) newService() UserRoleService {
+	return NewUserRoleService(m.userRole, m.user, m.role, m.permission)
+}
+
+func (m *userRoleRepositoryMocks) assertExpectations(t *testing.T) {
+	m.userRole.AssertExpectations(t)
+	m.user.AssertExpectations(t)
+	m.role.AssertExpectations(t)
+	m.permission.AssertExpectations(t)
+}
+
+func TestUserRoleService_AssignRole(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*userRoleRepositoryMocks)
+		expectedError error
+	}{
+		{
+			name: "successful assignment",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.user.On("GetByID", mock.Anything, testUserID).Return(&models.User{ID: testUserID}, nil)
+				m.role.On("GetByID", mock.Anything, int64(2)).Return(&models.Role{ID: 2}, nil)
+				m.userRole.On("AssignRole", mock.Anything, testUserID, int64(2)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "user not found",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.user.On("GetByID", mock.Anything, testUserID).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrUserNotFound,
+		},
+		{
+			name: "role not found",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.user.On("GetByID", mock.Anything, testUserID).Return(&models.User{ID: testUserID}, nil)
+				m.role.On("GetByID", mock.Anything, int64(2)).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrRoleNotFound,
+		},
+		{
+			name: "repository error",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.user.On("GetByID", mock.Anything, testUserID).Return(&models.User{ID: testUserID}, nil)
+				m.role.On("GetByID", mock.Anything, int64(2)).Return(&models.Role{ID: 2}, nil)
+				m.userRole.On("AssignRole", mock.Anything, testUserID, int64(2)).Return(assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			mocks := newUserRoleRepositoryMocks()
+			testCase.mockSetup(mocks)
+
+			err := mocks.newService().AssignRole(context.Background(), testUserID, 2)
+
+			assert.Equal(t, testCase.expectedError, err)
+			mocks.assertExpectations(t)
+		})
+	}
+}
+
+func TestUserRoleService_RemoveRole(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*userRoleRepositoryMocks)
+		expectedError error
+	}{
+		{
+			name: "successful removal",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.userRole.On("RemoveRole", mock.Anything, testUserID, int64(2)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "role not assigned",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.userRole.On("RemoveRole", mock.Anything, testUserID, int64(2)).Return(sql.ErrNoRows)
+			},
+			expectedError: ErrRoleNotAssigned,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			mocks := newUserRoleRepositoryMocks()
+			testCase.mockSetup(mocks)
+
+			err := mocks.newService().RemoveRole(context.Background(), testUserID, 2)
+
+			assert.Equal(t, testCase.expectedError, err)
+			mocks.assertExpectations(t)
+		})
+	}
+}
+
+func TestUserRoleService_ListUserRoles(t *testing.T) {
+	roles := &[]models.Role{{ID: 2, Name: "admin"}}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*userRoleRepositoryMocks)
+		expectedRoles *[]models.Role
+		expectedError error
+	}{
+		{
+			name: "successful list",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.user.On("GetByID", mock.Anything, testUserID).Return(&models.User{ID: testUserID}, nil)
+				m.userRole.On("ListRolesByUser", mock.Anything, testUserID).Return(roles, nil)
+			},
+			expectedRoles: roles,
+			expectedError: nil,
+		},
+		{
+			name: "user not found",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.user.On("GetByID", mock.Anything, testUserID).Return(nil, sql.ErrNoRows)
+			},
+			expectedRoles: nil,
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			mocks := newUserRoleRepositoryMocks()
+			testCase.mockSetup(mocks)
+
+			roles, err := mocks.newService().ListUserRoles(context.Background(), testUserID)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedRoles, roles)
+			mocks.assertExpectations(t)
+		})
+	}
+}
+
+func TestUserRoleService_ListUserPermissions(t *testing.T) {
+	permissions := &[]models.Permission{{ID: 3, Name: "user:read"}}
+
+	testCaseList := []struct {
+		name                string
+		mockSetup           func(*userRoleRepositoryMocks)
+		expectedPermissions *[]models.Permission
+		expectedError       error
+	}{
+		{
+			name: "successful list",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.user.On("GetByID", mock.Anything, testUserID).Return(&models.User{ID: testUserID}, nil)
+				m.userRole.On("ListPermissionsByUser", mock.Anything, testUserID).Return(permissions, nil)
+			},
+			expectedPermissions: permissions,
+			expectedError:       nil,
+		},
+		{
+			name: "repository error",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.user.On("GetByID", mock.Anything, testUserID).Return(nil, assert.AnError)
+			},
+			expectedPermissions: nil,
+			expectedError:       assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			mocks := newUserRoleRepositoryMocks()
+			testCase.mockSetup(mocks)
+
+			permissions, err := mocks.newService().ListUserPermissions(context.Background(), testUserID)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPermissions, permissions)
+			mocks.assertExpectations(t)
+		})
+	}
+}
+
+func TestUserRoleService_AssignPermission(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*userRoleRepositoryMocks)
+		expectedError error
+	}{
+		{
+			name: "successful assignment",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.role.On("GetByID", mock.Anything, int64(2)).Return(&models.Role{ID: 2}, nil)
+				m.permission.On("GetByID", mock.Anything, int64(3)).Return(&models.Permission{ID: 3}, nil)
+				m.userRole.On("AssignPermission", mock.Anything, int64(2), int64(3)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "role not found",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.role.On("GetByID", mock.Anything, int64(2)).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrRoleNotFound,
+		},
+		{
+			name: "permission not found",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.role.On("GetByID", mock.Anything, int64(2)).Return(&models.Role{ID: 2}, nil)
+				m.permission.On("GetByID", mock.Anything, int64(3)).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrPermissionNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			mocks := newUserRoleRepositoryMocks()
+			testCase.mockSetup(mocks)
+
+			err := mocks.newService().AssignPermission(context.Background(), 2, 3)
+
+			assert.Equal(t, testCase.expectedError, err)
+			mocks.assertExpectations(t)
+		})
+	}
+}
+
+func TestUserRoleService_RemovePermission(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*userRoleRepositoryMocks)
+		expectedError error
+	}{
+		{
+			name: "successful removal",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.userRole.On("RemovePermission", mock.Anything, int64(2), int64(3)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "permission not assigned",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.userRole.On("RemovePermission", mock.Anything, int64(2), int64(3)).Return(sql.ErrNoRows)
+			},
+			expectedError: ErrPermissionNotAssigned,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			mocks := newUserRoleRepositoryMocks()
+			testCase.mockSetup(mocks)
+
+			err := mocks.newService().RemovePermission(context.Background(), 2, 3)
+
+			assert.Equal(t, testCase.expectedError, err)
+			mocks.assertExpectations(t)
+		})
+	}
+}
+
+func TestUserRoleService_ListRolePermissions(t *testing.T) {
+	permissions := &[]models.Permission{{ID: 3, Name: "user:read"}}
+
+	testCaseList := []struct {
+		name                string
+		mockSetup           func(*userRoleRepositoryMocks)
+		expectedPermissions *[]models.Permission
+		expectedError       error
+	}{
+		{
+			name: "successful list",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.role.On("GetByID", mock.Anything, int64(2)).Return(&models.Role{ID: 2}, nil)
+				m.userRole.On("ListPermissionsByRole", mock.Anything, int64(2)).Return(permissions, nil)
+			},
+			expectedPermissions: permissions,
+			expectedError:       nil,
+		},
+		{
+			name: "role not found",
+			mockSetup: func(m *userRoleRepositoryMocks) {
+				m.role.On("GetByID", mock.Anything, int64(2)).Return(nil, sql.ErrNoRows)
+			},
+			expectedPermissions: nil,
+			expectedError:       ErrRoleNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			mocks := newUserRoleRepositoryMocks()
+			testCase.mockSetup(mocks)
+
+			permissions, err := mocks.newService().ListRolePermissions(context.Background(), 2)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedPermissions, permissions)
+			mocks.assertExpectations(t)
+		})
+	}
+}
@@ -1,32 +1,291 @@
 package services
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"golang-template/app"
 	"golang-template/app/models"
 	"golang-template/app/repositories"
+	"golang-template/hasher"
+	"golang-template/logger"
+	"sync"
+	"time"
 )
 
+var (
+	ErrInvalidCredentials  = app.NewUnauthorizedError("invalid_credentials", "invalid username or password")
+	ErrUserNotFound        = app.NewNotFoundError("user_not_found", "user not found")
+	ErrDeletedUserNotFound = app.NewNotFoundError("deleted_user_not_found", "deleted user not found")
+	ErrUserAlreadyExists   = app.NewConflictError("user_already_exists", "username or email already exists")
+	ErrInvalidCursor       = app.NewBadRequestError("invalid_cursor", "invalid cursor")
+	ErrIncorrectPassword   = app.NewBadRequestError("incorrect_password", "current password is incorrect")
+)
+
+const defaultPageSize = 20
+
 type UserService interface {
-	Register(user *models.UserRegister) error
-	Update(user *models.UserUpdatePassword) error
-	List() (*[]models.User, error)
+	Register(ctx context.Context, user *models.UserRegister) error
+	ChangePassword(ctx context.Context, id string, request *models.ChangePasswordRequest, ip string) error
+	List(ctx context.Context, query *models.UserListQuery) (*models.UserList, error)
+	Authenticate(ctx context.Context, username string, password string, ip string) (*models.User, error)
+	GetByID(ctx context.Context, id string) (*models.User, error)
+	Patch(ctx context.Context, id string, patch *models.UserPatch) (*models.User, error)
+	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) (*models.User, error)
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error)
+	Unlock(ctx context.Context, id string) error
 }
 
 type userService struct {
-	userRepository repositories.UserRepository
+	userRepository           repositories.UserRepository
+	passwordHasher           hasher.Hasher
+	emailVerificationService EmailVerificationService
+	loginAttemptService      LoginAttemptService
+
+	dummyHashOnce sync.Once
+	dummyHash     string
+}
+
+func NewUserService(
+	userRepository repositories.UserRepository,
+	passwordHasher hasher.Hasher,
+	emailVerificationService EmailVerificationService,
+	loginAttemptService LoginAttemptService,
+) UserService {
+	return &userService{
+		userRepository:           userRepository,
+		passwordHasher:           passwordHasher,
+		emailVerificationService: emailVerificationService,
+		loginAttemptService:      loginAttemptService,
+	}
+}
+
+func (s *userService) Register(ctx context.Context, user *models.UserRegister) error {
+	ctx, span := tracer.Start(ctx, "userService.Register")
+	defer span.End()
+
+	hash, err := s.passwordHasher.Hash(user.Password)
+	if err != nil {
+		return err
+	}
+
+	newUser := *user
+	newUser.Password = hash
+	created, err := s.userRepository.Create(ctx, &newUser)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return ErrUserAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	s.sendVerification(ctx, created)
+	return nil
 }
 
-func NewUserService(userRepository repositories.UserRepository) UserService {
-	return &userService{userRepository: userRepository}
+// ChangePassword sets a new password after checking the current one. The
+// user's sessions are kept, use the reset flow to sign out everywhere. Wrong
+// current passwords count as failed logins of the user from ip, so a stolen
+// access token can't be used to guess it.
+func (s *userService) ChangePassword(ctx context.Context, id string, request *models.ChangePasswordRequest, ip string) error {
+	ctx, span := tracer.Start(ctx, "userService.ChangePassword")
+	defer span.End()
+
+	user, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.loginAttemptService.Check(ctx, user.Username, ip); err != nil {
+		return err
+	}
+
+	if err := s.passwordHasher.Verify(user.Password, request.CurrentPassword); err != nil {
+		if errors.Is(err, hasher.ErrMismatchedHash) || errors.Is(err, hasher.ErrUnknownHashFormat) {
+			s.loginAttemptService.RecordFailure(ctx, user.Username, ip, "invalid_password")
+			return ErrIncorrectPassword
+		}
+		return err
+	}
+
+	hash, err := s.passwordHasher.Hash(request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	err = s.userRepository.UpdatePassword(ctx, id, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+func (s *userService) List(ctx context.Context, query *models.UserListQuery) (*models.UserList, error) {
+	listQuery := *query
+	if listQuery.Page == 0 {
+		listQuery.Page = 1
+	}
+	if listQuery.PageSize == 0 {
+		listQuery.PageSize = defaultPageSize
+	}
+	if listQuery.Sort == "" {
+		listQuery.Sort = "id"
+	}
+
+	list, err := s.userRepository.List(ctx, &listQuery)
+	if errors.Is(err, repositories.ErrInvalidCursor) {
+		return nil, ErrInvalidCursor
+	}
+	return list, err
+}
+
+func (s *userService) GetByID(ctx context.Context, id string) (*models.User, error) {
+	user, err := s.userRepository.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+func (s *userService) Patch(ctx context.Context, id string, patch *models.UserPatch) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "userService.Patch")
+	defer span.End()
+
+	user, err := s.userRepository.Patch(ctx, id, patch)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return nil, ErrUserAlreadyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Email != nil && user.VerifiedAt == nil {
+		s.sendVerification(ctx, user)
+	}
+	return user, nil
+}
+
+func (s *userService) Delete(ctx context.Context, id string) error {
+	err := s.userRepository.SoftDelete(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+func (s *userService) Restore(ctx context.Context, id string) (*models.User, error) {
+	err := s.userRepository.Restore(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDeletedUserNotFound
+	}
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return nil, ErrUserAlreadyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The user can be deleted again, or purged, before it is read back.
+	return s.GetByID(ctx, id)
+}
+
+// PurgeDeleted permanently removes users deleted more than retention ago.
+func (s *userService) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	ctx, span := tracer.Start(ctx, "userService.PurgeDeleted")
+	defer span.End()
+
+	purged, err := s.userRepository.PurgeDeleted(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	if purged > 0 {
+		logger.FromContext(ctx).With(logger.Fields{"count": purged}).Info("Purged deleted users")
+	}
+	return purged, nil
+}
+
+// Authenticate checks the password of a login from ip. Locked out usernames
+// and IPs are rejected before the password is checked, and every failure
+// counts towards the next lockout. The failures are only cleared once the
+// whole login, including a second factor, succeeded.
+func (s *userService) Authenticate(ctx context.Context, username string, password string, ip string) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "userService.Authenticate")
+	defer span.End()
+
+	if err := s.loginAttemptService.Check(ctx, username, ip); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepository.GetByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		s.verifyDummy(password)
+		s.loginAttemptService.RecordFailure(ctx, username, ip, "unknown_user")
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.passwordHasher.Verify(user.Password, password); err != nil {
+		if errors.Is(err, hasher.ErrMismatchedHash) || errors.Is(err, hasher.ErrUnknownHashFormat) {
+			s.loginAttemptService.RecordFailure(ctx, username, ip, "invalid_password")
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if s.passwordHasher.NeedsRehash(user.Password) {
+		s.rehash(ctx, user, password)
+	}
+
+	return user, nil
+}
+
+// Unlock lifts the login lockout of the user.
+func (s *userService) Unlock(ctx context.Context, id string) error {
+	ctx, span := tracer.Start(ctx, "userService.Unlock")
+	defer span.End()
+
+	user, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.loginAttemptService.Unlock(ctx, user.Username)
 }
 
-func (s *userService) Register(user *models.UserRegister) error {
-	return s.userRepository.Create(user)
+// sendVerification mails a verification token to a new or changed address.
+// The user is already saved, so a mail failure is only logged and the token
+// can be requested again through the resend endpoint.
+func (s *userService) sendVerification(ctx context.Context, user *models.User) {
+	if err := s.emailVerificationService.Send(ctx, user); err != nil {
+		logger.FromContext(ctx).With(logger.Fields{"user_id": user.ID, "error": err.Error()}).Warn("Verification email failed")
+	}
 }
 
-func (s *userService) Update(user *models.UserUpdatePassword) error {
-	return s.userRepository.Update(user)
+// verifyDummy checks the password against a hash of the current algorithm
+// and parameters, so logins of unknown usernames take as long as wrong
+// passwords and response times don't reveal which accounts exist.
+func (s *userService) verifyDummy(password string) {
+	s.dummyHashOnce.Do(func() {
+		s.dummyHash, _ = s.passwordHasher.Hash("dummy password")
+	})
+	if s.dummyHash != "" {
+		_ = s.passwordHasher.Verify(s.dummyHash, password)
+	}
 }
 
-func (s *userService) List() (*[]models.User, error) {
-	return s.userRepository.List()
+// rehash upgrades the stored hash to the current hasher parameters. A failure
+// here must not fail the login, the old hash is still valid.
+func (s *userService) rehash(ctx context.Context, user *models.User, password string) {
+	hash, err := s.passwordHasher.Hash(password)
+	if err == nil {
+		err = s.userRepository.UpdatePassword(ctx, user.ID, hash)
+	}
+	if err != nil {
+		logger.FromContext(ctx).With(logger.Fields{"user_id": user.ID, "error": err.Error()}).Warn("Password rehash failed")
+		return
+	}
+	user.Password = hash
 }
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"golang-template/app/models"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -14,20 +16,67 @@ func NewUserServiceMock() *UserServiceMock {
 	return &UserServiceMock{}
 }
 
-func (m *UserServiceMock) Register(user *models.UserRegister) error {
-	args := m.Mock.Called(user)
+func (m *UserServiceMock) Register(ctx context.Context, user *models.UserRegister) error {
+	args := m.Mock.Called(ctx, user)
 	return args.Error(0)
 }
 
-func (m *UserServiceMock) Update(user *models.UserUpdatePassword) error {
-	args := m.Mock.Called(user)
+func (m *UserServiceMock) ChangePassword(ctx context.Context, id string, request *models.ChangePasswordRequest, ip string) error {
+	args := m.Mock.Called(ctx, id, request, ip)
 	return args.Error(0)
 }
 
-func (m *UserServiceMock) List() (*[]models.User, error) {
-	args := m.Mock.Called()
+func (m *UserServiceMock) List(ctx context.Context, query *models.UserListQuery) (*models.UserList, error) {
+	args := m.Mock.Called(ctx, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*[]models.User), args.Error(1)
+	return args.Get(0).(*models.UserList), args.Error(1)
+}
+
+func (m *UserServiceMock) Authenticate(ctx context.Context, username string, password string, ip string) (*models.User, error) {
+	args := m.Mock.Called(ctx, username, password, ip)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *UserServiceMock) GetByID(ctx context.Context, id string) (*models.User, error) {
+	args := m.Mock.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *UserServiceMock) Patch(ctx context.Context, id string, patch *models.UserPatch) (*models.User, error) {
+	args := m.Mock.Called(ctx, id, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *UserServiceMock) Delete(ctx context.Context, id string) error {
+	args := m.Mock.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) Restore(ctx context.Context, id string) (*models.User, error) {
+	args := m.Mock.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *UserServiceMock) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	args := m.Mock.Called(ctx, retention)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *UserServiceMock) Unlock(ctx context.Context, id string) error {
+	args := m.Mock.Called(ctx, id)
+	return args.Error(0)
 }
@@ -1,8 +1,12 @@
 package services
 
 import (
+	"context"
+	"database/sql"
 	"golang-template/app/models"
 	"golang-template/app/repositories"
+	"golang-template/hasher"
+	"golang-template/logger"
 	"testing"
 	"time"
 
@@ -10,11 +14,15 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+const testUserID = "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01"
+
 func TestUserService_Register(t *testing.T) {
+	createdUser := &models.User{ID: testUserID, Username: "testuser", Email: "test@example.com"}
+
 	testCaseList := []struct {
 		name          string
 		data          *models.UserRegister
-		mockSetup     func(*repositories.UserRepositoryMock)
+		mockSetup     func(*repositories.UserRepositoryMock, *hasher.HasherMock, *EmailVerificationServiceMock)
 		expectedError error
 	}{
 		{
@@ -24,11 +32,43 @@ func TestUserService_Register(t *testing.T) {
 				Email:    "test@example.com",
 				Password: "password123",
 			},
-			mockSetup: func(m *repositories.UserRepositoryMock) {
-				m.On("Create", mock.Anything).Return(nil)
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, v *EmailVerificationServiceMock) {
+				h.On("Hash", "password123").Return("hashed", nil)
+				m.On("Create", mock.Anything, &models.UserRegister{
+					Username: "testuser",
+					Email:    "test@example.com",
+					Password: "hashed",
+				}).Return(createdUser, nil)
+				v.On("Send", mock.Anything, createdUser).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "verification email failure doesn't fail registration",
+			data: &models.UserRegister{
+				Username: "testuser",
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, v *EmailVerificationServiceMock) {
+				h.On("Hash", "password123").Return("hashed", nil)
+				m.On("Create", mock.Anything, mock.Anything).Return(createdUser, nil)
+				v.On("Send", mock.Anything, createdUser).Return(assert.AnError)
 			},
 			expectedError: nil,
 		},
+		{
+			name: "hasher error",
+			data: &models.UserRegister{
+				Username: "testuser",
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, v *EmailVerificationServiceMock) {
+				h.On("Hash", "password123").Return("", assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
 		{
 			name: "repository error",
 			data: &models.UserRegister{
@@ -36,54 +76,189 @@ func TestUserService_Register(t *testing.T) {
 				Email:    "test@example.com",
 				Password: "password123",
 			},
-			mockSetup: func(m *repositories.UserRepositoryMock) {
-				m.On("Create", mock.Anything).Return(assert.AnError)
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, v *EmailVerificationServiceMock) {
+				h.On("Hash", "password123").Return("hashed", nil)
+				m.On("Create", mock.Anything, mock.Anything).Return(nil, assert.AnError)
 			},
 			expectedError: assert.AnError,
 		},
+		{
+			name: "duplicate user",
+			data: &models.UserRegister{
+				Username: "testuser",
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, v *EmailVerificationServiceMock) {
+				h.On("Hash", "password123").Return("hashed", nil)
+				m.On("Create", mock.Anything, mock.Anything).Return(nil, repositories.ErrDuplicate.Wrap(assert.AnError))
+			},
+			expectedError: ErrUserAlreadyExists,
+		},
 	}
 
 	for _, testCase := range testCaseList {
 		t.Run(testCase.name, func(t *testing.T) {
 			repoMock := repositories.NewUserRepositoryMock()
-			testCase.mockSetup(repoMock)
+			hasherMock := hasher.NewHasherMock()
+			verificationMock := NewEmailVerificationServiceMock()
+			testCase.mockSetup(repoMock, hasherMock, verificationMock)
+
+			service := NewUserService(repoMock, hasherMock, verificationMock, NewLoginAttemptServiceMock())
+			err := service.Register(context.Background(), testCase.data)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, "password123", testCase.data.Password)
+			repoMock.AssertExpectations(t)
+			hasherMock.AssertExpectations(t)
+			verificationMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	request := &models.ChangePasswordRequest{CurrentPassword: "Current1", NewPassword: "NewPassw0rd"}
+	storedUser := &models.User{ID: testUserID, Username: "testuser", Password: "old-hash"}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.UserRepositoryMock, *hasher.HasherMock, *LoginAttemptServiceMock)
+		expectedError error
+	}{
+		{
+			name: "successful change",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				m.On("GetByID", mock.Anything, testUserID).Return(storedUser, nil)
+				l.On("Check", mock.Anything, "testuser", "10.0.0.1").Return(nil)
+				h.On("Verify", "old-hash", "Current1").Return(nil)
+				h.On("Hash", "NewPassw0rd").Return("new-hash", nil)
+				m.On("UpdatePassword", mock.Anything, testUserID, "new-hash").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "incorrect current password",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				m.On("GetByID", mock.Anything, testUserID).Return(storedUser, nil)
+				l.On("Check", mock.Anything, "testuser", "10.0.0.1").Return(nil)
+				h.On("Verify", "old-hash", "Current1").Return(hasher.ErrMismatchedHash)
+				l.On("RecordFailure", mock.Anything, "testuser", "10.0.0.1", "invalid_password")
+			},
+			expectedError: ErrIncorrectPassword,
+		},
+		{
+			name: "locked out",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				m.On("GetByID", mock.Anything, testUserID).Return(storedUser, nil)
+				l.On("Check", mock.Anything, "testuser", "10.0.0.1").Return(ErrLoginLocked)
+			},
+			expectedError: ErrLoginLocked,
+		},
+		{
+			name: "user not found",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				m.On("GetByID", mock.Anything, testUserID).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrUserNotFound,
+		},
+		{
+			name: "deleted meanwhile",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				m.On("GetByID", mock.Anything, testUserID).Return(storedUser, nil)
+				l.On("Check", mock.Anything, "testuser", "10.0.0.1").Return(nil)
+				h.On("Verify", "old-hash", "Current1").Return(nil)
+				h.On("Hash", "NewPassw0rd").Return("new-hash", nil)
+				m.On("UpdatePassword", mock.Anything, testUserID, "new-hash").Return(sql.ErrNoRows)
+			},
+			expectedError: ErrUserNotFound,
+		},
+		{
+			name: "hasher error",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				m.On("GetByID", mock.Anything, testUserID).Return(storedUser, nil)
+				l.On("Check", mock.Anything, "testuser", "10.0.0.1").Return(nil)
+				h.On("Verify", "old-hash", "Current1").Return(nil)
+				h.On("Hash", "NewPassw0rd").Return("", assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewUserRepositoryMock()
+			hasherMock := hasher.NewHasherMock()
+			loginAttemptMock := NewLoginAttemptServiceMock()
+			testCase.mockSetup(repoMock, hasherMock, loginAttemptMock)
 
-			service := NewUserService(repoMock)
-			err := service.Register(testCase.data)
+			service := NewUserService(repoMock, hasherMock, NewEmailVerificationServiceMock(), loginAttemptMock)
+			err := service.ChangePassword(context.Background(), testUserID, request, "10.0.0.1")
 
 			assert.Equal(t, testCase.expectedError, err)
 			repoMock.AssertExpectations(t)
+			hasherMock.AssertExpectations(t)
+			loginAttemptMock.AssertExpectations(t)
 		})
 	}
 }
 
-func TestUserService_Update(t *testing.T) {
+func TestUserService_List(t *testing.T) {
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	list := &models.UserList{
+		Users: []models.User{
+			{
+				Username:  "user1",
+				Email:     "user1@example.com",
+				CreatedAt: testTime,
+				UpdatedAt: testTime,
+			},
+		},
+		Page:     1,
+		PageSize: 20,
+		Total:    1,
+	}
+
 	testCaseList := []struct {
 		name          string
-		user          *models.UserUpdatePassword
+		query         *models.UserListQuery
 		mockSetup     func(*repositories.UserRepositoryMock)
+		expectedList  *models.UserList
 		expectedError error
 	}{
 		{
-			name: "successful update",
-			user: &models.UserUpdatePassword{
-				Username:    "testuser",
-				NewPassword: "newpassword123",
+			name:  "applies defaults",
+			query: &models.UserListQuery{},
+			mockSetup: func(m *repositories.UserRepositoryMock) {
+				m.On("List", mock.Anything, &models.UserListQuery{Page: 1, PageSize: 20, Sort: "id"}).Return(list, nil)
 			},
+			expectedList:  list,
+			expectedError: nil,
+		},
+		{
+			name:  "keeps requested paging",
+			query: &models.UserListQuery{Page: 3, PageSize: 5, Sort: "-createdAt", Q: "user"},
 			mockSetup: func(m *repositories.UserRepositoryMock) {
-				m.On("Update", mock.Anything).Return(nil)
+				m.On("List", mock.Anything, &models.UserListQuery{Page: 3, PageSize: 5, Sort: "-createdAt", Q: "user"}).Return(list, nil)
 			},
+			expectedList:  list,
 			expectedError: nil,
 		},
 		{
-			name: "repository error",
-			user: &models.UserUpdatePassword{
-				Username:    "testuser",
-				NewPassword: "newpassword123",
+			name:  "invalid cursor",
+			query: &models.UserListQuery{Cursor: "bad"},
+			mockSetup: func(m *repositories.UserRepositoryMock) {
+				m.On("List", mock.Anything, mock.Anything).Return(nil, repositories.ErrInvalidCursor)
 			},
+			expectedList:  nil,
+			expectedError: ErrInvalidCursor,
+		},
+		{
+			name:  "repository error",
+			query: &models.UserListQuery{},
 			mockSetup: func(m *repositories.UserRepositoryMock) {
-				m.On("Update", mock.Anything).Return(assert.AnError)
+				m.On("List", mock.Anything, mock.Anything).Return(nil, assert.AnError)
 			},
+			expectedList:  nil,
 			expectedError: assert.AnError,
 		},
 	}
@@ -93,54 +268,172 @@ func TestUserService_Update(t *testing.T) {
 			repoMock := repositories.NewUserRepositoryMock()
 			testCase.mockSetup(repoMock)
 
-			service := NewUserService(repoMock)
-			err := service.Update(testCase.user)
+			service := NewUserService(repoMock, hasher.NewHasherMock(), NewEmailVerificationServiceMock(), NewLoginAttemptServiceMock())
+			users, err := service.List(context.Background(), testCase.query)
 
 			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedList, users)
 			repoMock.AssertExpectations(t)
 		})
 	}
 }
 
-func TestUserService_List(t *testing.T) {
-	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+func TestUserService_Authenticate(t *testing.T) {
+	storedUser := func() *models.User {
+		return &models.User{ID: testUserID, Username: "testuser", Email: "test@example.com", Password: "old-hash"}
+	}
+
+	testCaseList := []struct {
+		name            string
+		username        string
+		password        string
+		mockSetup       func(*repositories.UserRepositoryMock, *hasher.HasherMock, *LoginAttemptServiceMock)
+		expectedUser    *models.User
+		expectedError   error
+		expectedWarning string
+	}{
+		{
+			name:     "successful authentication",
+			username: "testuser",
+			password: "password123",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				l.On("Check", mock.Anything, "testuser", "10.0.0.1").Return(nil)
+				m.On("GetByUsername", mock.Anything, "testuser").Return(storedUser(), nil)
+				h.On("Verify", "old-hash", "password123").Return(nil)
+				h.On("NeedsRehash", "old-hash").Return(false)
+			},
+			expectedUser:  storedUser(),
+			expectedError: nil,
+		},
+		{
+			name:     "rehash when parameters changed",
+			username: "testuser",
+			password: "password123",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				l.On("Check", mock.Anything, "testuser", "10.0.0.1").Return(nil)
+				m.On("GetByUsername", mock.Anything, "testuser").Return(storedUser(), nil)
+				h.On("Verify", "old-hash", "password123").Return(nil)
+				h.On("NeedsRehash", "old-hash").Return(true)
+				h.On("Hash", "password123").Return("new-hash", nil)
+				m.On("UpdatePassword", mock.Anything, testUserID, "new-hash").Return(nil)
+			},
+			expectedUser:  &models.User{ID: testUserID, Username: "testuser", Email: "test@example.com", Password: "new-hash"},
+			expectedError: nil,
+		},
+		{
+			name:     "rehash failure does not fail login",
+			username: "testuser",
+			password: "password123",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				l.On("Check", mock.Anything, "testuser", "10.0.0.1").Return(nil)
+				m.On("GetByUsername", mock.Anything, "testuser").Return(storedUser(), nil)
+				h.On("Verify", "old-hash", "password123").Return(nil)
+				h.On("NeedsRehash", "old-hash").Return(true)
+				h.On("Hash", "password123").Return("new-hash", nil)
+				m.On("UpdatePassword", mock.Anything, testUserID, "new-hash").Return(assert.AnError)
+			},
+			expectedUser:    storedUser(),
+			expectedError:   nil,
+			expectedWarning: "Password rehash failed",
+		},
+		{
+			name:     "user not found",
+			username: "missing",
+			password: "password123",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				l.On("Check", mock.Anything, "missing", "10.0.0.1").Return(nil)
+				m.On("GetByUsername", mock.Anything, "missing").Return(nil, sql.ErrNoRows)
+				h.On("Hash", "dummy password").Return("dummy-hash", nil).Once()
+				h.On("Verify", "dummy-hash", "password123").Return(hasher.ErrMismatchedHash)
+				l.On("RecordFailure", mock.Anything, "missing", "10.0.0.1", "unknown_user")
+			},
+			expectedUser:  nil,
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:     "wrong password",
+			username: "testuser",
+			password: "wrong",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				l.On("Check", mock.Anything, "testuser", "10.0.0.1").Return(nil)
+				m.On("GetByUsername", mock.Anything, "testuser").Return(storedUser(), nil)
+				h.On("Verify", "old-hash", "wrong").Return(hasher.ErrMismatchedHash)
+				l.On("RecordFailure", mock.Anything, "testuser", "10.0.0.1", "invalid_password")
+			},
+			expectedUser:  nil,
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:     "locked out",
+			username: "testuser",
+			password: "password123",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				l.On("Check", mock.Anything, "testuser", "10.0.0.1").Return(ErrLoginLocked)
+			},
+			expectedUser:  nil,
+			expectedError: ErrLoginLocked,
+		},
+		{
+			name:     "repository error",
+			username: "testuser",
+			password: "password123",
+			mockSetup: func(m *repositories.UserRepositoryMock, h *hasher.HasherMock, l *LoginAttemptServiceMock) {
+				l.On("Check", mock.Anything, "testuser", "10.0.0.1").Return(nil)
+				m.On("GetByUsername", mock.Anything, "testuser").Return(nil, assert.AnError)
+			},
+			expectedUser:  nil,
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewUserRepositoryMock()
+			hasherMock := hasher.NewHasherMock()
+			loginAttemptMock := NewLoginAttemptServiceMock()
+			loggerMock := logger.NewLoggerMock()
+			testCase.mockSetup(repoMock, hasherMock, loginAttemptMock)
+			if testCase.expectedWarning != "" {
+				loggerMock.On("With", mock.Anything).Return(loggerMock)
+				loggerMock.On("Warn", testCase.expectedWarning)
+			}
+			ctx := logger.WithContext(context.Background(), loggerMock)
+
+			service := NewUserService(repoMock, hasherMock, NewEmailVerificationServiceMock(), loginAttemptMock)
+			user, err := service.Authenticate(ctx, testCase.username, testCase.password, "10.0.0.1")
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedUser, user)
+			repoMock.AssertExpectations(t)
+			hasherMock.AssertExpectations(t)
+			loginAttemptMock.AssertExpectations(t)
+			loggerMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_GetByID(t *testing.T) {
+	user := &models.User{ID: testUserID, Username: "testuser"}
 
 	testCaseList := []struct {
 		name          string
 		mockSetup     func(*repositories.UserRepositoryMock)
-		expectedUsers *[]models.User
+		expectedUser  *models.User
 		expectedError error
 	}{
 		{
-			name: "successful list",
+			name: "found",
 			mockSetup: func(m *repositories.UserRepositoryMock) {
-				users := &[]models.User{
-					{
-						Username:  "user1",
-						Email:     "user1@example.com",
-						CreatedAt: testTime,
-						UpdatedAt: testTime,
-					},
-				}
-				m.On("List").Return(users, nil)
-			},
-			expectedUsers: &[]models.User{
-				{
-					Username:  "user1",
-					Email:     "user1@example.com",
-					CreatedAt: testTime,
-					UpdatedAt: testTime,
-				},
+				m.On("GetByID", mock.Anything, testUserID).Return(user, nil)
 			},
-			expectedError: nil,
+			expectedUser: user,
 		},
 		{
-			name: "repository error",
+			name: "not found",
 			mockSetup: func(m *repositories.UserRepositoryMock) {
-				m.On("List").Return(nil, assert.AnError)
+				m.On("GetByID", mock.Anything, testUserID).Return(nil, sql.ErrNoRows)
 			},
-			expectedUsers: nil,
-			expectedError: assert.AnError,
+			expectedError: ErrUserNotFound,
 		},
 	}
 
@@ -149,12 +442,223 @@ func TestUserService_List(t *testing.T) {
 			repoMock := repositories.NewUserRepositoryMock()
 			testCase.mockSetup(repoMock)
 
-			service := NewUserService(repoMock)
-			users, err := service.List()
+			service := NewUserService(repoMock, hasher.NewHasherMock(), NewEmailVerificationServiceMock(), NewLoginAttemptServiceMock())
+			user, err := service.GetByID(context.Background(), testUserID)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedUser, user)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_Patch(t *testing.T) {
+	email := "new@example.com"
+	username := "renamed"
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	user := &models.User{ID: testUserID, Username: "testuser", Email: email}
+
+	testCaseList := []struct {
+		name          string
+		patch         *models.UserPatch
+		mockSetup     func(*repositories.UserRepositoryMock, *EmailVerificationServiceMock)
+		expectedUser  *models.User
+		expectedError error
+	}{
+		{
+			name:  "new email is sent a verification",
+			patch: &models.UserPatch{Email: &email},
+			mockSetup: func(m *repositories.UserRepositoryMock, v *EmailVerificationServiceMock) {
+				m.On("Patch", mock.Anything, testUserID, &models.UserPatch{Email: &email}).Return(user, nil)
+				v.On("Send", mock.Anything, user).Return(nil)
+			},
+			expectedUser: user,
+		},
+		{
+			name:  "unchanged verified email",
+			patch: &models.UserPatch{Email: &email},
+			mockSetup: func(m *repositories.UserRepositoryMock, v *EmailVerificationServiceMock) {
+				m.On("Patch", mock.Anything, testUserID, mock.Anything).Return(&models.User{ID: testUserID, Email: email, VerifiedAt: &verifiedAt}, nil)
+			},
+			expectedUser: &models.User{ID: testUserID, Email: email, VerifiedAt: &verifiedAt},
+		},
+		{
+			name:  "username only",
+			patch: &models.UserPatch{Username: &username},
+			mockSetup: func(m *repositories.UserRepositoryMock, v *EmailVerificationServiceMock) {
+				m.On("Patch", mock.Anything, testUserID, mock.Anything).Return(user, nil)
+			},
+			expectedUser: user,
+		},
+		{
+			name:  "not found",
+			patch: &models.UserPatch{Email: &email},
+			mockSetup: func(m *repositories.UserRepositoryMock, v *EmailVerificationServiceMock) {
+				m.On("Patch", mock.Anything, testUserID, mock.Anything).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrUserNotFound,
+		},
+		{
+			name:  "duplicate email",
+			patch: &models.UserPatch{Email: &email},
+			mockSetup: func(m *repositories.UserRepositoryMock, v *EmailVerificationServiceMock) {
+				m.On("Patch", mock.Anything, testUserID, mock.Anything).Return(nil, repositories.ErrDuplicate)
+			},
+			expectedError: ErrUserAlreadyExists,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewUserRepositoryMock()
+			verificationMock := NewEmailVerificationServiceMock()
+			testCase.mockSetup(repoMock, verificationMock)
+
+			service := NewUserService(repoMock, hasher.NewHasherMock(), verificationMock, NewLoginAttemptServiceMock())
+			user, err := service.Patch(context.Background(), testUserID, testCase.patch)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedUser, user)
+			repoMock.AssertExpectations(t)
+			verificationMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_Delete(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		repoError     error
+		expectedError error
+	}{
+		{name: "successful delete", repoError: nil, expectedError: nil},
+		{name: "not found", repoError: sql.ErrNoRows, expectedError: ErrUserNotFound},
+		{name: "repository error", repoError: assert.AnError, expectedError: assert.AnError},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewUserRepositoryMock()
+			repoMock.On("SoftDelete", mock.Anything, testUserID).Return(testCase.repoError)
+
+			service := NewUserService(repoMock, hasher.NewHasherMock(), NewEmailVerificationServiceMock(), NewLoginAttemptServiceMock())
+			err := service.Delete(context.Background(), testUserID)
+
+			assert.Equal(t, testCase.expectedError, err)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_Restore(t *testing.T) {
+	user := &models.User{ID: testUserID, Username: "testuser"}
+
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.UserRepositoryMock)
+		expectedUser  *models.User
+		expectedError error
+	}{
+		{
+			name: "successful restore",
+			mockSetup: func(m *repositories.UserRepositoryMock) {
+				m.On("Restore", mock.Anything, testUserID).Return(nil)
+				m.On("GetByID", mock.Anything, testUserID).Return(user, nil)
+			},
+			expectedUser: user,
+		},
+		{
+			name: "not deleted",
+			mockSetup: func(m *repositories.UserRepositoryMock) {
+				m.On("Restore", mock.Anything, testUserID).Return(sql.ErrNoRows)
+			},
+			expectedError: ErrDeletedUserNotFound,
+		},
+		{
+			name: "username or email taken meanwhile",
+			mockSetup: func(m *repositories.UserRepositoryMock) {
+				m.On("Restore", mock.Anything, testUserID).Return(repositories.ErrDuplicate)
+			},
+			expectedError: ErrUserAlreadyExists,
+		},
+		{
+			name: "deleted again before it is read",
+			mockSetup: func(m *repositories.UserRepositoryMock) {
+				m.On("Restore", mock.Anything, testUserID).Return(nil)
+				m.On("GetByID", mock.Anything, testUserID).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewUserRepositoryMock()
+			testCase.mockSetup(repoMock)
+
+			service := NewUserService(repoMock, hasher.NewHasherMock(), NewEmailVerificationServiceMock(), NewLoginAttemptServiceMock())
+			user, err := service.Restore(context.Background(), testUserID)
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedUser, user)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_PurgeDeleted(t *testing.T) {
+	repoMock := repositories.NewUserRepositoryMock()
+	repoMock.On("PurgeDeleted", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= 720*time.Hour && time.Since(before) < 721*time.Hour
+	})).Return(int64(2), nil)
+	loggerMock := logger.NewLoggerMock()
+	loggerMock.On("With", logger.Fields{"count": int64(2)}).Return(loggerMock)
+	loggerMock.On("Info", "Purged deleted users")
+	ctx := logger.WithContext(context.Background(), loggerMock)
+
+	service := NewUserService(repoMock, hasher.NewHasherMock(), NewEmailVerificationServiceMock(), NewLoginAttemptServiceMock())
+	purged, err := service.PurgeDeleted(ctx, 720*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	repoMock.AssertExpectations(t)
+	loggerMock.AssertExpectations(t)
+}
+
+func TestUserService_Unlock(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		mockSetup     func(*repositories.UserRepositoryMock, *LoginAttemptServiceMock)
+		expectedError error
+	}{
+		{
+			name: "successful unlock",
+			mockSetup: func(m *repositories.UserRepositoryMock, l *LoginAttemptServiceMock) {
+				m.On("GetByID", mock.Anything, testUserID).Return(&models.User{ID: testUserID, Username: "testuser"}, nil)
+				l.On("Unlock", mock.Anything, "testuser").Return(nil)
+			},
+		},
+		{
+			name: "not found",
+			mockSetup: func(m *repositories.UserRepositoryMock, l *LoginAttemptServiceMock) {
+				m.On("GetByID", mock.Anything, testUserID).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			repoMock := repositories.NewUserRepositoryMock()
+			loginAttemptMock := NewLoginAttemptServiceMock()
+			testCase.mockSetup(repoMock, loginAttemptMock)
+
+			service := NewUserService(repoMock, hasher.NewHasherMock(), NewEmailVerificationServiceMock(), loginAttemptMock)
+			err := service.Unlock(context.Background(), testUserID)
 
 			assert.Equal(t, testCase.expectedError, err)
-			assert.Equal(t, testCase.expectedUsers, users)
 			repoMock.AssertExpectations(t)
+			loginAttemptMock.AssertExpectations(t)
 		})
 	}
 }
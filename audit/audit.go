@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"context"
+	"golang-template/logger"
+)
+
+// Event types. Add new ones here so consumers of the audit log have a single
+// list to match against.
+const (
+	LoginSucceeded  = "login_succeeded"
+	LoginFailed     = "login_failed"
+	LoginLocked     = "login_locked"
+	AccountUnlocked = "account_unlocked"
+
+	MFAEnabled          = "mfa_enabled"
+	MFADisabled         = "mfa_disabled"
+	MFAVerified         = "mfa_verified"
+	MFAFailed           = "mfa_failed"
+	MFARecoveryCodeUsed = "mfa_recovery_code_used"
+)
+
+// Event is a security relevant action. Fields must not contain secrets.
+type Event struct {
+	Type   string
+	Fields logger.Fields
+}
+
+type Recorder interface {
+	Record(ctx context.Context, event Event)
+}
+
+type logRecorder struct{}
+
+// NewLogRecorder writes events as info entries of the request logger, so they
+// carry its request_id and, for authenticated requests, the acting user_id.
+// Entries are marked with "audit": true and the event type under "event".
+func NewLogRecorder() Recorder {
+	return logRecorder{}
+}
+
+func (logRecorder) Record(ctx context.Context, event Event) {
+	fields := logger.Fields{"audit": true, "event": event.Type}
+	for key, value := range event.Fields {
+		fields[key] = value
+	}
+	logger.FromContext(ctx).With(fields).Info("Audit event")
+}
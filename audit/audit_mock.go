@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type RecorderMock struct {
+	mock.Mock
+}
+
+func NewRecorderMock() *RecorderMock {
+	return &RecorderMock{}
+}
+
+func (m *RecorderMock) Record(ctx context.Context, event Event) {
+	m.Mock.Called(ctx, event)
+}
@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"context"
+	"golang-template/logger"
+	"testing"
+)
+
+func TestLogRecorder(t *testing.T) {
+	loggerMock := logger.NewLoggerMock()
+	loggerMock.On("With", logger.Fields{"audit": true, "event": LoginLocked, "username": "alice"}).Return(loggerMock)
+	loggerMock.On("Info", "Audit event")
+	ctx := logger.WithContext(context.Background(), loggerMock)
+
+	NewLogRecorder().Record(ctx, Event{Type: LoginLocked, Fields: logger.Fields{"username": "alice"}})
+
+	loggerMock.AssertExpectations(t)
+}
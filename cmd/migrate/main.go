@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"golang-template/config"
+	"golang-template/database"
+)
+
+const usage = `Usage: migrate [flags] <command>
+
+Commands:
+  up       apply all pending migrations
+  down     revert the last applied migrations (see -steps)
+  status   list migrations and when they were applied
+  unlock   release a lock left by a crashed migration run
+
+Flags:
+`
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "print the migrations that would run without applying them")
+	steps := flag.Int("steps", 1, "number of migrations to revert with down")
+	lockTimeout := flag.Duration("lock-timeout", 30*time.Second, "how long to wait for another migration run to finish")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(".env")
+	if err != nil {
+		exit(err)
+	}
+
+	db, err := database.GetSQLite(cfg.Database.Path)
+	if err != nil {
+		exit(err)
+	}
+	defer database.CloseDB()
+
+	migrator, err := database.NewMigrator(db, database.Migrations(), database.MigratorConfig{
+		DryRun:      *dryRun,
+		LockTimeout: *lockTimeout,
+	})
+	if err != nil {
+		exit(err)
+	}
+
+	prefix := ""
+	if *dryRun {
+		prefix = "[dry-run] "
+	}
+
+	switch flag.Arg(0) {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			fmt.Printf("%sapplied %d_%s\n", prefix, migration.Version, migration.Name)
+		}
+		if err != nil {
+			exit(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		reverted, err := migrator.Down(*steps)
+		for _, migration := range reverted {
+			fmt.Printf("%sreverted %d_%s\n", prefix, migration.Version, migration.Name)
+		}
+		if err != nil {
+			exit(err)
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			exit(err)
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%06d_%-40s %s\n", status.Version, status.Name, appliedAt)
+		}
+	case "unlock":
+		if err := migrator.Unlock(); err != nil {
+			exit(err)
+		}
+		fmt.Println("migration lock released")
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func exit(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	database.CloseDB()
+	os.Exit(1)
+}
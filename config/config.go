@@ -0,0 +1,305 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-template/hasher"
+	"golang-template/health"
+	"golang-template/idempotency"
+	"golang-template/logger"
+	"golang-template/mailer"
+	"golang-template/ratelimit"
+	"golang-template/tracing"
+	"golang-template/validator"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Env          string `yaml:"env" env:"ENV" validate:"required"`
+	Port         int    `yaml:"port" env:"PORT" validate:"min=1,max=65535"`
+	AllowOrigins string `yaml:"allowOrigins" env:"ALLOW_ORIGINS" validate:"required"`
+	// RequestTimeout bounds the context passed to services for /api routes.
+	RequestTimeout time.Duration      `yaml:"requestTimeout" env:"REQUEST_TIMEOUT" validate:"gt=0"`
+	Database       DatabaseConfig     `yaml:"database"`
+	JWT            JWTConfig          `yaml:"jwt"`
+	Shutdown       ShutdownConfig     `yaml:"shutdown"`
+	Log            LogConfig          `yaml:"log"`
+	Tracing        tracing.Config     `yaml:"tracing"`
+	Health         health.Config      `yaml:"health"`
+	RateLimit      ratelimit.Config   `yaml:"rateLimit"`
+	Idempotency    idempotency.Config `yaml:"idempotency"`
+	Users          UsersConfig        `yaml:"users"`
+	Hasher         hasher.Config      `yaml:"hasher"`
+	Mail           mailer.Config      `yaml:"mail"`
+	// EmailVerification configures the tokens mailed to new addresses.
+	EmailVerification EmailVerificationConfig `yaml:"emailVerification"`
+	PasswordReset     PasswordResetConfig     `yaml:"passwordReset"`
+	Lockout           LockoutConfig           `yaml:"lockout"`
+	MFA               MFAConfig               `yaml:"mfa"`
+}
+
+type DatabaseConfig struct {
+	Path        string `yaml:"path" env:"DB_PATH" validate:"required"`
+	AutoMigrate bool   `yaml:"autoMigrate" env:"DB_AUTO_MIGRATE"`
+}
+
+// JWTConfig leaves key checks to token.NewManager, so tools that never sign
+// tokens, like cmd/migrate, can load the config without a secret.
+type JWTConfig struct {
+	Algorithm       string        `yaml:"algorithm" env:"JWT_ALGORITHM" validate:"oneof=HS256 EdDSA"`
+	Secret          string        `yaml:"secret" env:"JWT_SECRET"`
+	PrivateKey      string        `yaml:"privateKey" env:"JWT_PRIVATE_KEY"`
+	AccessTokenTTL  time.Duration `yaml:"accessTokenTTL" env:"JWT_ACCESS_TOKEN_TTL" validate:"gt=0"`
+	RefreshTokenTTL time.Duration `yaml:"refreshTokenTTL" env:"JWT_REFRESH_TOKEN_TTL" validate:"gt=0"`
+}
+
+type ShutdownConfig struct {
+	// Delay keeps serving after /readyz turns unhealthy so load balancers can
+	// stop routing traffic before connections are drained.
+	Delay   time.Duration `yaml:"delay" env:"SHUTDOWN_DELAY" validate:"gte=0"`
+	Timeout time.Duration `yaml:"timeout" env:"SHUTDOWN_TIMEOUT" validate:"gt=0"`
+}
+
+type UsersConfig struct {
+	// DeletedRetention is how long soft deleted users can be restored before
+	// the purge job removes them for good.
+	DeletedRetention time.Duration `yaml:"deletedRetention" env:"USER_DELETED_RETENTION" validate:"gt=0"`
+	PurgeInterval    time.Duration `yaml:"purgeInterval" env:"USER_PURGE_INTERVAL" validate:"gt=0"`
+}
+
+type EmailVerificationConfig struct {
+	TokenTTL time.Duration `yaml:"tokenTTL" env:"EMAIL_VERIFICATION_TTL" validate:"gt=0"`
+	// LinkURL is the frontend page that posts the token from its query
+	// string to /api/v1/auth/verify-email.
+	LinkURL string `yaml:"linkURL" env:"EMAIL_VERIFICATION_URL" validate:"omitempty,url"`
+}
+
+type PasswordResetConfig struct {
+	TokenTTL time.Duration `yaml:"tokenTTL" env:"PASSWORD_RESET_TTL" validate:"gt=0"`
+	// LinkURL is the frontend page that posts the token from its query
+	// string and the new password to /api/v1/auth/reset-password.
+	LinkURL string `yaml:"linkURL" env:"PASSWORD_RESET_URL" validate:"omitempty,url"`
+}
+
+// LockoutConfig sets when failed logins lock an account or a client IP. Each
+// lockout of the same key lasts twice as long as the previous one, from
+// BaseDuration up to MaxDuration, until no login failed for ResetAfter.
+type LockoutConfig struct {
+	MaxFailures     int           `yaml:"maxFailures" env:"LOGIN_MAX_FAILURES" validate:"min=1"`
+	IPMaxFailures   int           `yaml:"ipMaxFailures" env:"LOGIN_IP_MAX_FAILURES" validate:"min=1"`
+	BaseDuration    time.Duration `yaml:"baseDuration" env:"LOGIN_LOCKOUT_BASE" validate:"gt=0"`
+	MaxDuration     time.Duration `yaml:"maxDuration" env:"LOGIN_LOCKOUT_MAX" validate:"gtefield=BaseDuration"`
+	ResetAfter      time.Duration `yaml:"resetAfter" env:"LOGIN_ATTEMPTS_RESET_AFTER" validate:"gt=0"`
+	CleanupInterval time.Duration `yaml:"cleanupInterval" env:"LOGIN_ATTEMPTS_CLEANUP_INTERVAL" validate:"gt=0"`
+}
+
+type MFAConfig struct {
+	// Issuer is the name authenticator apps show next to the account.
+	Issuer string `yaml:"issuer" env:"MFA_ISSUER" validate:"required"`
+	// ChallengeTTL is how long the second step of a login can be completed.
+	ChallengeTTL    time.Duration `yaml:"challengeTTL" env:"MFA_CHALLENGE_TTL" validate:"gt=0"`
+	CleanupInterval time.Duration `yaml:"cleanupInterval" env:"MFA_CLEANUP_INTERVAL" validate:"gt=0"`
+	// RequiredRoles can only log in with two-factor authentication, users
+	// holding one of them enroll during their next login.
+	RequiredRoles []string `yaml:"requiredRoles" env:"MFA_REQUIRED_ROLES"`
+}
+
+type LogConfig struct {
+	logger.Config `yaml:",inline"`
+	Redact        logger.RedactConfig `yaml:"redact"`
+	// SkipBodyPaths are path.Match patterns of request paths, where a trailing
+	// "/*" also matches deeper paths, whose request and response bodies are
+	// never logged.
+	SkipBodyPaths []string `yaml:"skipBodyPaths" env:"LOG_SKIP_BODY_PATHS"`
+}
+
+func Default() Config {
+	return Config{
+		Env:            "local",
+		Port:           9090,
+		AllowOrigins:   "*",
+		RequestTimeout: 10 * time.Second,
+		Database: DatabaseConfig{
+			Path:        "./app.db",
+			AutoMigrate: true,
+		},
+		JWT: JWTConfig{
+			Algorithm:       "HS256",
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 7 * 24 * time.Hour,
+		},
+		Shutdown: ShutdownConfig{
+			Timeout: 15 * time.Second,
+		},
+		Log: LogConfig{
+			Config: logger.DefaultConfig(),
+			Redact: logger.DefaultRedactConfig(),
+		},
+		Tracing:     tracing.DefaultConfig(),
+		Health:      health.DefaultConfig(),
+		RateLimit:   ratelimit.DefaultConfig(),
+		Idempotency: idempotency.DefaultConfig(),
+		Users: UsersConfig{
+			DeletedRetention: 30 * 24 * time.Hour,
+			PurgeInterval:    24 * time.Hour,
+		},
+		Hasher: hasher.DefaultConfig(),
+		Mail:   mailer.DefaultConfig(),
+		EmailVerification: EmailVerificationConfig{
+			TokenTTL: 24 * time.Hour,
+		},
+		PasswordReset: PasswordResetConfig{
+			TokenTTL: time.Hour,
+		},
+		Lockout: LockoutConfig{
+			MaxFailures:     5,
+			IPMaxFailures:   20,
+			BaseDuration:    time.Minute,
+			MaxDuration:     time.Hour,
+			ResetAfter:      24 * time.Hour,
+			CleanupInterval: time.Hour,
+		},
+		MFA: MFAConfig{
+			Issuer:          "golang-template",
+			ChallengeTTL:    5 * time.Minute,
+			CleanupInterval: time.Hour,
+			RequiredRoles:   []string{"admin"},
+		},
+	}
+}
+
+// Load builds the configuration from, in increasing priority, the defaults,
+// the YAML file named by CONFIG_FILE, the given .env files and the process
+// environment. Missing .env files are skipped.
+func Load(envFiles ...string) (*Config, error) {
+	dotenv := map[string]string{}
+	for _, file := range envFiles {
+		values, err := godotenv.Read(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", file, err)
+		}
+		for key, value := range values {
+			if _, ok := dotenv[key]; !ok {
+				dotenv[key] = value
+			}
+		}
+	}
+
+	lookup := func(key string) (string, bool) {
+		if value, ok := os.LookupEnv(key); ok {
+			return value, true
+		}
+		value, ok := dotenv[key]
+		return value, ok
+	}
+
+	config := Default()
+
+	if path, ok := lookup("CONFIG_FILE"); ok && path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(content, &config); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(&config).Elem(), lookup); err != nil {
+		return nil, err
+	}
+
+	if err := validator.ValidateStruct(&config); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	// The outbox sends nothing, so deployed environments would silently lose
+	// every verification and reset mail.
+	if config.Mail.Driver == mailer.DriverOutbox && config.Env != "local" {
+		return nil, fmt.Errorf("invalid config: the %s mail driver requires ENV=local", mailer.DriverOutbox)
+	}
+
+	return &config, nil
+}
+
+func applyEnv(v reflect.Value, lookup func(string) (string, bool)) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := v.Type().Field(i).Tag.Get("env")
+		if key == "" {
+			continue
+		}
+
+		// An empty value keeps the default, so a blank line copied from
+		// .env.example doesn't wipe out a setting.
+		value, ok := lookup(key)
+		if !ok || value == "" {
+			continue
+		}
+
+		if err := setField(field, value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, value string) error {
+	switch {
+	case field.Type() == reflect.TypeOf(time.Duration(0)):
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case field.Type() == reflect.TypeOf([]string(nil)):
+		// Lists are comma separated and replace the default list.
+		var values []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported config type %s", field.Type())
+	}
+	return nil
+}
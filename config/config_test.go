@@ -0,0 +1,169 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang-template/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	config, err := Load(filepath.Join(t.TempDir(), "missing.env"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, Default(), *config)
+}
+
+func TestLoad_Sources(t *testing.T) {
+	yamlPath := writeFile(t, "config.yaml", `
+port: 8000
+log:
+  level: warn
+  outputs: [stdout, file]
+  file:
+    maxAge: 72h
+database:
+  path: /data/yaml.db
+jwt:
+  accessTokenTTL: 5m
+rateLimit:
+  policies:
+    - name: login
+      routes: ["POST /api/v1/auth/login"]
+      algorithm: sliding_window
+      limit: 3
+      window: 30s
+      key: ip
+users:
+  deletedRetention: 168h
+hasher:
+  algorithm: argon2id
+  argon2id:
+    iterations: 2
+mail:
+  driver: smtp
+  smtp:
+    host: smtp.example.com
+lockout:
+  maxFailures: 3
+mfa:
+  issuer: Example
+`)
+	envPath := writeFile(t, ".env", "CONFIG_FILE="+yamlPath+"\nPORT=8100\nALLOW_ORIGINS=https://example.com\n")
+
+	t.Setenv("PORT", "8200")
+	t.Setenv("DB_AUTO_MIGRATE", "false")
+	t.Setenv("LOG_SKIP_BODY_PATHS", "/api/v1/auth/*, /api/v1/user/register")
+	t.Setenv("TRACE_SAMPLE_RATIO", "0.25")
+	t.Setenv("HEALTH_DEPENDENCIES", "https://mail.example.com/health")
+	t.Setenv("RATE_LIMIT_STORE", "sqlite")
+	t.Setenv("IDEMPOTENCY_TTL", "1h")
+	t.Setenv("USER_PURGE_INTERVAL", "6h")
+	t.Setenv("ARGON2ID_MEMORY", "19456")
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("EMAIL_VERIFICATION_URL", "https://app.example.com/verify")
+	t.Setenv("PASSWORD_RESET_TTL", "30m")
+	t.Setenv("LOGIN_LOCKOUT_MAX", "2h")
+	t.Setenv("MFA_CHALLENGE_TTL", "2m")
+	t.Setenv("MFA_REQUIRED_ROLES", "admin, support")
+
+	config, err := Load(envPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8200, config.Port)
+	assert.Equal(t, "https://example.com", config.AllowOrigins)
+	assert.Equal(t, "warn", config.Log.Level)
+	assert.Equal(t, []string{"stdout", "file"}, config.Log.Outputs)
+	assert.Equal(t, 72*time.Hour, config.Log.File.MaxAge)
+	assert.Equal(t, "json", config.Log.Format)
+	assert.Equal(t, "/data/yaml.db", config.Database.Path)
+	assert.False(t, config.Database.AutoMigrate)
+	assert.Equal(t, 5*time.Minute, config.JWT.AccessTokenTTL)
+	assert.Equal(t, 7*24*time.Hour, config.JWT.RefreshTokenTTL)
+	assert.Equal(t, []string{"/api/v1/auth/*", "/api/v1/user/register"}, config.Log.SkipBodyPaths)
+	assert.Equal(t, 0.25, config.Tracing.SampleRatio)
+	assert.Equal(t, []string{"https://mail.example.com/health"}, config.Health.Dependencies)
+	assert.Equal(t, "sqlite", config.RateLimit.Store)
+	assert.Equal(t, []ratelimit.Policy{
+		{Name: "login", Routes: []string{"POST /api/v1/auth/login"}, Algorithm: ratelimit.SlidingWindow, Limit: 3, Window: 30 * time.Second, Key: ratelimit.KeyIP},
+	}, config.RateLimit.Policies)
+	assert.Equal(t, time.Hour, config.Idempotency.TTL)
+	assert.Equal(t, 168*time.Hour, config.Users.DeletedRetention)
+	assert.Equal(t, 6*time.Hour, config.Users.PurgeInterval)
+	assert.Equal(t, "argon2id", config.Hasher.Algorithm)
+	assert.Equal(t, 19456, config.Hasher.Argon2id.Memory)
+	assert.Equal(t, 2, config.Hasher.Argon2id.Iterations)
+	assert.Equal(t, 2, config.Hasher.Argon2id.Parallelism)
+	assert.Equal(t, "smtp", config.Mail.Driver)
+	assert.Equal(t, "smtp.example.com", config.Mail.SMTP.Host)
+	assert.Equal(t, 2525, config.Mail.SMTP.Port)
+	assert.Equal(t, "no-reply@localhost", config.Mail.From)
+	assert.Equal(t, "https://app.example.com/verify", config.EmailVerification.LinkURL)
+	assert.Equal(t, 24*time.Hour, config.EmailVerification.TokenTTL)
+	assert.Equal(t, 30*time.Minute, config.PasswordReset.TokenTTL)
+	assert.Equal(t, 3, config.Lockout.MaxFailures)
+	assert.Equal(t, 20, config.Lockout.IPMaxFailures)
+	assert.Equal(t, 2*time.Hour, config.Lockout.MaxDuration)
+	assert.Equal(t, "Example", config.MFA.Issuer)
+	assert.Equal(t, 2*time.Minute, config.MFA.ChallengeTTL)
+	assert.Equal(t, []string{"admin", "support"}, config.MFA.RequiredRoles)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	testCaseList := []struct {
+		name string
+		env  map[string]string
+	}{
+		{name: "invalid port", env: map[string]string{"PORT": "http"}},
+		{name: "port out of range", env: map[string]string{"PORT": "70000"}},
+		{name: "invalid duration", env: map[string]string{"JWT_ACCESS_TOKEN_TTL": "soon"}},
+		{name: "invalid bool", env: map[string]string{"DB_AUTO_MIGRATE": "sometimes"}},
+		{name: "unknown log level", env: map[string]string{"LOG_LEVEL": "verbose"}},
+		{name: "unknown log format", env: map[string]string{"LOG_FORMAT": "xml"}},
+		{name: "unknown log output", env: map[string]string{"LOG_OUTPUTS": "stdout,kafka"}},
+		{name: "unknown trace exporter", env: map[string]string{"TRACE_EXPORTER": "jaeger"}},
+		{name: "sample ratio out of range", env: map[string]string{"TRACE_SAMPLE_RATIO": "1.5"}},
+		{name: "invalid health dependency", env: map[string]string{"HEALTH_DEPENDENCIES": "not a url"}},
+		{name: "unknown rate limit store", env: map[string]string{"RATE_LIMIT_STORE": "redis"}},
+		{name: "zero idempotency TTL", env: map[string]string{"IDEMPOTENCY_TTL": "0s"}},
+		{name: "zero deleted user retention", env: map[string]string{"USER_DELETED_RETENTION": "0s"}},
+		{name: "unknown password hash algorithm", env: map[string]string{"PASSWORD_HASH_ALGORITHM": "scrypt"}},
+		{name: "bcrypt cost too low", env: map[string]string{"BCRYPT_COST": "3"}},
+		{name: "zero argon2id iterations", env: map[string]string{"ARGON2ID_ITERATIONS": "0"}},
+		{name: "short argon2id salt", env: map[string]string{"ARGON2ID_SALT_LENGTH": "8"}},
+		{name: "outbox outside local", env: map[string]string{"ENV": "production"}},
+		{name: "unknown mail driver", env: map[string]string{"MAIL_DRIVER": "sendgrid"}},
+		{name: "invalid verification link", env: map[string]string{"EMAIL_VERIFICATION_URL": "not a url"}},
+		{name: "zero password reset TTL", env: map[string]string{"PASSWORD_RESET_TTL": "0s"}},
+		{name: "zero login failures", env: map[string]string{"LOGIN_MAX_FAILURES": "0"}},
+		{name: "lockout max below base", env: map[string]string{"LOGIN_LOCKOUT_BASE": "10m", "LOGIN_LOCKOUT_MAX": "5m"}},
+		{name: "zero MFA challenge TTL", env: map[string]string{"MFA_CHALLENGE_TTL": "0s"}},
+		{name: "unsupported algorithm", env: map[string]string{"JWT_ALGORITHM": "RS256"}},
+		{name: "missing config file", env: map[string]string{"CONFIG_FILE": "/does/not/exist.yaml"}},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			for key, value := range testCase.env {
+				t.Setenv(key, value)
+			}
+
+			config, err := Load()
+
+			assert.Nil(t, config)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package database
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var (
+	ErrMigrationLocked   = errors.New("migrations are locked by another process")
+	ErrChecksumMismatch  = errors.New("applied migration has been modified")
+	ErrMigrationNotFound = errors.New("applied migration is missing from the migration files")
+)
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+const lockPollInterval = 100 * time.Millisecond
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type MigratorConfig struct {
+	// DryRun reports which migrations would run without executing them or
+	// taking the lock.
+	DryRun      bool
+	LockTimeout time.Duration
+}
+
+type Migrator interface {
+	Up() ([]Migration, error)
+	Down(steps int) ([]Migration, error)
+	Status() ([]MigrationStatus, error)
+	Unlock() error
+}
+
+type migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	config     MigratorConfig
+}
+
+// Migrations returns the migration files embedded in the binary.
+func Migrations() fs.FS {
+	migrations, _ := fs.Sub(migrationFiles, "migrations")
+	return migrations
+}
+
+func NewMigrator(db *sql.DB, migrationFS fs.FS, config MigratorConfig) (Migrator, error) {
+	migrations, err := loadMigrations(migrationFS)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = 30 * time.Second
+	}
+
+	return &migrator{db: db, migrations: migrations, config: config}, nil
+}
+
+func (m *migrator) Up() ([]Migration, error) {
+	if !m.config.DryRun {
+		if err := m.createTables(); err != nil {
+			return nil, err
+		}
+		unlock, err := m.lock()
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	applied, err := m.verifiedAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if !m.config.DryRun {
+			if err := m.apply(migration); err != nil {
+				return pending, err
+			}
+		}
+		pending = append(pending, migration)
+	}
+
+	return pending, nil
+}
+
+func (m *migrator) Down(steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	if !m.config.DryRun {
+		if err := m.createTables(); err != nil {
+			return nil, err
+		}
+		unlock, err := m.lock()
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	applied, err := m.verifiedAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if !m.config.DryRun {
+			if err := m.revert(migration); err != nil {
+				return reverted, err
+			}
+		}
+		reverted = append(reverted, migration)
+	}
+
+	return reverted, nil
+}
+
+func (m *migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.appliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Unlock releases a lock left behind by a process that died mid-migration.
+func (m *migrator) Unlock() error {
+	if err := m.createTables(); err != nil {
+		return err
+	}
+	_, err := m.db.Exec(`DELETE FROM schema_migrations_lock WHERE id = 1`)
+	return err
+}
+
+func (m *migrator) createTables() error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version integer primary key,
+			name varchar(255) not null,
+			checksum varchar(64) not null,
+			applied_at timestamp not null default current_timestamp
+		);
+		CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+			id integer primary key check (id = 1),
+			locked_at timestamp not null default current_timestamp
+		);
+	`)
+	return err
+}
+
+func (m *migrator) lock() (func(), error) {
+	deadline := time.Now().Add(m.config.LockTimeout)
+	for {
+		result, err := m.db.Exec(`INSERT OR IGNORE INTO schema_migrations_lock (id) VALUES (1)`)
+		if err != nil {
+			return nil, err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if rowsAffected == 1 {
+			return func() {
+				m.db.Exec(`DELETE FROM schema_migrations_lock WHERE id = 1`)
+			}, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, ErrMigrationLocked
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+func (m *migrator) apply(migration Migration) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migration.Up); err != nil {
+		return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO schema_migrations (version, name, checksum)
+		VALUES (?, ?, ?)
+	`, migration.Version, migration.Name, migration.Checksum)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *migrator) revert(migration Migration) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migration.Down); err != nil {
+		return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, migration.Version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+func (m *migrator) appliedMigrations() (map[int64]appliedMigration, error) {
+	applied := map[int64]appliedMigration{}
+
+	var count int
+	err := m.db.QueryRow(`
+		SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'
+	`).Scan(&count)
+	if err != nil || count == 0 {
+		return applied, err
+	}
+
+	rows, err := m.db.Query(`SELECT version, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		var record appliedMigration
+		if err := rows.Scan(&version, &record.checksum, &record.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = record
+	}
+
+	return applied, rows.Err()
+}
+
+// verifiedAppliedMigrations refuses to continue when an applied migration was
+// edited or deleted, since the schema would no longer match the files.
+func (m *migrator) verifiedAppliedMigrations() (map[int64]appliedMigration, error) {
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	for version, record := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: version %d", ErrMigrationNotFound, version)
+		}
+		if migration.Checksum != record.checksum {
+			return nil, fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+
+	return applied, nil
+}
+
+func loadMigrations(migrationFS fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(migrationFS, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		sum := sha256.Sum256([]byte(migration.Up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,222 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"000001_create_items.up.sql":     {Data: []byte(`CREATE TABLE items (id integer primary key);`)},
+		"000001_create_items.down.sql":   {Data: []byte(`DROP TABLE items;`)},
+		"000002_add_items_name.up.sql":   {Data: []byte(`ALTER TABLE items ADD COLUMN name text;`)},
+		"000002_add_items_name.down.sql": {Data: []byte(`ALTER TABLE items DROP COLUMN name;`)},
+	}
+}
+
+func appliedVersions(migrations []Migration) []int64 {
+	versions := []int64{}
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return versions
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db := newTestDB(t)
+	migrator, err := NewMigrator(db, newTestMigrations(), MigratorConfig{})
+	assert.NoError(t, err)
+
+	applied, err := migrator.Up()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, appliedVersions(applied))
+
+	_, err = db.Exec(`INSERT INTO items (name) VALUES ('test')`)
+	assert.NoError(t, err)
+
+	applied, err = migrator.Up()
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err := migrator.Status()
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.NotNil(t, statuses[1].AppliedAt)
+}
+
+func TestMigrator_UpDryRun(t *testing.T) {
+	db := newTestDB(t)
+	migrator, err := NewMigrator(db, newTestMigrations(), MigratorConfig{DryRun: true})
+	assert.NoError(t, err)
+
+	applied, err := migrator.Up()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, appliedVersions(applied))
+
+	var count int
+	err = db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name IN ('items', 'schema_migrations')`).Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db := newTestDB(t)
+	migrator, err := NewMigrator(db, newTestMigrations(), MigratorConfig{})
+	assert.NoError(t, err)
+	_, err = migrator.Up()
+	assert.NoError(t, err)
+
+	reverted, err := migrator.Down(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, appliedVersions(reverted))
+
+	statuses, err := migrator.Status()
+	assert.NoError(t, err)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+
+	reverted, err = migrator.Down(5)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, appliedVersions(reverted))
+
+	_, err = migrator.Down(0)
+	assert.Error(t, err)
+}
+
+func TestMigrator_ChecksumMismatch(t *testing.T) {
+	db := newTestDB(t)
+	migrations := newTestMigrations()
+	migrator, err := NewMigrator(db, migrations, MigratorConfig{})
+	assert.NoError(t, err)
+	_, err = migrator.Up()
+	assert.NoError(t, err)
+
+	migrations["000001_create_items.up.sql"] = &fstest.MapFile{Data: []byte(`CREATE TABLE items (id integer primary key, extra text);`)}
+	migrator, err = NewMigrator(db, migrations, MigratorConfig{})
+	assert.NoError(t, err)
+
+	_, err = migrator.Up()
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+}
+
+func TestMigrator_MissingMigration(t *testing.T) {
+	db := newTestDB(t)
+	migrations := newTestMigrations()
+	migrator, err := NewMigrator(db, migrations, MigratorConfig{})
+	assert.NoError(t, err)
+	_, err = migrator.Up()
+	assert.NoError(t, err)
+
+	delete(migrations, "000002_add_items_name.up.sql")
+	delete(migrations, "000002_add_items_name.down.sql")
+	migrator, err = NewMigrator(db, migrations, MigratorConfig{})
+	assert.NoError(t, err)
+
+	_, err = migrator.Up()
+	assert.True(t, errors.Is(err, ErrMigrationNotFound))
+}
+
+func TestMigrator_Lock(t *testing.T) {
+	db := newTestDB(t)
+	migrator, err := NewMigrator(db, newTestMigrations(), MigratorConfig{LockTimeout: time.Millisecond})
+	assert.NoError(t, err)
+
+	assert.NoError(t, migrator.Unlock())
+	_, err = db.Exec(`INSERT INTO schema_migrations_lock (id) VALUES (1)`)
+	assert.NoError(t, err)
+
+	_, err = migrator.Up()
+	assert.Equal(t, ErrMigrationLocked, err)
+
+	assert.NoError(t, migrator.Unlock())
+	applied, err := migrator.Up()
+	assert.NoError(t, err)
+	assert.Len(t, applied, 2)
+}
+
+func TestNewMigrator_InvalidFiles(t *testing.T) {
+	testCaseList := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{
+			name:  "invalid file name",
+			files: fstest.MapFS{"create_items.sql": {Data: []byte(`SELECT 1;`)}},
+		},
+		{
+			name:  "missing down file",
+			files: fstest.MapFS{"000001_create_items.up.sql": {Data: []byte(`SELECT 1;`)}},
+		},
+		{
+			name: "duplicate version",
+			files: fstest.MapFS{
+				"000001_create_items.up.sql":   {Data: []byte(`SELECT 1;`)},
+				"000001_create_users.up.sql":   {Data: []byte(`SELECT 1;`)},
+				"000001_create_items.down.sql": {Data: []byte(`SELECT 1;`)},
+			},
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewMigrator(newTestDB(t), testCase.files, MigratorConfig{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	db := newTestDB(t)
+	migrator, err := NewMigrator(db, Migrations(), MigratorConfig{})
+	assert.NoError(t, err)
+
+	applied, err := migrator.Up()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, applied)
+
+	reverted, err := migrator.Down(len(applied))
+	assert.NoError(t, err)
+	assert.Len(t, reverted, len(applied))
+
+	_, err = migrator.Up()
+	assert.NoError(t, err)
+}
+
+func TestMigrations_UsersUniqueAmongActive(t *testing.T) {
+	db := newTestDB(t)
+	migrator, err := NewMigrator(db, Migrations(), MigratorConfig{})
+	assert.NoError(t, err)
+	_, err = migrator.Up()
+	assert.NoError(t, err)
+
+	insertUser := func(id string) error {
+		_, err := db.Exec(`INSERT INTO users (id, username, email, password) VALUES (?, 'alice', 'alice@example.com', 'hash')`, id)
+		return err
+	}
+
+	assert.NoError(t, insertUser("user-1"))
+	assert.Error(t, insertUser("user-2"))
+
+	_, err = db.Exec(`UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = 'user-1'`)
+	assert.NoError(t, err)
+	assert.NoError(t, insertUser("user-2"))
+
+	_, err = db.Exec(`UPDATE users SET deleted_at = NULL WHERE id = 'user-1'`)
+	assert.Error(t, err)
+}
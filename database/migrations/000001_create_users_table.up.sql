@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS users (
+	id integer primary key autoincrement,
+	username varchar(255) not null,
+	email varchar(255) not null,
+	password varchar(255) not null,
+	created_at timestamp not null default current_timestamp,
+	updated_at timestamp not null default current_timestamp
+);
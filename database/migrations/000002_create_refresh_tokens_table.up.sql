@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+	id integer primary key autoincrement,
+	user_id integer not null references users(id) on delete cascade,
+	family_id varchar(36) not null,
+	token_hash varchar(64) not null unique,
+	expires_at timestamp not null,
+	revoked_at timestamp,
+	created_at timestamp not null default current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS roles (
+	id integer primary key autoincrement,
+	name varchar(100) not null unique,
+	description varchar(255) not null default '',
+	created_at timestamp not null default current_timestamp,
+	updated_at timestamp not null default current_timestamp
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+	id integer primary key autoincrement,
+	name varchar(100) not null unique,
+	resource varchar(50) not null,
+	action varchar(50) not null,
+	description varchar(255) not null default '',
+	created_at timestamp not null default current_timestamp,
+	updated_at timestamp not null default current_timestamp
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+	user_id integer not null references users(id) on delete cascade,
+	role_id integer not null references roles(id) on delete cascade,
+	created_at timestamp not null default current_timestamp,
+	primary key (user_id, role_id)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+	role_id integer not null references roles(id) on delete cascade,
+	permission_id integer not null references permissions(id) on delete cascade,
+	created_at timestamp not null default current_timestamp,
+	primary key (role_id, permission_id)
+);
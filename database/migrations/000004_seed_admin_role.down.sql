@@ -0,0 +1,11 @@
+DELETE FROM role_permissions WHERE role_id IN (SELECT id FROM roles WHERE name = 'admin');
+DELETE FROM role_permissions WHERE permission_id IN (
+	SELECT id FROM permissions WHERE name IN (
+		'user:read', 'user:write', 'role:read', 'role:write', 'permission:read', 'permission:write'
+	)
+);
+DELETE FROM user_roles WHERE role_id IN (SELECT id FROM roles WHERE name = 'admin');
+DELETE FROM roles WHERE name = 'admin';
+DELETE FROM permissions WHERE name IN (
+	'user:read', 'user:write', 'role:read', 'role:write', 'permission:read', 'permission:write'
+);
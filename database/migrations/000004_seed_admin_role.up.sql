@@ -0,0 +1,13 @@
+-- Permissions guarding the built-in routes and an admin role that holds all of them.
+INSERT OR IGNORE INTO permissions (name, resource, action, description) VALUES
+	('user:read', 'user', 'read', 'List users'),
+	('user:write', 'user', 'write', 'Update users'),
+	('role:read', 'role', 'read', 'View roles and assignments'),
+	('role:write', 'role', 'write', 'Manage roles and assignments'),
+	('permission:read', 'permission', 'read', 'View permissions'),
+	('permission:write', 'permission', 'write', 'Manage permissions');
+
+INSERT OR IGNORE INTO roles (name, description) VALUES ('admin', 'Full access');
+
+INSERT OR IGNORE INTO role_permissions (role_id, permission_id)
+	SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin';
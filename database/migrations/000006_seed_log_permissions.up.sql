@@ -0,0 +1,7 @@
+-- Permissions for the runtime log level endpoint, granted to the admin role.
+INSERT OR IGNORE INTO permissions (name, resource, action, description) VALUES
+	('log:read', 'log', 'read', 'View the log level'),
+	('log:write', 'log', 'write', 'Change the log level');
+
+INSERT OR IGNORE INTO role_permissions (role_id, permission_id)
+	SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name IN ('log:read', 'log:write');
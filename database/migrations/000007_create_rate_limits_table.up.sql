@@ -0,0 +1,10 @@
+-- Rate limiter state, one row per policy and client key.
+CREATE TABLE IF NOT EXISTS rate_limits (
+	key varchar(255) primary key,
+	value real not null,
+	previous real not null,
+	updated_at integer not null,
+	expires_at integer not null
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limits_expires_at ON rate_limits (expires_at);
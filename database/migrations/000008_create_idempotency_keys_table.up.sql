@@ -0,0 +1,13 @@
+-- Responses stored for Idempotency-Key retries. status_code is 0 while the
+-- first request is still running.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	key varchar(320) primary key,
+	fingerprint varchar(64) not null,
+	status_code integer not null default 0,
+	content_type varchar(255) not null default '',
+	body blob,
+	created_at integer not null,
+	expires_at integer not null
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
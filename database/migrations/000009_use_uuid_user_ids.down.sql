@@ -0,0 +1,52 @@
+-- Numbers users again in creation order.
+CREATE TABLE user_id_map (
+	old_id integer primary key autoincrement,
+	new_id varchar(36) not null unique
+);
+INSERT INTO user_id_map (new_id) SELECT id FROM users ORDER BY id;
+
+CREATE TABLE users_old (
+	id integer primary key autoincrement,
+	username varchar(255) not null,
+	email varchar(255) not null,
+	password varchar(255) not null,
+	created_at timestamp not null default current_timestamp,
+	updated_at timestamp not null default current_timestamp
+);
+INSERT INTO users_old (id, username, email, password, created_at, updated_at)
+	SELECT m.old_id, u.username, u.email, u.password, u.created_at, u.updated_at
+	FROM users u JOIN user_id_map m ON m.new_id = u.id;
+DROP TABLE users;
+ALTER TABLE users_old RENAME TO users;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE refresh_tokens_old (
+	id integer primary key autoincrement,
+	user_id integer not null references users(id) on delete cascade,
+	family_id varchar(36) not null,
+	token_hash varchar(64) not null unique,
+	expires_at timestamp not null,
+	revoked_at timestamp,
+	created_at timestamp not null default current_timestamp
+);
+INSERT INTO refresh_tokens_old (id, user_id, family_id, token_hash, expires_at, revoked_at, created_at)
+	SELECT t.id, m.old_id, t.family_id, t.token_hash, t.expires_at, t.revoked_at, t.created_at
+	FROM refresh_tokens t JOIN user_id_map m ON m.new_id = t.user_id;
+DROP TABLE refresh_tokens;
+ALTER TABLE refresh_tokens_old RENAME TO refresh_tokens;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+
+CREATE TABLE user_roles_old (
+	user_id integer not null references users(id) on delete cascade,
+	role_id integer not null references roles(id) on delete cascade,
+	created_at timestamp not null default current_timestamp,
+	primary key (user_id, role_id)
+);
+INSERT INTO user_roles_old (user_id, role_id, created_at)
+	SELECT m.old_id, ur.role_id, ur.created_at
+	FROM user_roles ur JOIN user_id_map m ON m.new_id = ur.user_id;
+DROP TABLE user_roles;
+ALTER TABLE user_roles_old RENAME TO user_roles;
+
+DROP TABLE user_id_map;
@@ -0,0 +1,7 @@
+DELETE FROM role_permissions WHERE permission_id IN (
+	SELECT id FROM permissions WHERE name IN ('user:delete', 'user:restore')
+);
+DELETE FROM permissions WHERE name IN ('user:delete', 'user:restore');
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
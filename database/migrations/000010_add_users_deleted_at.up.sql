@@ -0,0 +1,11 @@
+-- Soft delete for users, and permissions to delete and restore them granted
+-- to the admin role.
+ALTER TABLE users ADD COLUMN deleted_at timestamp;
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+INSERT OR IGNORE INTO permissions (name, resource, action, description) VALUES
+	('user:delete', 'user', 'delete', 'Delete users'),
+	('user:restore', 'user', 'restore', 'Restore deleted users');
+
+INSERT OR IGNORE INTO role_permissions (role_id, permission_id)
+	SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name IN ('user:delete', 'user:restore');
@@ -0,0 +1,13 @@
+-- Users registered before verification existed keep being able to log in.
+ALTER TABLE users ADD COLUMN verified_at timestamp;
+UPDATE users SET verified_at = created_at;
+
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+	id integer primary key autoincrement,
+	user_id varchar(36) not null references users(id) on delete cascade,
+	token_hash varchar(64) not null unique,
+	expires_at timestamp not null,
+	used_at timestamp,
+	created_at timestamp not null default current_timestamp
+);
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user_id ON email_verification_tokens (user_id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+	id integer primary key autoincrement,
+	user_id varchar(36) not null references users(id) on delete cascade,
+	token_hash varchar(64) not null unique,
+	expires_at timestamp not null,
+	used_at timestamp,
+	created_at timestamp not null default current_timestamp
+);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens (user_id);
@@ -0,0 +1,18 @@
+-- Failed login tracking for account and IP lockouts, and a permission to lift
+-- an account lockout granted to the admin role.
+CREATE TABLE IF NOT EXISTS login_attempts (
+	scope varchar(16) not null,
+	key varchar(255) not null,
+	failures integer not null default 0,
+	lockouts integer not null default 0,
+	locked_until timestamp,
+	last_failed_at timestamp not null,
+	primary key (scope, key)
+);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_last_failed_at ON login_attempts (last_failed_at);
+
+INSERT OR IGNORE INTO permissions (name, resource, action, description) VALUES
+	('user:unlock', 'user', 'unlock', 'Unlock locked out users');
+
+INSERT OR IGNORE INTO role_permissions (role_id, permission_id)
+	SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'user:unlock';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_mfa_challenges_expires_at;
+DROP TABLE IF EXISTS mfa_challenges;
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
@@ -0,0 +1,30 @@
+-- TOTP two-factor authentication: one secret per user, confirmed once the
+-- user entered a code from it, single use recovery codes and the challenges
+-- that connect the password and code steps of a login.
+CREATE TABLE IF NOT EXISTS user_totp (
+	user_id varchar(36) primary key references users(id) on delete cascade,
+	secret varchar(64) not null,
+	confirmed_at timestamp,
+	last_used_step integer not null default 0,
+	created_at timestamp not null default current_timestamp
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+	id integer primary key autoincrement,
+	user_id varchar(36) not null references users(id) on delete cascade,
+	code_hash varchar(64) not null,
+	used_at timestamp,
+	created_at timestamp not null default current_timestamp,
+	unique (user_id, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS mfa_challenges (
+	id integer primary key autoincrement,
+	user_id varchar(36) not null references users(id) on delete cascade,
+	token_hash varchar(64) not null unique,
+	expires_at timestamp not null,
+	attempts integer not null default 0,
+	used_at timestamp,
+	created_at timestamp not null default current_timestamp
+);
+CREATE INDEX IF NOT EXISTS idx_mfa_challenges_expires_at ON mfa_challenges (expires_at);
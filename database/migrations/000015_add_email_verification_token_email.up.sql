@@ -0,0 +1,4 @@
+-- Tokens only verify the address they were mailed to, so one sent before an
+-- email change can't verify the new address.
+ALTER TABLE email_verification_tokens ADD COLUMN email varchar(255) not null default '';
+UPDATE email_verification_tokens SET email = COALESCE((SELECT email FROM users WHERE users.id = email_verification_tokens.user_id), '');
@@ -1,19 +1,33 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"log"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/mattn/go-sqlite3"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var DB *sql.DB
 
-func InitSQLite() (*sql.DB, error) {
+func InitSQLite(path string) (*sql.DB, error) {
 
 	var err error
 
-	DB, err = sql.Open("sqlite3", "./app.db")
+	DB, err = otelsql.Open("sqlite3", path+"?_busy_timeout=5000",
+		otelsql.WithAttributes(semconv.DBSystemSqlite),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			// Queries outside a request, like migrations, are not traced.
+			SpanFilter: func(ctx context.Context, method otelsql.Method, query string, args []driver.NamedValue) bool {
+				return trace.SpanContextFromContext(ctx).IsValid()
+			},
+		}),
+	)
 	if err != nil {
 		log.Fatal(err)
 		return nil, err
@@ -30,10 +44,10 @@ func InitSQLite() (*sql.DB, error) {
 	return DB, nil
 }
 
-func GetSQLite() (*sql.DB, error) {
+func GetSQLite(path string) (*sql.DB, error) {
 
 	if DB == nil {
-		db, err := InitSQLite()
+		db, err := InitSQLite(path)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -43,8 +57,11 @@ func GetSQLite() (*sql.DB, error) {
 	return DB, nil
 }
 
-func CloseDB() {
-	if DB != nil {
-		DB.Close()
+func CloseDB() error {
+	if DB == nil {
+		return nil
 	}
+	err := DB.Close()
+	DB = nil
+	return err
 }
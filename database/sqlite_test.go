@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInitSQLite_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	db, err := InitSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { CloseDB() })
+
+	_, err = db.ExecContext(context.Background(), "CREATE TABLE items (id integer primary key)")
+	assert.NoError(t, err)
+	assert.Empty(t, recorder.Ended(), "queries outside a trace are not recorded")
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	_, err = db.ExecContext(ctx, "INSERT INTO items (id) VALUES (1)")
+	parent.End()
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "sql.conn.exec", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
@@ -0,0 +1,104 @@
+package hasher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+var (
+	ErrUnknownHashFormat = errors.New("unknown password hash format")
+	ErrMismatchedHash    = errors.New("password does not match")
+)
+
+// Config selects the algorithm new password hashes use. Hashes made with
+// other algorithms or parameters still verify and are upgraded on login.
+type Config struct {
+	Algorithm  string         `yaml:"algorithm" env:"PASSWORD_HASH_ALGORITHM" validate:"oneof=bcrypt argon2id"`
+	BcryptCost int            `yaml:"bcryptCost" env:"BCRYPT_COST" validate:"min=4,max=31"`
+	Argon2id   Argon2idConfig `yaml:"argon2id"`
+}
+
+// Argon2idConfig holds the argon2id parameters, Memory is in KiB and the
+// salt and key lengths are in bytes.
+type Argon2idConfig struct {
+	Memory      int `yaml:"memory" env:"ARGON2ID_MEMORY" validate:"min=8192,max=4194304"`
+	Iterations  int `yaml:"iterations" env:"ARGON2ID_ITERATIONS" validate:"min=1,max=100"`
+	Parallelism int `yaml:"parallelism" env:"ARGON2ID_PARALLELISM" validate:"min=1,max=255"`
+	SaltLength  int `yaml:"saltLength" env:"ARGON2ID_SALT_LENGTH" validate:"min=16,max=64"`
+	KeyLength   int `yaml:"keyLength" env:"ARGON2ID_KEY_LENGTH" validate:"min=16,max=64"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Algorithm:  AlgorithmBcrypt,
+		BcryptCost: bcrypt.DefaultCost,
+		Argon2id: Argon2idConfig{
+			Memory:      int(DefaultArgon2idParams.Memory),
+			Iterations:  int(DefaultArgon2idParams.Iterations),
+			Parallelism: int(DefaultArgon2idParams.Parallelism),
+			SaltLength:  int(DefaultArgon2idParams.SaltLength),
+			KeyLength:   int(DefaultArgon2idParams.KeyLength),
+		},
+	}
+}
+
+// New returns the hasher selected by config.Algorithm.
+func New(config Config) (Hasher, error) {
+	switch config.Algorithm {
+	case AlgorithmBcrypt, "":
+		if config.BcryptCost < bcrypt.MinCost || config.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost %d is outside %d-%d", config.BcryptCost, bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		return NewBcryptHasher(config.BcryptCost), nil
+	case AlgorithmArgon2id:
+		argon2id := config.Argon2id
+		if argon2id.Memory <= 0 || argon2id.Iterations <= 0 || argon2id.Parallelism <= 0 || argon2id.Parallelism > 255 ||
+			argon2id.SaltLength <= 0 || argon2id.KeyLength <= 0 {
+			return nil, fmt.Errorf("invalid argon2id parameters %+v", argon2id)
+		}
+		return NewArgon2idHasher(Argon2idParams{
+			Memory:      uint32(argon2id.Memory),
+			Iterations:  uint32(argon2id.Iterations),
+			Parallelism: uint8(argon2id.Parallelism),
+			SaltLength:  uint32(argon2id.SaltLength),
+			KeyLength:   uint32(argon2id.KeyLength),
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", config.Algorithm)
+	}
+}
+
+type Hasher interface {
+	Hash(password string) (string, error)
+	Verify(hash string, password string) error
+	NeedsRehash(hash string) bool
+}
+
+// Verify checks a password against any hash format supported by this package,
+// so stored hashes keep working after the configured algorithm changes.
+func Verify(hash string, password string) error {
+	switch {
+	case isBcryptHash(hash):
+		return verifyBcrypt(hash, password)
+	case isArgon2idHash(hash):
+		return verifyArgon2id(hash, password)
+	default:
+		return ErrUnknownHashFormat
+	}
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func isArgon2idHash(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
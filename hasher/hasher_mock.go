@@ -0,0 +1,28 @@
+package hasher
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type HasherMock struct {
+	mock.Mock
+}
+
+func NewHasherMock() *HasherMock {
+	return &HasherMock{}
+}
+
+func (m *HasherMock) Hash(password string) (string, error) {
+	args := m.Mock.Called(password)
+	return args.String(0), args.Error(1)
+}
+
+func (m *HasherMock) Verify(hash string, password string) error {
+	args := m.Mock.Called(hash, password)
+	return args.Error(0)
+}
+
+func (m *HasherMock) NeedsRehash(hash string) bool {
+	args := m.Mock.Called(hash)
+	return args.Bool(0)
+}
@@ -0,0 +1,142 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testArgon2idParams = Argon2idParams{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestHasher(t *testing.T) {
+	testCaseList := []struct {
+		name   string
+		hasher Hasher
+	}{
+		{name: "bcrypt", hasher: NewBcryptHasher(bcrypt.MinCost)},
+		{name: "argon2id", hasher: NewArgon2idHasher(testArgon2idParams)},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			hash, err := testCase.hasher.Hash("password123")
+			assert.NoError(t, err)
+			assert.NotEqual(t, "password123", hash)
+
+			assert.NoError(t, testCase.hasher.Verify(hash, "password123"))
+			assert.ErrorIs(t, testCase.hasher.Verify(hash, "wrong"), ErrMismatchedHash)
+			assert.False(t, testCase.hasher.NeedsRehash(hash))
+		})
+	}
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	bcryptHash, _ := NewBcryptHasher(bcrypt.MinCost).Hash("password123")
+	argon2idHash, _ := NewArgon2idHasher(testArgon2idParams).Hash("password123")
+
+	strongerParams := testArgon2idParams
+	strongerParams.Iterations = 2
+
+	testCaseList := []struct {
+		name     string
+		hasher   Hasher
+		hash     string
+		expected bool
+	}{
+		{name: "bcrypt same cost", hasher: NewBcryptHasher(bcrypt.MinCost), hash: bcryptHash, expected: false},
+		{name: "bcrypt cost changed", hasher: NewBcryptHasher(bcrypt.MinCost + 1), hash: bcryptHash, expected: true},
+		{name: "bcrypt to argon2id", hasher: NewArgon2idHasher(testArgon2idParams), hash: bcryptHash, expected: true},
+		{name: "argon2id params changed", hasher: NewArgon2idHasher(strongerParams), hash: argon2idHash, expected: true},
+		{name: "argon2id to bcrypt", hasher: NewBcryptHasher(bcrypt.MinCost), hash: argon2idHash, expected: true},
+		{name: "plaintext", hasher: NewBcryptHasher(bcrypt.MinCost), hash: "password123", expected: true},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.hasher.NeedsRehash(testCase.hash))
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	bcryptHash, _ := NewBcryptHasher(bcrypt.MinCost).Hash("password123")
+	argon2idHash, _ := NewArgon2idHasher(testArgon2idParams).Hash("password123")
+
+	testCaseList := []struct {
+		name          string
+		hash          string
+		password      string
+		expectedError error
+	}{
+		{name: "bcrypt match", hash: bcryptHash, password: "password123", expectedError: nil},
+		{name: "bcrypt mismatch", hash: bcryptHash, password: "wrong", expectedError: ErrMismatchedHash},
+		{name: "argon2id match", hash: argon2idHash, password: "password123", expectedError: nil},
+		{name: "argon2id mismatch", hash: argon2idHash, password: "wrong", expectedError: ErrMismatchedHash},
+		{name: "argon2id unsupported version", hash: strings.Replace(argon2idHash, "$v=19$", "$v=16$", 1), password: "password123", expectedError: ErrUnknownHashFormat},
+		{name: "argon2id zero memory", hash: "$argon2id$v=19$m=0,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5", password: "password123", expectedError: ErrUnknownHashFormat},
+		{name: "argon2id zero iterations", hash: "$argon2id$v=19$m=1024,t=0,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5", password: "password123", expectedError: ErrUnknownHashFormat},
+		{name: "argon2id zero parallelism", hash: "$argon2id$v=19$m=1024,t=1,p=0$c2FsdHNhbHQ$a2V5a2V5a2V5", password: "password123", expectedError: ErrUnknownHashFormat},
+		{name: "argon2id empty salt", hash: "$argon2id$v=19$m=1024,t=1,p=1$$a2V5a2V5a2V5", password: "password123", expectedError: ErrUnknownHashFormat},
+		{name: "argon2id empty key", hash: "$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHQ$", password: "", expectedError: ErrUnknownHashFormat},
+		{name: "unknown format", hash: "password123", password: "password123", expectedError: ErrUnknownHashFormat},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := Verify(testCase.hash, testCase.password)
+			assert.ErrorIs(t, err, testCase.expectedError)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	argon2idConfig := DefaultConfig()
+	argon2idConfig.Algorithm = AlgorithmArgon2id
+	argon2idConfig.Argon2id = Argon2idConfig{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	invalidCost := DefaultConfig()
+	invalidCost.BcryptCost = bcrypt.MaxCost + 1
+
+	invalidArgon2id := argon2idConfig
+	invalidArgon2id.Argon2id.Parallelism = 0
+
+	unknown := DefaultConfig()
+	unknown.Algorithm = "scrypt"
+
+	testCaseList := []struct {
+		name          string
+		config        Config
+		expectedHash  string
+		expectedError bool
+	}{
+		{name: "bcrypt", config: DefaultConfig(), expectedHash: "$2a$10$"},
+		{name: "argon2id", config: argon2idConfig, expectedHash: "$argon2id$v=19$m=1024,t=1,p=1$"},
+		{name: "invalid bcrypt cost", config: invalidCost, expectedError: true},
+		{name: "invalid argon2id parameters", config: invalidArgon2id, expectedError: true},
+		{name: "unknown algorithm", config: unknown, expectedError: true},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			hasher, err := New(testCase.config)
+			if testCase.expectedError {
+				assert.Nil(t, hasher)
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			hash, err := hasher.Hash("password123")
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, testCase.expectedHash), hash)
+		})
+	}
+}
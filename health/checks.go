@@ -0,0 +1,69 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+
+	"golang-template/database"
+	"golang-template/httpclient"
+)
+
+// Database pings db.
+func Database(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// Migrations fails while embedded migrations are not applied yet, e.g. when
+// the server runs with auto migration disabled.
+func Migrations(migrator database.Migrator) Check {
+	return func(ctx context.Context) error {
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+
+		pending := 0
+		for _, status := range statuses {
+			if status.AppliedAt == nil {
+				pending++
+			}
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d pending migrations", pending)
+		}
+		return nil
+	}
+}
+
+// DiskSpace fails when the file system holding path has less than
+// minFreeBytes available.
+func DiskSpace(path string, minFreeBytes uint64) Check {
+	return func(ctx context.Context) error {
+		free, err := freeDiskSpace(filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		if free < minFreeBytes {
+			return fmt.Errorf("%d MB free, need %d MB", free>>20, minFreeBytes>>20)
+		}
+		return nil
+	}
+}
+
+// HTTP fails when a GET of url errors or returns a 4xx or 5xx status.
+func HTTP(client httpclient.HttpClient, url string) Check {
+	return func(ctx context.Context) error {
+		response, err := client.R().SetContext(ctx).Get(url)
+		if err != nil {
+			return err
+		}
+		if response.IsError() {
+			return fmt.Errorf("unexpected status %d", response.StatusCode())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"golang-template/database"
+	"golang-template/httpclient"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabase(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, Database(db)(context.Background()))
+	db.Close()
+	assert.Error(t, Database(db)(context.Background()))
+}
+
+func TestMigrations(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	migrator, err := database.NewMigrator(db, fstest.MapFS{
+		"000001_create_items.up.sql":   {Data: []byte(`CREATE TABLE items (id integer primary key);`)},
+		"000001_create_items.down.sql": {Data: []byte(`DROP TABLE items;`)},
+	}, database.MigratorConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := Migrations(migrator)
+
+	assert.EqualError(t, check(context.Background()), "1 pending migrations")
+	_, err = migrator.Up()
+	assert.NoError(t, err)
+	assert.NoError(t, check(context.Background()))
+}
+
+func TestDiskSpace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+
+	assert.NoError(t, DiskSpace(path, 0)(context.Background()))
+	assert.Error(t, DiskSpace(path, 1<<62)(context.Background()))
+}
+
+func TestHTTP(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		statusCode    int
+		expectedError bool
+	}{
+		{name: "ok", statusCode: http.StatusOK},
+		{name: "server error", statusCode: http.StatusServiceUnavailable, expectedError: true},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(testCase.statusCode)
+			}))
+			defer server.Close()
+			client := httpclient.NewHttpClient().SetDebug(false).SetRetryCount(0)
+
+			err := HTTP(client, server.URL)(context.Background())
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package health
+
+import (
+	"errors"
+	"runtime"
+)
+
+func freeDiskSpace(dir string) (uint64, error) {
+	return 0, errors.New("disk space check is not supported on " + runtime.GOOS)
+}
@@ -0,0 +1,208 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang-template/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+var ErrTimeout = errors.New("check timed out")
+
+type Check func(ctx context.Context) error
+
+type CheckConfig struct {
+	// Critical checks make the service unready when they fail, the others
+	// only degrade the report.
+	Critical bool
+	// Timeout bounds a single run, Config.Timeout is used when it is zero.
+	Timeout time.Duration
+}
+
+type Config struct {
+	Timeout time.Duration `yaml:"timeout" env:"HEALTH_CHECK_TIMEOUT" validate:"gt=0"`
+	// CacheTTL is how long a result is reused, so frequent probes don't
+	// hammer the dependencies.
+	CacheTTL      time.Duration `yaml:"cacheTTL" env:"HEALTH_CACHE_TTL" validate:"gte=0"`
+	MinFreeDiskMB int           `yaml:"minFreeDiskMB" env:"HEALTH_MIN_FREE_DISK_MB" validate:"gte=0"`
+	// Dependencies are URLs of outbound services that are pinged with a GET.
+	Dependencies []string `yaml:"dependencies" env:"HEALTH_DEPENDENCIES" validate:"dive,url"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Timeout:       2 * time.Second,
+		CacheTTL:      5 * time.Second,
+		MinFreeDiskMB: 100,
+	}
+}
+
+type CheckResult struct {
+	Name      string    `json:"name"`
+	Status    Status    `json:"status"`
+	Critical  bool      `json:"critical"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checkedAt"`
+	Error     string    `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+type Registry interface {
+	// Register adds a named check. Checks run concurrently.
+	Register(name string, check Check, config CheckConfig)
+	Report(ctx context.Context) Report
+	// Ready reports whether every critical check passes.
+	Ready(ctx context.Context) bool
+}
+
+type entry struct {
+	name    string
+	check   Check
+	config  CheckConfig
+	mu      sync.Mutex
+	result  CheckResult
+	expires time.Time
+}
+
+type registry struct {
+	mu      sync.Mutex
+	entries []*entry
+	config  Config
+	now     func() time.Time
+}
+
+func NewRegistry(config Config) Registry {
+	return &registry{config: config, now: time.Now}
+}
+
+func (r *registry) Register(name string, check Check, config CheckConfig) {
+	if config.Timeout <= 0 {
+		config.Timeout = r.config.Timeout
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, &entry{name: name, check: check, config: config})
+}
+
+func (r *registry) Report(ctx context.Context) Report {
+	r.mu.Lock()
+	entries := append([]*entry(nil), r.entries...)
+	r.mu.Unlock()
+
+	report := Report{Status: StatusUp, Checks: make([]CheckResult, len(entries))}
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func(i int, e *entry) {
+			defer wg.Done()
+			report.Checks[i] = r.run(ctx, e)
+		}(i, e)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status == StatusUp {
+			continue
+		}
+		if result.Critical {
+			report.Status = StatusDown
+			break
+		}
+		report.Status = StatusDegraded
+	}
+	return report
+}
+
+func (r *registry) Ready(ctx context.Context) bool {
+	return r.Report(ctx).Status != StatusDown
+}
+
+// run returns the cached result of e or runs it. Concurrent callers wait for
+// a single run instead of starting their own.
+func (r *registry) run(ctx context.Context, e *entry) CheckResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	start := r.now()
+	if start.Before(e.expires) {
+		return e.result
+	}
+
+	// The result is shared with other callers, so it must not fail because
+	// this caller went away.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.config.Timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- e.check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ErrTimeout
+	}
+
+	result := CheckResult{
+		Name:      e.name,
+		Status:    StatusUp,
+		Critical:  e.config.Critical,
+		Duration:  r.now().Sub(start).String(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	if result.Status != e.result.Status && (e.result.Status != "" || result.Status == StatusDown) {
+		log := logger.FromContext(ctx).With(logger.Fields{"check": e.name, "critical": e.config.Critical})
+		if err != nil {
+			log.Warn("Health check failed: ", err)
+		} else {
+			log.Info("Health check recovered")
+		}
+	}
+
+	e.result = result
+	e.expires = start.Add(r.config.CacheTTL)
+	return result
+}
+
+// Handler serves the report as JSON, with 503 when a critical check fails.
+// Check errors can reveal internal addresses, so they are only included when
+// showErrors is set.
+func Handler(registry Registry, showErrors bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		report := registry.Report(c.UserContext())
+		if !showErrors {
+			for i := range report.Checks {
+				report.Checks[i].Error = ""
+			}
+		}
+
+		status := fiber.StatusOK
+		if report.Status == StatusDown {
+			status = fiber.StatusServiceUnavailable
+		}
+		return c.Status(status).JSON(report)
+	}
+}
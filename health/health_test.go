@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func passing(ctx context.Context) error {
+	return nil
+}
+
+func failing(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestRegistry_Report(t *testing.T) {
+	testCaseList := []struct {
+		name           string
+		setup          func(registry Registry)
+		expectedStatus Status
+		expectedReady  bool
+	}{
+		{
+			name: "all up",
+			setup: func(registry Registry) {
+				registry.Register("database", passing, CheckConfig{Critical: true})
+				registry.Register("mail", passing, CheckConfig{})
+			},
+			expectedStatus: StatusUp,
+			expectedReady:  true,
+		},
+		{
+			name: "non-critical down",
+			setup: func(registry Registry) {
+				registry.Register("database", passing, CheckConfig{Critical: true})
+				registry.Register("mail", failing, CheckConfig{})
+			},
+			expectedStatus: StatusDegraded,
+			expectedReady:  true,
+		},
+		{
+			name: "critical down",
+			setup: func(registry Registry) {
+				registry.Register("mail", failing, CheckConfig{})
+				registry.Register("database", failing, CheckConfig{Critical: true})
+			},
+			expectedStatus: StatusDown,
+			expectedReady:  false,
+		},
+		{
+			name: "critical timeout",
+			setup: func(registry Registry) {
+				registry.Register("database", func(ctx context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				}, CheckConfig{Critical: true, Timeout: 10 * time.Millisecond})
+			},
+			expectedStatus: StatusDown,
+			expectedReady:  false,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			registry := NewRegistry(DefaultConfig())
+			testCase.setup(registry)
+
+			report := registry.Report(context.Background())
+
+			assert.Equal(t, testCase.expectedStatus, report.Status)
+			assert.Equal(t, testCase.expectedReady, registry.Ready(context.Background()))
+		})
+	}
+}
+
+func TestRegistry_Cache(t *testing.T) {
+	now := time.Now()
+	registry := NewRegistry(Config{Timeout: time.Second, CacheTTL: 5 * time.Second}).(*registry)
+	registry.now = func() time.Time { return now }
+
+	calls := 0
+	registry.Register("database", func(ctx context.Context) error {
+		calls++
+		return nil
+	}, CheckConfig{Critical: true})
+
+	registry.Report(context.Background())
+	registry.Report(context.Background())
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(6 * time.Second)
+	registry.Report(context.Background())
+	assert.Equal(t, 2, calls)
+}
+
+func TestHandler(t *testing.T) {
+	testCaseList := []struct {
+		name               string
+		check              Check
+		showErrors         bool
+		expectedStatusCode int
+		expectedError      string
+	}{
+		{name: "up", check: passing, expectedStatusCode: fiber.StatusOK},
+		{name: "down hides errors", check: failing, expectedStatusCode: fiber.StatusServiceUnavailable},
+		{name: "down shows errors", check: failing, showErrors: true, expectedStatusCode: fiber.StatusServiceUnavailable, expectedError: "connection refused"},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			registry := NewRegistry(DefaultConfig())
+			registry.Register("database", testCase.check, CheckConfig{Critical: true})
+			app := fiber.New()
+			app.Get("/health", Handler(registry, testCase.showErrors))
+
+			response, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/health", nil), -1)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
+
+			body, _ := io.ReadAll(response.Body)
+			var report Report
+			assert.NoError(t, json.Unmarshal(body, &report))
+			assert.Len(t, report.Checks, 1)
+			assert.Equal(t, "database", report.Checks[0].Name)
+			assert.Equal(t, testCase.expectedError, report.Checks[0].Error)
+		})
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type HttpClient = *resty.Client
 
+// NewHttpClient returns a client whose requests are traced as child spans of
+// the request context and carry a traceparent header, so pass it along with
+// client.R().SetContext(ctx).
 func NewHttpClient() HttpClient {
 	client := resty.New()
 	client.SetDebug(true)
@@ -16,6 +20,7 @@ func NewHttpClient() HttpClient {
 	client.SetRetryWaitTime(1 * time.Second)
 	client.SetRetryMaxWaitTime(5 * time.Second)
 	client.SetDebug(true)
+	client.SetTransport(otelhttp.NewTransport(client.GetClient().Transport))
 
 	return client
 }
@@ -0,0 +1,65 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"golang-template/jobs"
+	"golang-template/logger"
+)
+
+type Config struct {
+	Enabled bool `yaml:"enabled" env:"IDEMPOTENCY_ENABLED"`
+	// TTL is how long a key and its stored response are kept.
+	TTL             time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" validate:"gt=0"`
+	CleanupInterval time.Duration `yaml:"cleanupInterval" env:"IDEMPOTENCY_CLEANUP_INTERVAL" validate:"gt=0"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Enabled:         true,
+		TTL:             24 * time.Hour,
+		CleanupInterval: time.Hour,
+	}
+}
+
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// Record is the stored state of a key. Fingerprint identifies the request
+// that first used it and Response is nil while that request is running.
+type Record struct {
+	Fingerprint string
+	Response    *Response
+}
+
+type Store interface {
+	// Reserve claims key for the request with fingerprint until ttl has
+	// passed. When the key is already taken it returns the existing record
+	// and false instead.
+	Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (Record, bool, error)
+	// Complete stores the response of a reserved key.
+	Complete(ctx context.Context, key string, response Response) error
+	// Release frees a reserved key so the request can be retried.
+	Release(ctx context.Context, key string) error
+	// DeleteExpired removes expired keys and returns how many were removed.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// StartCleanup deletes expired keys from store every interval until the
+// returned function is called, which waits for a running cleanup to finish.
+func StartCleanup(store Store, interval time.Duration) func(ctx context.Context) error {
+	return jobs.Start("idempotency cleanup", interval, func(ctx context.Context) error {
+		deleted, err := store.DeleteExpired(ctx)
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			logger.FromContext(ctx).With(logger.Fields{"deleted": deleted}).Debug("Deleted expired idempotency keys")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,30 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStartCleanup(t *testing.T) {
+	deleted := make(chan struct{}, 1)
+	storeMock := NewStoreMock()
+	storeMock.On("DeleteExpired", mock.Anything).Return(int64(0), nil).Run(func(args mock.Arguments) {
+		select {
+		case deleted <- struct{}{}:
+		default:
+		}
+	})
+
+	stop := StartCleanup(storeMock, time.Millisecond)
+
+	select {
+	case <-deleted:
+	case <-time.After(time.Second):
+		t.Fatal("cleanup did not run")
+	}
+	assert.NoError(t, stop(context.Background()))
+}
@@ -0,0 +1,82 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type sqliteStore struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewSQLiteStore keeps keys in the idempotency_keys table.
+func NewSQLiteStore(db *sql.DB) Store {
+	return &sqliteStore{db: db, now: time.Now}
+}
+
+func (s *sqliteStore) Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (Record, bool, error) {
+	now := s.now()
+	// An expired key is taken over as if it never existed.
+	query := `
+		INSERT INTO idempotency_keys (key, fingerprint, created_at, expires_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			fingerprint = excluded.fingerprint,
+			status_code = 0,
+			content_type = '',
+			body = NULL,
+			created_at = excluded.created_at,
+			expires_at = excluded.expires_at
+		WHERE idempotency_keys.expires_at < ?
+	`
+	result, err := s.db.ExecContext(ctx, query, key, fingerprint, now.UnixNano(), now.Add(ttl).UnixNano(), now.UnixNano())
+	if err != nil {
+		return Record{}, false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return Record{}, false, err
+	}
+	if rowsAffected > 0 {
+		return Record{Fingerprint: fingerprint}, true, nil
+	}
+
+	var record Record
+	var response Response
+	query = `SELECT fingerprint, status_code, content_type, body FROM idempotency_keys WHERE key = ?`
+	err = s.db.QueryRowContext(ctx, query, key).Scan(&record.Fingerprint, &response.StatusCode, &response.ContentType, &response.Body)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released in the meantime, report it as still running so the
+		// client retries.
+		return Record{Fingerprint: fingerprint}, false, nil
+	}
+	if err != nil {
+		return Record{}, false, err
+	}
+	if response.StatusCode != 0 {
+		record.Response = &response
+	}
+	return record, false, nil
+}
+
+func (s *sqliteStore) Complete(ctx context.Context, key string, response Response) error {
+	query := `UPDATE idempotency_keys SET status_code = ?, content_type = ?, body = ? WHERE key = ?`
+	_, err := s.db.ExecContext(ctx, query, response.StatusCode, response.ContentType, response.Body, key)
+	return err
+}
+
+func (s *sqliteStore) Release(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = ? AND status_code = 0`, key)
+	return err
+}
+
+func (s *sqliteStore) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < ?`, s.now().UnixNano())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
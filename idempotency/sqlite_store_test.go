@@ -0,0 +1,75 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang-template/database"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(t *testing.T, now *time.Time) *sqliteStore {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.NewMigrator(db, database.Migrations(), database.MigratorConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewSQLiteStore(db).(*sqliteStore)
+	store.now = func() time.Time { return *now }
+	return store
+}
+
+func TestSQLiteStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newTestStore(t, &now)
+	response := Response{StatusCode: 201, ContentType: "application/json", Body: []byte(`{"status":"success"}`)}
+
+	record, reserved, err := store.Reserve(ctx, "anonymous:key-1", "fp-1", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Equal(t, Record{Fingerprint: "fp-1"}, record)
+
+	record, reserved, err = store.Reserve(ctx, "anonymous:key-1", "fp-1", time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Nil(t, record.Response, "still in progress")
+
+	assert.NoError(t, store.Complete(ctx, "anonymous:key-1", response))
+	assert.NoError(t, store.Release(ctx, "anonymous:key-1"), "completed keys are kept")
+
+	record, reserved, err = store.Reserve(ctx, "anonymous:key-1", "fp-2", time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, Record{Fingerprint: "fp-1", Response: &response}, record)
+
+	_, _, err = store.Reserve(ctx, "anonymous:key-2", "fp-1", time.Hour)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Release(ctx, "anonymous:key-2"))
+	_, reserved, err = store.Reserve(ctx, "anonymous:key-2", "fp-2", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, reserved, "released keys can be reserved again")
+
+	now = now.Add(2 * time.Hour)
+	_, reserved, err = store.Reserve(ctx, "anonymous:key-1", "fp-2", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, reserved, "expired keys can be reserved again")
+
+	deleted, err := store.DeleteExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
@@ -0,0 +1,36 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type StoreMock struct {
+	mock.Mock
+}
+
+func NewStoreMock() *StoreMock {
+	return &StoreMock{}
+}
+
+func (m *StoreMock) Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (Record, bool, error) {
+	args := m.Mock.Called(ctx, key, fingerprint, ttl)
+	return args.Get(0).(Record), args.Bool(1), args.Error(2)
+}
+
+func (m *StoreMock) Complete(ctx context.Context, key string, response Response) error {
+	args := m.Mock.Called(ctx, key, response)
+	return args.Error(0)
+}
+
+func (m *StoreMock) Release(ctx context.Context, key string) error {
+	args := m.Mock.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *StoreMock) DeleteExpired(ctx context.Context) (int64, error) {
+	args := m.Mock.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"golang-template/logger"
+)
+
+// Start runs job every interval until the returned function is called, which
+// waits for a running job to finish. Failures are logged and the job runs
+// again on the next tick.
+func Start(name string, interval time.Duration, job func(ctx context.Context) error) func(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := job(ctx); err != nil {
+				logger.FromContext(ctx).With(logger.Fields{"job": name, "error": err.Error()}).Warn("Background job failed")
+			}
+		}
+	}()
+
+	return func(ctx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStart(t *testing.T) {
+	testCaseList := []struct {
+		name string
+		err  error
+	}{
+		{name: "job succeeds", err: nil},
+		{name: "job keeps running after a failure", err: errors.New("database is locked")},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			runs := make(chan struct{}, 2)
+			stop := Start("test", time.Millisecond, func(ctx context.Context) error {
+				select {
+				case runs <- struct{}{}:
+				default:
+				}
+				return testCase.err
+			})
+
+			for i := 0; i < 2; i++ {
+				select {
+				case <-runs:
+				case <-time.After(time.Second):
+					t.Fatal("job did not run")
+				}
+			}
+			assert.NoError(t, stop(context.Background()))
+		})
+	}
+}
+
+func TestStart_StopTimesOut(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	stop := Start("test", time.Millisecond, func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, stop(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type contextKey struct{}
+
+var defaultLogger atomic.Value
+
+func init() {
+	SetDefault(NewLogger("info"))
+}
+
+// SetDefault replaces the logger FromContext returns for contexts that do not
+// carry one, e.g. background jobs started outside a request.
+func SetDefault(logger Logger) {
+	defaultLogger.Store(&logger)
+}
+
+func Default() Logger {
+	return *defaultLogger.Load().(*Logger)
+}
+
+func WithContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger stored by WithContext, or the
+// default logger when there is none.
+func FromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return logger
+	}
+	return Default()
+}
@@ -1,44 +1,212 @@
 package logger
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
 	logrus "github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-type Logger struct {
-	logger *logrus.Logger
+type Config struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" validate:"oneof=trace debug info warn error"`
+	Format string `yaml:"format" env:"LOG_FORMAT" validate:"oneof=json console"`
+	// Outputs lists the sinks every entry is written to.
+	Outputs []string     `yaml:"outputs" env:"LOG_OUTPUTS" validate:"min=1,dive,oneof=stdout stderr file syslog"`
+	File    FileConfig   `yaml:"file"`
+	Syslog  SyslogConfig `yaml:"syslog"`
+}
+
+// FileConfig rotates the log file once it reaches MaxSizeMB and removes
+// rotated files older than MaxAge or beyond MaxBackups (0 keeps them all).
+type FileConfig struct {
+	Path       string        `yaml:"path" env:"LOG_FILE_PATH"`
+	MaxSizeMB  int           `yaml:"maxSizeMB" env:"LOG_FILE_MAX_SIZE_MB" validate:"gte=0"`
+	MaxAge     time.Duration `yaml:"maxAge" env:"LOG_FILE_MAX_AGE" validate:"gte=0"`
+	MaxBackups int           `yaml:"maxBackups" env:"LOG_FILE_MAX_BACKUPS" validate:"gte=0"`
+	Compress   bool          `yaml:"compress" env:"LOG_FILE_COMPRESS"`
+}
+
+// SyslogConfig connects to the local syslog daemon when Network and Address
+// are empty, or to the given socket otherwise.
+type SyslogConfig struct {
+	Network string `yaml:"network" env:"LOG_SYSLOG_NETWORK"`
+	Address string `yaml:"address" env:"LOG_SYSLOG_ADDRESS"`
+	Tag     string `yaml:"tag" env:"LOG_SYSLOG_TAG"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Level:   "info",
+		Format:  "json",
+		Outputs: []string{"stderr"},
+		File: FileConfig{
+			Path:       "./logs/app.log",
+			MaxSizeMB:  100,
+			MaxAge:     7 * 24 * time.Hour,
+			MaxBackups: 10,
+		},
+		Syslog: SyslogConfig{
+			Tag: "golang-template",
+		},
+	}
+}
+
+type Fields map[string]interface{}
+
+type Logger interface {
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+	Fatal(args ...interface{})
+	Panic(args ...interface{})
+	// With returns a logger that adds fields to every entry. It shares
+	// level and sinks with its parent.
+	With(fields Fields) Logger
+	Request(request map[string]interface{})
+	Response(response map[string]interface{})
+	Level() string
+	SetLevel(level string) error
+	// Close releases the file and syslog sinks shared by every logger
+	// derived from the same root.
+	Close() error
+}
+
+type logger struct {
+	entry   *logrus.Entry
+	closers []io.Closer
+}
+
+func NewLogger(level string) Logger {
+	config := DefaultConfig()
+	config.Level = level
+	logger, _ := New(config)
+	return logger
 }
 
-func NewLogger() Logger {
-	var logger = logrus.New()
-	logger.SetFormatter(&logrus.JSONFormatter{PrettyPrint: true})
+// New builds a logger from config. Close it on shutdown to release the file
+// and syslog sinks.
+func New(config Config) (Logger, error) {
+	var root = logrus.New()
+
+	switch config.Format {
+	case "console":
+		root.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	default:
+		root.SetFormatter(&logrus.JSONFormatter{})
+	}
+
+	if parsedLevel, err := logrus.ParseLevel(config.Level); err == nil {
+		root.SetLevel(parsedLevel)
+	}
+
+	result := &logger{entry: logrus.NewEntry(root)}
+	var writers []io.Writer
+	for _, output := range config.Outputs {
+		switch output {
+		case "stdout":
+			writers = append(writers, os.Stdout)
+		case "stderr":
+			writers = append(writers, os.Stderr)
+		case "file":
+			file := &lumberjack.Logger{
+				Filename:   config.File.Path,
+				MaxSize:    config.File.MaxSizeMB,
+				MaxAge:     int(math.Ceil(config.File.MaxAge.Hours() / 24)),
+				MaxBackups: config.File.MaxBackups,
+				Compress:   config.File.Compress,
+			}
+			writers = append(writers, file)
+			result.closers = append(result.closers, file)
+		case "syslog":
+			hook, closer, err := newSyslogHook(config.Syslog)
+			if err != nil {
+				_ = result.Close()
+				return nil, fmt.Errorf("syslog output: %w", err)
+			}
+			root.AddHook(hook)
+			result.closers = append(result.closers, closer)
+		default:
+			_ = result.Close()
+			return nil, fmt.Errorf("unknown log output %q", output)
+		}
+	}
+
+	switch len(writers) {
+	case 0:
+		root.SetOutput(io.Discard)
+	case 1:
+		root.SetOutput(writers[0])
+	default:
+		root.SetOutput(io.MultiWriter(writers...))
+	}
+
+	return result, nil
+}
+
+func (l *logger) With(fields Fields) Logger {
+	return &logger{entry: l.entry.WithFields(logrus.Fields(fields)), closers: l.closers}
+}
+
+// Level returns the current minimum level.
+func (l *logger) Level() string {
+	return l.entry.Logger.GetLevel().String()
+}
+
+// SetLevel changes the minimum level of a running logger.
+func (l *logger) SetLevel(level string) error {
+	parsedLevel, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.entry.Logger.SetLevel(parsedLevel)
+	return nil
+}
+
+// Close flushes and releases the file and syslog sinks.
+func (l *logger) Close() error {
+	var errs []error
+	for _, closer := range l.closers {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
 
-	return Logger{logger: logger}
+func (l *logger) Request(request map[string]interface{}) {
+	l.entry.WithFields(request).Info("Request")
 }
 
-func (l *Logger) Request(request map[string]interface{}) {
-	l.logger.WithFields(request).Info("Request")
+func (l *logger) Response(response map[string]interface{}) {
+	l.entry.WithFields(response).Info("Response")
 }
 
-func (l *Logger) Response(response map[string]interface{}) {
-	l.logger.WithFields(response).Info("Response")
+func (l *logger) Debug(args ...interface{}) {
+	l.entry.Debug(args...)
 }
 
-func (l *Logger) Debug(args ...interface{}) {
-	l.logger.Debug(args...)
+func (l *logger) Info(args ...interface{}) {
+	l.entry.Info(args...)
 }
 
-func (l *Logger) Error(args ...interface{}) {
-	l.logger.Error(args...)
+func (l *logger) Error(args ...interface{}) {
+	l.entry.Error(args...)
 }
 
-func (l *Logger) Fatal(args ...interface{}) {
-	l.logger.Fatal(args...)
+func (l *logger) Fatal(args ...interface{}) {
+	l.entry.Fatal(args...)
 }
 
-func (l *Logger) Panic(args ...interface{}) {
-	l.logger.Panic(args...)
+func (l *logger) Panic(args ...interface{}) {
+	l.entry.Panic(args...)
 }
 
-func (l *Logger) Warn(args ...interface{}) {
-	l.logger.Warn(args...)
+func (l *logger) Warn(args ...interface{}) {
+	l.entry.Warn(args...)
 }
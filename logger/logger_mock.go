@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type LoggerMock struct {
+	mock.Mock
+}
+
+func NewLoggerMock() *LoggerMock {
+	return &LoggerMock{}
+}
+
+func (m *LoggerMock) Debug(args ...interface{}) {
+	m.Mock.Called(args...)
+}
+
+func (m *LoggerMock) Info(args ...interface{}) {
+	m.Mock.Called(args...)
+}
+
+func (m *LoggerMock) Warn(args ...interface{}) {
+	m.Mock.Called(args...)
+}
+
+func (m *LoggerMock) Error(args ...interface{}) {
+	m.Mock.Called(args...)
+}
+
+func (m *LoggerMock) Fatal(args ...interface{}) {
+	m.Mock.Called(args...)
+}
+
+func (m *LoggerMock) Panic(args ...interface{}) {
+	m.Mock.Called(args...)
+}
+
+func (m *LoggerMock) With(fields Fields) Logger {
+	args := m.Mock.Called(fields)
+	return args.Get(0).(Logger)
+}
+
+func (m *LoggerMock) Request(request map[string]interface{}) {
+	m.Mock.Called(request)
+}
+
+func (m *LoggerMock) Response(response map[string]interface{}) {
+	m.Mock.Called(response)
+}
+
+func (m *LoggerMock) Level() string {
+	args := m.Mock.Called()
+	return args.String(0)
+}
+
+func (m *LoggerMock) SetLevel(level string) error {
+	args := m.Mock.Called(level)
+	return args.Error(0)
+}
+
+func (m *LoggerMock) Close() error {
+	args := m.Mock.Called()
+	return args.Error(0)
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		config        func(config *Config)
+		expectedError bool
+	}{
+		{
+			name:   "defaults",
+			config: func(config *Config) {},
+		},
+		{
+			name: "console format on stdout",
+			config: func(config *Config) {
+				config.Format = "console"
+				config.Outputs = []string{"stdout"}
+			},
+		},
+		{
+			name: "unknown output",
+			config: func(config *Config) {
+				config.Outputs = []string{"kafka"}
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			config := DefaultConfig()
+			testCase.config(&config)
+
+			logger, err := New(config)
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, logger.Close())
+		})
+	}
+}
+
+func TestNew_FileOutput(t *testing.T) {
+	config := DefaultConfig()
+	config.Level = "warn"
+	config.Outputs = []string{"file"}
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+
+	logger, err := New(config)
+	assert.NoError(t, err)
+
+	logger.Info("hidden")
+	logger.Warn("written")
+	assert.NoError(t, logger.Close())
+
+	content, err := os.ReadFile(config.File.Path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"msg":"written"`)
+	assert.NotContains(t, string(content), "hidden")
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	logger := NewLogger("info")
+
+	assert.NoError(t, logger.SetLevel("debug"))
+	assert.Equal(t, "debug", logger.Level())
+	assert.Error(t, logger.SetLevel("verbose"))
+	assert.Equal(t, "debug", logger.Level())
+}
+
+func TestLogger_With(t *testing.T) {
+	config := DefaultConfig()
+	config.Outputs = []string{"file"}
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+
+	logger, err := New(config)
+	assert.NoError(t, err)
+
+	logger.With(Fields{"request_id": "abc"}).Info("scoped")
+	logger.Info("unscoped")
+	assert.NoError(t, logger.Close())
+
+	content, err := os.ReadFile(config.File.Path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"request_id":"abc"`)
+	assert.NotContains(t, lines[1], "request_id")
+}
+
+func TestFromContext(t *testing.T) {
+	logger := NewLoggerMock()
+
+	assert.Equal(t, Logger(logger), FromContext(WithContext(context.Background(), logger)))
+	assert.Equal(t, Default(), FromContext(context.Background()))
+}
@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const Redacted = "[REDACTED]"
+
+const mimeForm = "application/x-www-form-urlencoded"
+
+// RedactConfig describes what is removed from logged requests and responses.
+type RedactConfig struct {
+	// Fields are case-insensitive glob patterns matched against JSON keys at
+	// any depth, e.g. "*password*".
+	Fields []string `yaml:"fields" env:"LOG_REDACT_FIELDS"`
+	// Paths are dotted JSON paths from the body root, "*" matches any key or
+	// array index, e.g. "users.*.email".
+	Paths []string `yaml:"paths" env:"LOG_REDACT_PATHS"`
+	// Headers are header names whose values are never logged.
+	Headers []string `yaml:"headers" env:"LOG_REDACT_HEADERS"`
+	// Patterns are regular expressions replaced in every logged string value.
+	Patterns []string `yaml:"patterns" env:"LOG_REDACT_PATTERNS"`
+	// MaxBodySize truncates logged bodies to this many bytes, 0 disables it.
+	MaxBodySize int `yaml:"maxBodySize" env:"LOG_MAX_BODY_SIZE" validate:"gte=0"`
+}
+
+func DefaultRedactConfig() RedactConfig {
+	return RedactConfig{
+		Fields:  []string{"*password*", "*secret*", "*token*", "*apikey*", "*api_key*", "authorization", "cookie", "otp", "*otpauth*", "*qrcode*", "*recoverycode*"},
+		Headers: []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"},
+		Patterns: []string{
+			`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`,
+			`(?i)bearer\s+[A-Za-z0-9._~+/-]+=*`,
+			`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+		},
+		MaxBodySize: 4096,
+	}
+}
+
+type Redactor struct {
+	fields      []string
+	paths       [][]string
+	headers     map[string]bool
+	patterns    []*regexp.Regexp
+	maxBodySize int
+}
+
+func NewRedactor(config RedactConfig) (*Redactor, error) {
+	redactor := &Redactor{
+		headers:     map[string]bool{},
+		maxBodySize: config.MaxBodySize,
+	}
+
+	for _, field := range config.Fields {
+		pattern := strings.ToLower(field)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("redact field %q: %w", field, err)
+		}
+		redactor.fields = append(redactor.fields, pattern)
+	}
+	for _, jsonPath := range config.Paths {
+		redactor.paths = append(redactor.paths, strings.Split(jsonPath, "."))
+	}
+	for _, header := range config.Headers {
+		redactor.headers[http.CanonicalHeaderKey(header)] = true
+	}
+	for _, pattern := range config.Patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redact pattern %q: %w", pattern, err)
+		}
+		redactor.patterns = append(redactor.patterns, compiled)
+	}
+
+	return redactor, nil
+}
+
+// Body returns a loggable copy of a request or response body with the given
+// Content-Type. JSON and form-encoded bodies are redacted field by field.
+// Anything else, such as multipart forms, could hold secrets that can't be
+// found, so only its size is logged. Bodies larger than MaxBodySize are logged
+// as a truncated string.
+func (r *Redactor) Body(contentType string, body []byte) any {
+	if len(body) == 0 {
+		return nil
+	}
+
+	var parsed any
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if mediaType != mimeForm {
+			return omitted(body)
+		}
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return omitted(body)
+		}
+		parsed = formValues(values)
+	}
+
+	redacted := r.value(parsed, nil)
+	if r.maxBodySize <= 0 {
+		return redacted
+	}
+	encoded, err := json.Marshal(redacted)
+	if err != nil || len(encoded) <= r.maxBodySize {
+		return redacted
+	}
+	return r.truncate(string(encoded))
+}
+
+// formValues converts a parsed form to the shape of a JSON object, keys with
+// a single value map to it and the others to a list.
+func formValues(values url.Values) map[string]any {
+	result := make(map[string]any, len(values))
+	for key, list := range values {
+		if len(list) == 1 {
+			result[key] = list[0]
+			continue
+		}
+		items := make([]any, len(list))
+		for i, item := range list {
+			items[i] = item
+		}
+		result[key] = items
+	}
+	return result
+}
+
+func omitted(body []byte) string {
+	return fmt.Sprintf("[OMITTED %d bytes]", len(body))
+}
+
+// Headers flattens headers for logging with sensitive values redacted.
+func (r *Redactor) Headers(headers map[string][]string) map[string]string {
+	result := make(map[string]string, len(headers))
+	for name, values := range headers {
+		if r.headers[http.CanonicalHeaderKey(name)] {
+			result[name] = Redacted
+			continue
+		}
+		result[name] = r.String(strings.Join(values, ", "))
+	}
+	return result
+}
+
+// String replaces every match of the configured patterns.
+func (r *Redactor) String(value string) string {
+	for _, pattern := range r.patterns {
+		value = pattern.ReplaceAllString(value, Redacted)
+	}
+	return value
+}
+
+func (r *Redactor) value(value any, jsonPath []string) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(typed))
+		for key, child := range typed {
+			childPath := append(jsonPath[:len(jsonPath):len(jsonPath)], key)
+			if r.matchField(key) || r.matchPath(childPath) {
+				result[key] = Redacted
+				continue
+			}
+			result[key] = r.value(child, childPath)
+		}
+		return result
+	case []any:
+		result := make([]any, len(typed))
+		for i, child := range typed {
+			childPath := append(jsonPath[:len(jsonPath):len(jsonPath)], strconv.Itoa(i))
+			if r.matchPath(childPath) {
+				result[i] = Redacted
+				continue
+			}
+			result[i] = r.value(child, childPath)
+		}
+		return result
+	case string:
+		return r.String(typed)
+	default:
+		return value
+	}
+}
+
+func (r *Redactor) matchField(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range r.fields {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) matchPath(jsonPath []string) bool {
+	for _, pattern := range r.paths {
+		if len(pattern) != len(jsonPath) {
+			continue
+		}
+		matched := true
+		for i, segment := range pattern {
+			if segment != "*" && segment != jsonPath[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) truncate(value string) string {
+	if r.maxBodySize <= 0 || len(value) <= r.maxBodySize {
+		return value
+	}
+	kept := strings.ToValidUTF8(value[:r.maxBodySize], "")
+	return fmt.Sprintf("%s...(truncated %d bytes)", kept, len(value)-len(kept))
+}
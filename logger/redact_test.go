@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_Body(t *testing.T) {
+	testCaseList := []struct {
+		name         string
+		config       RedactConfig
+		contentType  string
+		body         string
+		expectedBody any
+	}{
+		{
+			name:         "empty body",
+			config:       DefaultRedactConfig(),
+			body:         "",
+			expectedBody: nil,
+		},
+		{
+			name:   "field names at any depth",
+			config: DefaultRedactConfig(),
+			body:   `{"username":"john","password":"secret","profile":{"newPassword":"secret"},"tokens":[{"refreshToken":"abc"}]}`,
+			expectedBody: map[string]any{
+				"username": "john",
+				"password": Redacted,
+				"profile":  map[string]any{"newPassword": Redacted},
+				"tokens":   Redacted,
+			},
+		},
+		{
+			name:   "json paths",
+			config: RedactConfig{Paths: []string{"users.*.phone", "card"}},
+			body:   `{"users":[{"name":"a","phone":"0812345678"}],"card":"4111","cardholder":"a"}`,
+			expectedBody: map[string]any{
+				"users":      []any{map[string]any{"name": "a", "phone": Redacted}},
+				"card":       Redacted,
+				"cardholder": "a",
+			},
+		},
+		{
+			name:   "patterns in values",
+			config: DefaultRedactConfig(),
+			body:   `{"message":"sent to john@example.com","note":"Bearer abc.def"}`,
+			expectedBody: map[string]any{
+				"message": "sent to " + Redacted,
+				"note":    Redacted,
+			},
+		},
+		{
+			name:        "form body",
+			config:      DefaultRedactConfig(),
+			contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			body:        "username=john&password=Secret123&email=john@example.com&tag=a&tag=b",
+			expectedBody: map[string]any{
+				"username": "john",
+				"password": Redacted,
+				"email":    Redacted,
+				"tag":      []any{"a", "b"},
+			},
+		},
+		{
+			name:         "invalid form body",
+			config:       DefaultRedactConfig(),
+			contentType:  "application/x-www-form-urlencoded",
+			body:         "password=%zz",
+			expectedBody: "[OMITTED 12 bytes]",
+		},
+		{
+			name:         "multipart body",
+			config:       DefaultRedactConfig(),
+			contentType:  "multipart/form-data; boundary=x",
+			body:         "--x\r\nContent-Disposition: form-data; name=\"password\"\r\n\r\nSecret123\r\n--x--",
+			expectedBody: "[OMITTED 72 bytes]",
+		},
+		{
+			name:         "text body",
+			config:       DefaultRedactConfig(),
+			contentType:  "text/plain",
+			body:         "password=Secret123",
+			expectedBody: "[OMITTED 18 bytes]",
+		},
+		{
+			name:         "truncates large bodies",
+			config:       RedactConfig{MaxBodySize: 10},
+			body:         `{"description":"a long description"}`,
+			expectedBody: `{"descript...(truncated 26 bytes)`,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			redactor, err := NewRedactor(testCase.config)
+			assert.NoError(t, err)
+
+			assert.Equal(t, testCase.expectedBody, redactor.Body(testCase.contentType, []byte(testCase.body)))
+		})
+	}
+}
+
+func TestRedactor_Headers(t *testing.T) {
+	redactor, err := NewRedactor(DefaultRedactConfig())
+	assert.NoError(t, err)
+
+	headers := redactor.Headers(map[string][]string{
+		"Authorization": {"Bearer abc"},
+		"cookie":        {"session=abc"},
+		"Accept":        {"application/json", "text/plain"},
+	})
+
+	assert.Equal(t, map[string]string{
+		"Authorization": Redacted,
+		"cookie":        Redacted,
+		"Accept":        "application/json, text/plain",
+	}, headers)
+}
+
+func TestNewRedactor_InvalidPattern(t *testing.T) {
+	_, err := NewRedactor(RedactConfig{Patterns: []string{"("}})
+
+	assert.Error(t, err)
+}
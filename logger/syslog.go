@@ -0,0 +1,21 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"io"
+	"log/syslog"
+
+	logrus "github.com/sirupsen/logrus"
+	logrusSyslog "github.com/sirupsen/logrus/hooks/syslog"
+)
+
+// newSyslogHook sends every entry to syslog with a priority matching its
+// level, formatted by the logger's formatter.
+func newSyslogHook(config SyslogConfig) (logrus.Hook, io.Closer, error) {
+	hook, err := logrusSyslog.NewSyslogHook(config.Network, config.Address, syslog.LOG_INFO|syslog.LOG_DAEMON, config.Tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	return hook, hook.Writer, nil
+}
@@ -0,0 +1,14 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"errors"
+	"io"
+
+	logrus "github.com/sirupsen/logrus"
+)
+
+func newSyslogHook(config SyslogConfig) (logrus.Hook, io.Closer, error) {
+	return nil, nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,116 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DriverSMTP   = "smtp"
+	DriverOutbox = "outbox"
+)
+
+type Config struct {
+	// Driver is smtp, or outbox to keep the last messages in memory instead
+	// of sending them, which is only allowed with ENV=local.
+	Driver string `yaml:"driver" env:"MAIL_DRIVER" validate:"oneof=smtp outbox"`
+	// From is the sender, e.g. "Example <no-reply@example.com>".
+	From string `yaml:"from" env:"MAIL_FROM" validate:"required"`
+	// OutboxDir, when set, makes the outbox also write every message to an
+	// .eml file in this directory.
+	OutboxDir string     `yaml:"outboxDir" env:"MAIL_OUTBOX_DIR"`
+	SMTP      SMTPConfig `yaml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host string `yaml:"host" env:"SMTP_HOST"`
+	Port int    `yaml:"port" env:"SMTP_PORT" validate:"min=1,max=65535"`
+	// Username and Password are sent with PLAIN auth, which net/smtp only
+	// allows over TLS or to localhost.
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Driver: DriverOutbox,
+		From:   "no-reply@localhost",
+		SMTP:   SMTPConfig{Port: 587},
+	}
+}
+
+type Message struct {
+	To      string
+	Subject string
+	// Body is plain text.
+	Body string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// New returns the mailer selected by config.Driver.
+func New(config Config) (Mailer, error) {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mail sender %q: %w", config.From, err)
+	}
+
+	switch config.Driver {
+	case DriverSMTP:
+		if config.SMTP.Host == "" {
+			return nil, fmt.Errorf("SMTP host is required for the %s mail driver", DriverSMTP)
+		}
+		return NewSMTPMailer(config.SMTP, from), nil
+	case DriverOutbox, "":
+		return NewOutbox(from, config.OutboxDir), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver %q", config.Driver)
+	}
+}
+
+// format renders message as an RFC 5322 message with a quoted-printable
+// UTF-8 body.
+func format(from *mail.Address, message Message, date time.Time) ([]byte, error) {
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mail recipient %q: %w", message.To, err)
+	}
+
+	var buf bytes.Buffer
+	headers := [][2]string{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", message.Subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", uuid.NewString(), domain(from.Address))},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=utf-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", header[0], header[1])
+	}
+	buf.WriteString("\r\n")
+
+	body := quotedprintable.NewWriter(&buf)
+	body.Write([]byte(strings.ReplaceAll(message.Body, "\n", "\r\n")))
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func domain(address string) string {
+	_, domain, _ := strings.Cut(address, "@")
+	return domain
+}
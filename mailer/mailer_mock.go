@@ -0,0 +1,20 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MailerMock struct {
+	mock.Mock
+}
+
+func NewMailerMock() *MailerMock {
+	return &MailerMock{}
+}
+
+func (m *MailerMock) Send(ctx context.Context, message Message) error {
+	args := m.Mock.Called(ctx, message)
+	return args.Error(0)
+}
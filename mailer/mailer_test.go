@@ -0,0 +1,161 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testFrom = &mail.Address{Name: "Example", Address: "no-reply@example.com"}
+
+func TestNew(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		config        Config
+		expectedError string
+	}{
+		{name: "outbox", config: Config{Driver: DriverOutbox, From: "no-reply@example.com"}},
+		{name: "smtp", config: Config{Driver: DriverSMTP, From: "Example <no-reply@example.com>", SMTP: SMTPConfig{Host: "smtp.example.com", Port: 587}}},
+		{name: "smtp without host", config: Config{Driver: DriverSMTP, From: "no-reply@example.com"}, expectedError: "SMTP host is required"},
+		{name: "invalid sender", config: Config{Driver: DriverOutbox, From: "not an address"}, expectedError: "invalid mail sender"},
+		{name: "unknown driver", config: Config{Driver: "ses", From: "no-reply@example.com"}, expectedError: "unsupported mail driver"},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			mailer, err := New(testCase.config)
+			if testCase.expectedError != "" {
+				assert.ErrorContains(t, err, testCase.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, mailer)
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, err := format(testFrom, Message{To: "user@example.com", Subject: "Héllo", Body: "Line one\nLine two"}, date)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, `"Example" <no-reply@example.com>`, parsed.Header.Get("From"))
+	assert.Equal(t, "<user@example.com>", parsed.Header.Get("To"))
+	assert.Equal(t, "=?utf-8?q?H=C3=A9llo?=", parsed.Header.Get("Subject"))
+	assert.Equal(t, "Tue, 02 Jan 2024 03:04:05 +0000", parsed.Header.Get("Date"))
+	assert.True(t, strings.HasSuffix(parsed.Header.Get("Message-ID"), "@example.com>"))
+	assert.Contains(t, string(data), "\r\n\r\nLine one\r\nLine two")
+
+	_, err = format(testFrom, Message{To: "nobody"}, date)
+	assert.ErrorContains(t, err, "invalid mail recipient")
+}
+
+func TestOutbox(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	outbox := NewOutbox(testFrom, dir)
+	message := Message{To: "user@example.com", Subject: "Verify", Body: "token"}
+
+	assert.NoError(t, outbox.Send(context.Background(), message))
+	assert.Equal(t, []Message{message}, outbox.Messages())
+
+	files, err := os.ReadDir(dir)
+	if !assert.NoError(t, err) || !assert.Len(t, files, 1) {
+		return
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.Contains(t, string(data), "Subject: Verify\r\n")
+
+	assert.Error(t, outbox.Send(context.Background(), Message{To: "nobody"}))
+	assert.Len(t, outbox.Messages(), 1)
+}
+
+func TestOutbox_Size(t *testing.T) {
+	outbox := NewOutbox(testFrom, "")
+	for i := 0; i <= outboxSize; i++ {
+		assert.NoError(t, outbox.Send(context.Background(), Message{To: "user@example.com", Subject: strconv.Itoa(i)}))
+	}
+
+	messages := outbox.Messages()
+	assert.Len(t, messages, outboxSize)
+	assert.Equal(t, "1", messages[0].Subject)
+	assert.Equal(t, strconv.Itoa(outboxSize), messages[outboxSize-1].Subject)
+}
+
+func TestSMTPMailer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go serveSMTP(listener, received)
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	mailer := NewSMTPMailer(SMTPConfig{Host: host, Port: portNumber}, testFrom)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = mailer.Send(ctx, Message{To: "user@example.com", Subject: "Verify", Body: "token"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	commands := <-received
+	assert.Equal(t, "MAIL FROM:<no-reply@example.com>", commands[1])
+	assert.Equal(t, "RCPT TO:<user@example.com>", commands[2])
+	assert.Equal(t, "DATA", commands[3])
+	assert.Contains(t, commands[4], "Subject: Verify")
+}
+
+// serveSMTP accepts one connection and answers just enough of RFC 5321 for
+// net/smtp, sending the commands and the message it got to received.
+func serveSMTP(listener net.Listener, received chan<- []string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP")
+	var commands []string
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		commands = append(commands, line)
+		switch {
+		case strings.HasPrefix(line, "EHLO"):
+			text.PrintfLine("250 localhost")
+		case line == "DATA":
+			text.PrintfLine("354 go ahead")
+			data, _ := text.ReadDotBytes()
+			commands = append(commands, string(data))
+			text.PrintfLine("250 queued")
+		case line == "QUIT":
+			text.PrintfLine("221 bye")
+			received <- commands
+			return
+		default:
+			text.PrintfLine("250 ok")
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang-template/logger"
+)
+
+// outboxSize is how many messages the outbox keeps in memory, older ones are
+// dropped so a long running process doesn't grow without bound.
+const outboxSize = 100
+
+// Outbox keeps sent messages instead of delivering them, so they can be read
+// in tests through Messages or, with a directory, opened as .eml files.
+type Outbox struct {
+	from     *mail.Address
+	dir      string
+	mu       sync.Mutex
+	messages []Message
+	sent     int
+}
+
+func NewOutbox(from *mail.Address, dir string) *Outbox {
+	return &Outbox{from: from, dir: dir}
+}
+
+func (o *Outbox) Send(ctx context.Context, message Message) error {
+	now := time.Now()
+	data, err := format(o.from, message, now)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	if len(o.messages) == outboxSize {
+		o.messages = append(o.messages[:0], o.messages[1:]...)
+	}
+	o.messages = append(o.messages, message)
+	o.sent++
+	count := o.sent
+	o.mu.Unlock()
+
+	if o.dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(o.dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(o.dir, fmt.Sprintf("%s-%d.eml", now.UTC().Format("20060102T150405"), count))
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	logger.FromContext(ctx).With(logger.Fields{"path": path}).Info("Mail written to outbox")
+	return nil
+}
+
+// Messages returns the last messages sent, oldest first.
+func (o *Outbox) Messages() []Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]Message(nil), o.messages...)
+}
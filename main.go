@@ -1,12 +1,33 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"golang-template/app/handlers"
 	"golang-template/app/repositories"
 	"golang-template/app/services"
+	"golang-template/audit"
+	"golang-template/config"
 	"golang-template/database"
+	"golang-template/hasher"
+	"golang-template/health"
+	"golang-template/httpclient"
+	"golang-template/idempotency"
+	"golang-template/jobs"
 	"golang-template/logger"
+	"golang-template/mailer"
+	"golang-template/metrics"
 	"golang-template/middleware"
+	"golang-template/openapi"
+	"golang-template/ratelimit"
+	"golang-template/shutdown"
+	"golang-template/token"
+	"golang-template/tracing"
 
 	"github.com/goccy/go-json"
 
@@ -18,34 +39,255 @@ import (
 )
 
 func main() {
-	logger := logger.NewLogger()
+	cfg, err := config.Load(".env")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	redactor, err := logger.NewRedactor(cfg.Log.Redact)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logConfig := middleware.LogConfig{Redactor: redactor, SkipBodyPaths: cfg.Log.SkipBodyPaths}
+
+	appLogger, err := logger.New(cfg.Log.Config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger.SetDefault(appLogger)
+
+	shutdownManager := shutdown.NewManager(cfg.Shutdown.Delay)
+	// Registered first so it runs last and later hooks can still log.
+	shutdownManager.Register("logger", func(ctx context.Context) error {
+		return appLogger.Close()
+	})
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		appLogger.Fatal(err)
+	}
+	shutdownManager.Register("tracing", shutdownTracing)
+
+	db, err := database.GetSQLite(cfg.Database.Path)
+	if err != nil {
+		appLogger.Fatal(err)
+	}
+	shutdownManager.Register("database", func(ctx context.Context) error {
+		return database.CloseDB()
+	})
+
+	metricsRegistry := metrics.NewRegistry()
+	if err := metricsRegistry.RegisterDB("sqlite", db); err != nil {
+		appLogger.Fatal(err)
+	}
+
+	migrator, err := database.NewMigrator(db, database.Migrations(), database.MigratorConfig{})
+	if err != nil {
+		appLogger.Fatal(err)
+	}
+	if cfg.Database.AutoMigrate {
+		applied, err := migrator.Up()
+		if err != nil {
+			appLogger.Fatal(err)
+		}
+		for _, migration := range applied {
+			appLogger.Info(fmt.Sprintf("Applied migration %d_%s", migration.Version, migration.Name))
+		}
+	}
+
+	healthRegistry := health.NewRegistry(cfg.Health)
+	healthRegistry.Register("database", health.Database(db), health.CheckConfig{Critical: true})
+	healthRegistry.Register("migrations", health.Migrations(migrator), health.CheckConfig{Critical: true})
+	healthRegistry.Register("disk", health.DiskSpace(cfg.Database.Path, uint64(cfg.Health.MinFreeDiskMB)<<20), health.CheckConfig{Critical: true})
+	dependencyClient := httpclient.NewHttpClient().SetDebug(false).SetRetryCount(0)
+	for _, dependency := range cfg.Health.Dependencies {
+		healthRegistry.Register(dependency, health.HTTP(dependencyClient, dependency), health.CheckConfig{})
+	}
+
+	tokenManager, err := token.NewManager(token.Config{
+		Algorithm:       cfg.JWT.Algorithm,
+		Secret:          cfg.JWT.Secret,
+		PrivateKeyPEM:   cfg.JWT.PrivateKey,
+		Issuer:          "golang-template",
+		AccessTokenTTL:  cfg.JWT.AccessTokenTTL,
+		RefreshTokenTTL: cfg.JWT.RefreshTokenTTL,
+	})
+	if err != nil {
+		appLogger.Fatal(err)
+	}
 
-	db, err := database.GetSQLite()
+	appMailer, err := mailer.New(cfg.Mail)
 	if err != nil {
-		logger.Fatal(err)
+		appLogger.Fatal(err)
 	}
 
 	// Create new Fiber app
 	app := fiber.New(fiber.Config{
-		JSONEncoder: json.Marshal,
-		JSONDecoder: json.Unmarshal,
+		JSONEncoder:  json.Marshal,
+		JSONDecoder:  json.Unmarshal,
+		ErrorHandler: middleware.ErrorHandler,
 	})
-	app.Use(cors.New())
+	app.Use(cors.New(cors.Config{AllowOrigins: cfg.AllowOrigins}))
 	app.Use(requestid.New())
+	// Recovers panics anywhere below, including the metrics, tracing and
+	// context logger middleware. It logs with the request logger when one
+	// was already added.
+	recoverConfig := middleware.RecoverConfig{ShowDetails: cfg.Env == "local"}
+	app.Use(middleware.NewRecover(recoverConfig))
 	app.Use(compress.New())
-	app.Use(healthcheck.New())
-	app.Use(middleware.Recover)
-	app.Use(middleware.NewRequestLog(logger))
-	app.Use(middleware.NewResponseLog(logger))
+	app.Use(healthcheck.New(healthcheck.Config{
+		ReadinessProbe: func(c *fiber.Ctx) bool {
+			return shutdownManager.Ready() && healthRegistry.Ready(c.UserContext())
+		},
+	}))
+	app.Get("/health", health.Handler(healthRegistry, cfg.Env == "local"))
+	// Registered before the request logs so scrapes are neither logged nor
+	// counted.
+	app.Get("/metrics", metricsRegistry.Handler())
+	// Swagger UI assets are large and static, so they skip the logs as well.
+	app.Get("/docs*", openapi.UIHandler(handlers.OpenAPI().Info.Title, "/openapi.json", "/docs"))
+	app.Use(middleware.NewMetrics(metricsRegistry))
+	app.Use(middleware.NewTracing())
+	app.Use(middleware.NewContextLogger(appLogger))
+	// Handler panics are recovered again here, so metrics, traces and
+	// response logs record them as internal errors.
+	app.Use(middleware.NewRecover(recoverConfig))
+	app.Use(middleware.NewRequestLog(appLogger, logConfig))
+	app.Use(middleware.NewResponseLog(appLogger, logConfig))
+	if cfg.RateLimit.Enabled {
+		rateLimitStore := ratelimit.NewMemoryStore()
+		if cfg.RateLimit.Store == "sqlite" {
+			rateLimitStore = ratelimit.NewSQLiteStore(db)
+		}
+		app.Use(middleware.NewRateLimit(middleware.RateLimitConfig{
+			Limiter:      ratelimit.NewLimiter(rateLimitStore),
+			Policies:     cfg.RateLimit.Policies,
+			TokenManager: tokenManager,
+		}))
+	}
+	if cfg.Idempotency.Enabled {
+		idempotencyStore := idempotency.NewSQLiteStore(db)
+		shutdownManager.Register("idempotency cleanup", idempotency.StartCleanup(idempotencyStore, cfg.Idempotency.CleanupInterval))
+		app.Use(middleware.NewIdempotency(middleware.IdempotencyConfig{
+			Store:        idempotencyStore,
+			TTL:          cfg.Idempotency.TTL,
+			TokenManager: tokenManager,
+			// Login, refresh and MFA responses carry tokens, secrets and
+			// recovery codes that must never be stored.
+			SkipPaths: []string{"/api/v1/auth/*"},
+		}))
+	}
 
-	api := app.Group("/api")
+	api := app.Group("/api", middleware.NewTimeout(cfg.RequestTimeout))
 
 	// Register routes
+	passwordHasher, err := hasher.New(cfg.Hasher)
+	if err != nil {
+		appLogger.Fatal(err)
+	}
 	userRepository := repositories.NewUserRepository(db)
-	userService := services.NewUserService(userRepository)
-	userHandler := handlers.NewUserHandler(userService)
-	handlers.RegisterUserRoutes(api.Group("/v1/user"), userHandler)
+	emailVerificationRepository := repositories.NewEmailVerificationRepository(db)
+	emailVerificationService := services.NewEmailVerificationService(userRepository, emailVerificationRepository, appMailer, services.EmailVerificationOptions{
+		TokenTTL: cfg.EmailVerification.TokenTTL,
+		LinkURL:  cfg.EmailVerification.LinkURL,
+	})
+	loginAttemptService := services.NewLoginAttemptService(repositories.NewLoginAttemptRepository(db), audit.NewLogRecorder(), services.LockoutOptions{
+		MaxFailures:   cfg.Lockout.MaxFailures,
+		IPMaxFailures: cfg.Lockout.IPMaxFailures,
+		BaseDuration:  cfg.Lockout.BaseDuration,
+		MaxDuration:   cfg.Lockout.MaxDuration,
+		ResetAfter:    cfg.Lockout.ResetAfter,
+	})
+	shutdownManager.Register("login attempt cleanup", jobs.Start("login attempt cleanup", cfg.Lockout.CleanupInterval, func(ctx context.Context) error {
+		_, err := loginAttemptService.Cleanup(ctx)
+		return err
+	}))
+	userService := services.NewUserService(userRepository, passwordHasher, emailVerificationService, loginAttemptService)
+	passwordResetRepository := repositories.NewPasswordResetRepository(db)
+	passwordResetService := services.NewPasswordResetService(userRepository, passwordResetRepository, passwordHasher, appMailer, services.PasswordResetOptions{
+		TokenTTL: cfg.PasswordReset.TokenTTL,
+		LinkURL:  cfg.PasswordReset.LinkURL,
+	})
+	shutdownManager.Register("password reset mail", passwordResetService.Wait)
+	shutdownManager.Register("deleted user purge", jobs.Start("deleted user purge", cfg.Users.PurgeInterval, func(ctx context.Context) error {
+		_, err := userService.PurgeDeleted(ctx, cfg.Users.DeletedRetention)
+		return err
+	}))
+
+	roleRepository := repositories.NewRoleRepository(db)
+	roleService := services.NewRoleService(roleRepository)
+
+	permissionRepository := repositories.NewPermissionRepository(db)
+	permissionService := services.NewPermissionService(permissionRepository)
+
+	userRoleRepository := repositories.NewUserRoleRepository(db)
+	userRoleService := services.NewUserRoleService(userRoleRepository, userRepository, roleRepository, permissionRepository)
+
+	mfaService := services.NewMFAService(userRepository, repositories.NewMFARepository(db), passwordHasher, loginAttemptService, audit.NewLogRecorder(), services.MFAOptions{
+		Issuer:        cfg.MFA.Issuer,
+		ChallengeTTL:  cfg.MFA.ChallengeTTL,
+		RequiredRoles: cfg.MFA.RequiredRoles,
+	})
+	shutdownManager.Register("MFA challenge cleanup", jobs.Start("MFA challenge cleanup", cfg.MFA.CleanupInterval, func(ctx context.Context) error {
+		_, err := mfaService.Cleanup(ctx)
+		return err
+	}))
+
+	refreshTokenRepository := repositories.NewRefreshTokenRepository(db)
+	authService := services.NewAuthService(userService, userRoleService, mfaService, loginAttemptService, refreshTokenRepository, tokenManager)
+
+	handlers.RegisterRoutes(api, handlers.Handlers{
+		User:              handlers.NewUserHandler(userService),
+		Role:              handlers.NewRoleHandler(roleService),
+		Permission:        handlers.NewPermissionHandler(permissionService),
+		UserRole:          handlers.NewUserRoleHandler(userRoleService),
+		Auth:              handlers.NewAuthHandler(authService),
+		EmailVerification: handlers.NewEmailVerificationHandler(emailVerificationService),
+		Password:          handlers.NewPasswordHandler(userService, passwordResetService),
+		MFA:               handlers.NewMFAHandler(mfaService, authService),
+		Log:               handlers.NewLogHandler(appLogger),
+	}, middleware.NewAuth(tokenManager))
+
+	spec, err := openapi.Generate(app.GetRoutes(true), handlers.OpenAPI())
+	if err != nil {
+		appLogger.Fatal(err)
+	}
+	specHandler, err := openapi.Handler(spec)
+	if err != nil {
+		appLogger.Fatal(err)
+	}
+	app.Get("/openapi.json", specHandler)
+
+	shutdownManager.Register("http server", func(ctx context.Context) error {
+		return app.ShutdownWithTimeout(cfg.Shutdown.Timeout)
+	})
 
 	// Start server
-	app.Listen(":9090")
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.Listen(fmt.Sprintf(":%d", cfg.Port))
+	}()
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	exitCode := 0
+	select {
+	case err := <-listenErr:
+		appLogger.Error(err)
+		exitCode = 1
+	case <-signalCtx.Done():
+		appLogger.Info("Shutting down")
+	}
+	stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Shutdown.Delay+cfg.Shutdown.Timeout)
+	defer cancel()
+	if err := shutdownManager.Shutdown(ctx); err != nil {
+		appLogger.Error(err)
+		exitCode = 1
+	}
+
+	os.Exit(exitCode)
 }
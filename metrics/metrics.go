@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every collector exposed on /metrics. Features create their
+// metrics through it rather than the global prometheus registry, so tests can
+// use a fresh one. Registering the same name twice panics.
+type Registry interface {
+	NewCounterVec(name string, help string, labels ...string) *prometheus.CounterVec
+	NewGaugeVec(name string, help string, labels ...string) *prometheus.GaugeVec
+	NewHistogramVec(name string, help string, buckets []float64, labels ...string) *prometheus.HistogramVec
+	// RegisterDB exports the connection pool stats of db labelled with name.
+	RegisterDB(name string, db *sql.DB) error
+	Register(collector prometheus.Collector) error
+	Gatherer() prometheus.Gatherer
+	// Handler serves the metrics in the Prometheus text format.
+	Handler() fiber.Handler
+}
+
+type registry struct {
+	registry *prometheus.Registry
+	factory  promauto.Factory
+}
+
+// NewRegistry returns a registry that already exports the Go runtime and
+// process metrics.
+func NewRegistry() Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return &registry{registry: r, factory: promauto.With(r)}
+}
+
+func (r *registry) NewCounterVec(name string, help string, labels ...string) *prometheus.CounterVec {
+	return r.factory.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+}
+
+func (r *registry) NewGaugeVec(name string, help string, labels ...string) *prometheus.GaugeVec {
+	return r.factory.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labels)
+}
+
+func (r *registry) NewHistogramVec(name string, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	return r.factory.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)
+}
+
+func (r *registry) RegisterDB(name string, db *sql.DB) error {
+	return r.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+func (r *registry) Register(collector prometheus.Collector) error {
+	return r.registry.Register(collector)
+}
+
+func (r *registry) Gatherer() prometheus.Gatherer {
+	return r.registry
+}
+
+func (r *registry) Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{}))
+}
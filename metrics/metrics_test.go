@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Handler(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	registry := NewRegistry()
+	registry.NewCounterVec("jobs_total", "Number of jobs.", "queue").WithLabelValues("mail").Inc()
+	assert.NoError(t, registry.RegisterDB("sqlite", db))
+	assert.Error(t, registry.RegisterDB("sqlite", db))
+
+	app := fiber.New()
+	app.Get("/metrics", registry.Handler())
+
+	response, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/metrics", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+	body, _ := io.ReadAll(response.Body)
+	assert.Contains(t, string(body), `jobs_total{queue="mail"} 1`)
+	assert.Contains(t, string(body), `go_sql_max_open_connections{db_name="sqlite"}`)
+	assert.Contains(t, string(body), "go_goroutines")
+	assert.Contains(t, string(body), "process_cpu_seconds_total")
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"golang-template/token"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuth(t *testing.T) {
+	tokenManager, err := token.NewManager(token.Config{Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accessToken, _ := tokenManager.GenerateAccessToken(token.Identity{UserID: "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01", Username: "testuser"})
+
+	testCaseList := []struct {
+		name               string
+		authorization      string
+		expectedStatusCode int
+	}{
+		{name: "valid token", authorization: "Bearer " + accessToken, expectedStatusCode: fiber.StatusOK},
+		{name: "missing header", authorization: "", expectedStatusCode: fiber.StatusUnauthorized},
+		{name: "wrong scheme", authorization: "Basic " + accessToken, expectedStatusCode: fiber.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer invalid", expectedStatusCode: fiber.StatusUnauthorized},
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewAuth(tokenManager))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(CurrentUser(c).Username)
+	})
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/", nil)
+			if testCase.authorization != "" {
+				request.Header.Set("Authorization", testCase.authorization)
+			}
+			response, err := app.Test(request, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	testCaseList := []struct {
+		name               string
+		claims             *token.Claims
+		expectedStatusCode int
+	}{
+		{name: "has permission", claims: &token.Claims{Permissions: []string{"user:write"}}, expectedStatusCode: fiber.StatusOK},
+		{name: "missing permission", claims: &token.Claims{Permissions: []string{"user:read"}}, expectedStatusCode: fiber.StatusForbidden},
+		{name: "not authenticated", claims: nil, expectedStatusCode: fiber.StatusUnauthorized},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Use(func(c *fiber.Ctx) error {
+				if testCase.claims != nil {
+					SetCurrentUser(c, testCase.claims)
+				}
+				return c.Next()
+			})
+			app.Get("/", RequirePermission("user:write"), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			response, err := app.Test(httptest.NewRequest("GET", "/", nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"golang-template/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewContextLogger stores a logger enriched with the request ID, method, path
+// and trace ID in c.UserContext(), so services can log through
+// logger.FromContext(ctx). It must run after requestid.New() and NewTracing().
+func NewContextLogger(log logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		fields := logger.Fields{
+			"request_id": c.Locals("requestid"),
+			"method":     c.Method(),
+			"path":       c.Path(),
+		}
+		if spanContext := trace.SpanContextFromContext(c.UserContext()); spanContext.IsValid() {
+			fields["trace_id"] = spanContext.TraceID().String()
+			fields["span_id"] = spanContext.SpanID().String()
+		}
+
+		c.SetUserContext(logger.WithContext(c.UserContext(), log.With(fields)))
+		return c.Next()
+	}
+}
+
+// enrichContextLogger adds fields to the request-scoped logger.
+func enrichContextLogger(c *fiber.Ctx, fields logger.Fields) {
+	ctx := c.UserContext()
+	c.SetUserContext(logger.WithContext(ctx, logger.FromContext(ctx).With(fields)))
+}
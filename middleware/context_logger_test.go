@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"golang-template/logger"
+	"golang-template/token"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestContextLogger(t *testing.T) {
+	testCaseList := []struct {
+		name           string
+		traceParent    string
+		expectedFields logger.Fields
+	}{
+		{
+			name:           "without trace",
+			expectedFields: logger.Fields{"request_id": "req-1", "method": "GET", "path": "/"},
+		},
+		{
+			name:           "with trace",
+			traceParent:    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedFields: logger.Fields{"request_id": "req-1", "method": "GET", "path": "/", "trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7"},
+		},
+		{
+			name:           "invalid trace",
+			traceParent:    "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			expectedFields: logger.Fields{"request_id": "req-1", "method": "GET", "path": "/"},
+		},
+	}
+
+	// Without a tracer provider the server span is a no-op that carries the
+	// incoming span context.
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			root := logger.NewLoggerMock()
+			scoped := logger.NewLoggerMock()
+			root.On("With", testCase.expectedFields).Return(scoped)
+			scoped.On("Info", "handled")
+
+			app := fiber.New()
+			app.Use(requestid.New(requestid.Config{Generator: func() string { return "req-1" }}))
+			app.Use(NewTracing())
+			app.Use(NewContextLogger(root))
+			app.Get("/", func(c *fiber.Ctx) error {
+				logger.FromContext(c.UserContext()).Info("handled")
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			request := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if testCase.traceParent != "" {
+				request.Header.Set("traceparent", testCase.traceParent)
+			}
+			response, err := app.Test(request, -1)
+
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, response.StatusCode)
+			root.AssertExpectations(t)
+			scoped.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuth_EnrichesContextLogger(t *testing.T) {
+	tokenManager, err := token.NewManager(token.Config{Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accessToken, _ := tokenManager.GenerateAccessToken(token.Identity{UserID: "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01", Username: "testuser", Permissions: []string{"user:read"}})
+
+	root := logger.NewLoggerMock()
+	authenticated := logger.NewLoggerMock()
+	authorized := logger.NewLoggerMock()
+	root.On("With", logger.Fields{"user_id": "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01"}).Return(authenticated)
+	authenticated.On("With", logger.Fields{"route": "/users/:id"}).Return(authorized)
+	authorized.On("Info", "handled")
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(logger.WithContext(c.UserContext(), root))
+		return c.Next()
+	})
+	users := app.Group("/users")
+	users.Use(NewAuth(tokenManager))
+	users.Get("/:id", RequirePermission("user:read"), func(c *fiber.Ctx) error {
+		logger.FromContext(c.UserContext()).Info("handled")
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	request := httptest.NewRequest(fiber.MethodGet, "/users/1", nil)
+	request.Header.Set(fiber.HeaderAuthorization, "Bearer "+accessToken)
+	response, err := app.Test(request, -1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, response.StatusCode)
+	root.AssertExpectations(t)
+	authenticated.AssertExpectations(t)
+	authorized.AssertExpectations(t)
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"golang-template/app"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// ErrorHandler renders every error returned from a handler. Clients that
+// prefer application/problem+json get an RFC 7807 body, everyone else gets
+// the usual response envelope.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		err = fiberError(fiberErr)
+	}
+
+	appErr := app.AsError(err)
+	c.Status(appErr.Status())
+
+	if c.Accepts(fiber.MIMEApplicationJSON, MIMEApplicationProblemJSON) == MIMEApplicationProblemJSON {
+		return c.JSON(app.NewProblem(appErr, c.OriginalURL()), MIMEApplicationProblemJSON)
+	}
+	return c.JSON(app.NewResponseError(appErr))
+}
+
+// fiberError classifies the errors fiber raises itself, such as unknown
+// routes or unparsable bodies.
+func fiberError(err *fiber.Error) *app.Error {
+	switch err.Code {
+	case fiber.StatusNotFound:
+		return app.NewNotFoundError("route_not_found", err.Message)
+	case fiber.StatusMethodNotAllowed:
+		return &app.Error{Kind: app.KindMethodNotAllowed, Code: "method_not_allowed", Message: err.Message}
+	case fiber.StatusRequestTimeout, fiber.StatusGatewayTimeout:
+		return app.ErrTimeout.Wrap(err)
+	case fiber.StatusTooManyRequests:
+		return app.NewTooManyRequestsError("rate_limited", err.Message)
+	}
+	if err.Code >= fiber.StatusBadRequest && err.Code < fiber.StatusInternalServerError {
+		return app.NewBadRequestError("bad_request", err.Message)
+	}
+	return app.ErrInternal.Wrap(err)
+}
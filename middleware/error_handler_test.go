@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"errors"
+	"golang-template/app"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHandler(t *testing.T) {
+	testCaseList := []struct {
+		name                string
+		path                string
+		accept              string
+		err                 error
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "application error as envelope",
+			path:                "/",
+			err:                 app.NewNotFoundError("user_not_found", "user not found"),
+			expectedStatusCode:  fiber.StatusNotFound,
+			expectedContentType: fiber.MIMEApplicationJSON,
+			expectedBody:        `{"status":"error","code":"user_not_found","message":"user not found"}`,
+		},
+		{
+			name:                "application error as problem",
+			path:                "/",
+			accept:              MIMEApplicationProblemJSON,
+			err:                 app.NewConflictError("user_already_exists", "username or email already exists"),
+			expectedStatusCode:  fiber.StatusConflict,
+			expectedContentType: MIMEApplicationProblemJSON,
+			expectedBody:        `{"type":"about:blank","title":"Conflict","status":409,"detail":"username or email already exists","instance":"/","code":"user_already_exists"}`,
+		},
+		{
+			name:                "prefers envelope when both are accepted",
+			path:                "/",
+			accept:              "application/json, application/problem+json;q=0.5",
+			err:                 app.NewBadRequestError("invalid_id", "invalid id"),
+			expectedStatusCode:  fiber.StatusBadRequest,
+			expectedContentType: fiber.MIMEApplicationJSON,
+			expectedBody:        `{"status":"error","code":"invalid_id","message":"invalid id"}`,
+		},
+		{
+			name:                "unknown error hides the cause",
+			path:                "/",
+			err:                 errors.New("UNIQUE constraint failed: users.username"),
+			expectedStatusCode:  fiber.StatusInternalServerError,
+			expectedContentType: fiber.MIMEApplicationJSON,
+			expectedBody:        `{"status":"error","code":"internal_error","message":"internal server error"}`,
+		},
+		{
+			name:                "unknown route",
+			path:                "/missing",
+			accept:              MIMEApplicationProblemJSON,
+			expectedStatusCode:  fiber.StatusNotFound,
+			expectedContentType: MIMEApplicationProblemJSON,
+			expectedBody:        `{"type":"about:blank","title":"Not Found","status":404,"detail":"Cannot GET /missing","instance":"/missing","code":"route_not_found"}`,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			fiberApp := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			fiberApp.Get("/", func(c *fiber.Ctx) error {
+				return testCase.err
+			})
+
+			request := httptest.NewRequest(fiber.MethodGet, testCase.path, nil)
+			if testCase.accept != "" {
+				request.Header.Set(fiber.HeaderAccept, testCase.accept)
+			}
+			response, err := fiberApp.Test(request, -1)
+			assert.NoError(t, err)
+
+			body, err := io.ReadAll(response.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, testCase.expectedContentType, response.Header.Get(fiber.HeaderContentType))
+			assert.JSONEq(t, testCase.expectedBody, string(body))
+		})
+	}
+}
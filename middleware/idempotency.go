@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"golang-template/app"
+	"golang-template/idempotency"
+	"golang-template/logger"
+	"golang-template/token"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+var (
+	errInvalidIdempotencyKey    = app.NewBadRequestError("invalid_idempotency_key", "idempotency key must be at most 255 characters")
+	errIdempotencyKeyReused     = app.NewConflictError("idempotency_key_reused", "idempotency key was already used for a different request")
+	errIdempotencyKeyInProgress = app.NewConflictError("idempotency_key_in_progress", "a request with this idempotency key is still in progress")
+)
+
+type IdempotencyConfig struct {
+	Store idempotency.Store
+	TTL   time.Duration
+	// TokenManager scopes keys to the user, as the middleware runs before
+	// the auth middleware. Keys of anonymous requests share one scope.
+	TokenManager token.Manager
+	// SkipPaths are path.Match patterns, where a trailing "/*" also matches
+	// deeper paths, of routes that never use idempotency keys. Routes whose
+	// responses carry tokens or secrets belong here, as responses are stored
+	// in plain text and a replay would bypass checks like refresh token
+	// reuse detection.
+	SkipPaths []string
+}
+
+// NewIdempotency makes POST, PUT and PATCH requests with an Idempotency-Key
+// header safe to retry. The first successful response is stored and
+// replayed for later requests with the same key and body, while reusing the
+// key for a different request fails with 409. Failed requests release the
+// key so they can be retried.
+func NewIdempotency(config IdempotencyConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if key == "" || !isMutatingMethod(c.Method()) || skipIdempotency(c, config.SkipPaths) {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return errInvalidIdempotencyKey
+		}
+
+		scope := "anonymous"
+		if claims := peekCurrentUser(c, config.TokenManager); claims != nil {
+			scope = "user:" + claims.Subject
+		}
+		key = scope + ":" + key
+		fingerprint := requestFingerprint(c)
+
+		ctx := c.UserContext()
+		record, reserved, err := config.Store.Reserve(ctx, key, fingerprint, config.TTL)
+		if err != nil {
+			return err
+		}
+		if !reserved {
+			switch {
+			case record.Fingerprint != fingerprint:
+				return errIdempotencyKeyReused
+			case record.Response == nil:
+				return errIdempotencyKeyInProgress
+			}
+			c.Set(HeaderIdempotentReplayed, "true")
+			c.Set(fiber.HeaderContentType, record.Response.ContentType)
+			return c.Status(record.Response.StatusCode).Send(record.Response.Body)
+		}
+
+		completed := false
+		// Deferred so the key is also released when the handler panics.
+		defer func() {
+			if completed {
+				return
+			}
+			if err := config.Store.Release(context.WithoutCancel(ctx), key); err != nil {
+				logger.FromContext(ctx).Warn("Idempotency key release failed: ", err)
+			}
+		}()
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if c.Response().StatusCode() >= fiber.StatusInternalServerError {
+			return nil
+		}
+
+		response := idempotency.Response{
+			StatusCode:  c.Response().StatusCode(),
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+		if err := config.Store.Complete(context.WithoutCancel(ctx), key, response); err != nil {
+			logger.FromContext(ctx).Warn("Idempotency key completion failed: ", err)
+			return nil
+		}
+		completed = true
+		return nil
+	}
+}
+
+func skipIdempotency(c *fiber.Ctx, skipPaths []string) bool {
+	for _, pattern := range skipPaths {
+		if matchPath(pattern, c.Path()) {
+			return true
+		}
+	}
+	return false
+}
+
+func isMutatingMethod(method string) bool {
+	return method == fiber.MethodPost || method == fiber.MethodPut || method == fiber.MethodPatch
+}
+
+// requestFingerprint hashes everything that makes two requests the same.
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method() + " " + c.OriginalURL() + "\n"))
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
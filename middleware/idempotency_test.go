@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang-template/app"
+	"golang-template/idempotency"
+	"golang-template/token"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotency(t *testing.T) {
+	tokenManager, err := token.NewManager(token.Config{Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accessToken, _ := tokenManager.GenerateAccessToken(token.Identity{UserID: "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01", Username: "testuser"})
+
+	body := `{"username":"testuser"}`
+	stored := &idempotency.Response{StatusCode: fiber.StatusCreated, ContentType: fiber.MIMEApplicationJSON, Body: []byte(`{"id":1}`)}
+
+	testCaseList := []struct {
+		name               string
+		method             string
+		path               string
+		key                string
+		authorization      string
+		handlerErr         error
+		setupMock          func(storeMock *idempotency.StoreMock, fingerprint string)
+		expectedStatusCode int
+		expectedBody       string
+		expectedReplayed   bool
+		expectedCalls      int
+	}{
+		{
+			name:               "no key",
+			method:             "POST",
+			setupMock:          func(storeMock *idempotency.StoreMock, fingerprint string) {},
+			expectedStatusCode: fiber.StatusCreated,
+			expectedBody:       `{"id":2}`,
+			expectedCalls:      1,
+		},
+		{
+			name:               "safe method",
+			method:             "GET",
+			key:                "key-1",
+			setupMock:          func(storeMock *idempotency.StoreMock, fingerprint string) {},
+			expectedStatusCode: fiber.StatusCreated,
+			expectedBody:       `{"id":2}`,
+			expectedCalls:      1,
+		},
+		{
+			name:               "skipped path",
+			method:             "POST",
+			path:               "/auth/mfa/verify",
+			key:                "key-1",
+			setupMock:          func(storeMock *idempotency.StoreMock, fingerprint string) {},
+			expectedStatusCode: fiber.StatusCreated,
+			expectedBody:       `{"id":2}`,
+			expectedCalls:      1,
+		},
+		{
+			name:               "key too long",
+			method:             "POST",
+			key:                strings.Repeat("k", 256),
+			setupMock:          func(storeMock *idempotency.StoreMock, fingerprint string) {},
+			expectedStatusCode: fiber.StatusBadRequest,
+		},
+		{
+			name:   "first request is stored",
+			method: "POST",
+			key:    "key-1",
+			setupMock: func(storeMock *idempotency.StoreMock, fingerprint string) {
+				storeMock.On("Reserve", mock.Anything, "anonymous:key-1", fingerprint, time.Hour).Return(idempotency.Record{Fingerprint: fingerprint}, true, nil)
+				storeMock.On("Complete", mock.Anything, "anonymous:key-1", idempotency.Response{
+					StatusCode:  fiber.StatusCreated,
+					ContentType: fiber.MIMEApplicationJSON,
+					Body:        []byte(`{"id":2}`),
+				}).Return(nil)
+			},
+			expectedStatusCode: fiber.StatusCreated,
+			expectedBody:       `{"id":2}`,
+			expectedCalls:      1,
+		},
+		{
+			name:          "keys are scoped to the user",
+			method:        "PUT",
+			key:           "key-1",
+			authorization: "Bearer " + accessToken,
+			setupMock: func(storeMock *idempotency.StoreMock, fingerprint string) {
+				storeMock.On("Reserve", mock.Anything, "user:0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01:key-1", fingerprint, time.Hour).Return(idempotency.Record{Fingerprint: fingerprint}, true, nil)
+				storeMock.On("Complete", mock.Anything, "user:0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01:key-1", mock.Anything).Return(nil)
+			},
+			expectedStatusCode: fiber.StatusCreated,
+			expectedBody:       `{"id":2}`,
+			expectedCalls:      1,
+		},
+		{
+			name:   "duplicate is replayed",
+			method: "POST",
+			key:    "key-1",
+			setupMock: func(storeMock *idempotency.StoreMock, fingerprint string) {
+				storeMock.On("Reserve", mock.Anything, "anonymous:key-1", fingerprint, time.Hour).Return(idempotency.Record{Fingerprint: fingerprint, Response: stored}, false, nil)
+			},
+			expectedStatusCode: fiber.StatusCreated,
+			expectedBody:       `{"id":1}`,
+			expectedReplayed:   true,
+		},
+		{
+			name:   "key reused for a different request",
+			method: "POST",
+			key:    "key-1",
+			setupMock: func(storeMock *idempotency.StoreMock, fingerprint string) {
+				storeMock.On("Reserve", mock.Anything, "anonymous:key-1", fingerprint, time.Hour).Return(idempotency.Record{Fingerprint: "other", Response: stored}, false, nil)
+			},
+			expectedStatusCode: fiber.StatusConflict,
+		},
+		{
+			name:   "duplicate while in progress",
+			method: "PATCH",
+			key:    "key-1",
+			setupMock: func(storeMock *idempotency.StoreMock, fingerprint string) {
+				storeMock.On("Reserve", mock.Anything, "anonymous:key-1", fingerprint, time.Hour).Return(idempotency.Record{Fingerprint: fingerprint}, false, nil)
+			},
+			expectedStatusCode: fiber.StatusConflict,
+		},
+		{
+			name:       "failed request releases the key",
+			method:     "POST",
+			key:        "key-1",
+			handlerErr: app.NewConflictError("user_already_exists", "username or email already exists"),
+			setupMock: func(storeMock *idempotency.StoreMock, fingerprint string) {
+				storeMock.On("Reserve", mock.Anything, "anonymous:key-1", fingerprint, time.Hour).Return(idempotency.Record{Fingerprint: fingerprint}, true, nil)
+				storeMock.On("Release", mock.Anything, "anonymous:key-1").Return(nil)
+			},
+			expectedStatusCode: fiber.StatusConflict,
+			expectedCalls:      1,
+		},
+		{
+			name:   "store failure",
+			method: "POST",
+			key:    "key-1",
+			setupMock: func(storeMock *idempotency.StoreMock, fingerprint string) {
+				storeMock.On("Reserve", mock.Anything, "anonymous:key-1", fingerprint, time.Hour).Return(idempotency.Record{}, false, errors.New("database is locked"))
+			},
+			expectedStatusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			storeMock := idempotency.NewStoreMock()
+			calls := 0
+
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Use(NewIdempotency(IdempotencyConfig{Store: storeMock, TTL: time.Hour, TokenManager: tokenManager, SkipPaths: []string{"/auth/*"}}))
+			app.All("/*", func(c *fiber.Ctx) error {
+				calls++
+				if testCase.handlerErr != nil {
+					return testCase.handlerErr
+				}
+				return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": 2})
+			})
+
+			requestPath := testCase.path
+			if requestPath == "" {
+				requestPath = "/users"
+			}
+			sum := sha256.Sum256([]byte(testCase.method + " " + requestPath + "\n" + body))
+			fingerprint := hex.EncodeToString(sum[:])
+			testCase.setupMock(storeMock, fingerprint)
+
+			request := httptest.NewRequest(testCase.method, requestPath, strings.NewReader(body))
+			request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			if testCase.key != "" {
+				request.Header.Set(HeaderIdempotencyKey, testCase.key)
+			}
+			if testCase.authorization != "" {
+				request.Header.Set(fiber.HeaderAuthorization, testCase.authorization)
+			}
+			response, err := app.Test(request, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
+			if testCase.expectedBody != "" {
+				responseBody, _ := io.ReadAll(response.Body)
+				assert.Equal(t, testCase.expectedBody, string(responseBody))
+			}
+			assert.Equal(t, testCase.expectedReplayed, response.Header.Get(HeaderIdempotentReplayed) == "true")
+			assert.Equal(t, testCase.expectedCalls, calls)
+			storeMock.AssertExpectations(t)
+		})
+	}
+}
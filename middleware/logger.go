@@ -1,23 +1,50 @@
 package middleware
 
 import (
-	"encoding/json"
+	"golang-template/app"
 	"golang-template/logger"
 
 	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func getJsonBody(body string) map[string]interface{} {
-	bodyMap := map[string]interface{}{}
-	_ = json.Unmarshal([]byte(body), &bodyMap)
-	return bodyMap
+type LogConfig struct {
+	// Redactor removes secrets from logged bodies, headers and errors. The
+	// logger.DefaultRedactConfig rules are used when it is nil.
+	Redactor *logger.Redactor
+	// SkipBodyPaths are path.Match patterns, e.g. "/api/v1/auth/*", where a
+	// trailing "/*" also matches deeper paths, of routes whose bodies are never
+	// logged.
+	SkipBodyPaths []string
 }
 
-func NewRequestLog(logger logger.Logger) fiber.Handler {
+func (config LogConfig) redactor() *logger.Redactor {
+	if config.Redactor != nil {
+		return config.Redactor
+	}
+	redactor, _ := logger.NewRedactor(logger.DefaultRedactConfig())
+	return redactor
+}
+
+func (config LogConfig) logBody(c *fiber.Ctx) bool {
+	for _, pattern := range config.SkipBodyPaths {
+		if matchPath(pattern, c.Path()) {
+			return false
+		}
+	}
+	return true
+}
+
+func NewRequestLog(logger logger.Logger, config LogConfig) fiber.Handler {
+	redactor := config.redactor()
+
 	return func(c *fiber.Ctx) error {
-		body := getJsonBody(string(c.Body()))
+		var body any
+		if config.logBody(c) {
+			body = redactor.Body(c.Get(fiber.HeaderContentType), c.Body())
+		}
 
-		logger.Request(map[string]interface{}{
+		fields := map[string]interface{}{
 			"request_id":   c.Locals("requestid"),
 			"method":       c.Method(),
 			"path":         c.Path(),
@@ -27,33 +54,67 @@ func NewRequestLog(logger logger.Logger) fiber.Handler {
 			"referer":      c.Get("Referer"),
 			"host":         c.Hostname(),
 			"protocol":     c.Protocol(),
-			"headers":      c.Get("User-Agent"),
-			"query":        c.Query("query"),
+			"headers":      redactor.Headers(c.GetReqHeaders()),
+			"query":        redactor.String(string(c.Request().URI().QueryString())),
 			"params":       c.Params("params"),
-			"cookies":      c.Cookies("cookies"),
 			"session":      c.Locals("session"),
 			"session_id":   c.Locals("session_id"),
 			"session_data": c.Locals("session_data"),
-		})
+		}
+		addTraceID(c, fields)
+		logger.Request(fields)
 		return c.Next()
 	}
 }
 
-func NewResponseLog(logger logger.Logger) fiber.Handler {
+func NewResponseLog(logger logger.Logger, config LogConfig) fiber.Handler {
+	redactor := config.redactor()
+
 	return func(c *fiber.Ctx) error {
+		// Render the error here rather than in the app's error handler so the
+		// logged status and body match what the client receives.
 		err := c.Next()
-		reponseData := getJsonBody(string(c.Response().Body()))
+		if err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				return handlerErr
+			}
+		}
+
+		var reponseData any
+		if config.logBody(c) {
+			reponseData = redactor.Body(string(c.Response().Header.ContentType()), c.Response().Body())
+		}
 
-		logger.Response(map[string]interface{}{
+		fields := map[string]interface{}{
 			"request_id": c.Locals("requestid"),
 			"status":     c.Response().StatusCode(),
 			"method":     c.Method(),
 			"path":       c.Path(),
+			"route":      c.Route().Path,
 			"ip":         c.IP(),
 			"host":       c.Hostname(),
+			"headers":    redactor.Headers(c.GetRespHeaders()),
 			"response":   reponseData,
-		})
+		}
+		addTraceID(c, fields)
+		if claims := CurrentUser(c); claims != nil {
+			fields["user_id"] = claims.UserID()
+		}
+		if err != nil {
+			appErr := app.AsError(err)
+			fields["error_code"] = appErr.Code
+			if appErr.Err != nil {
+				fields["error"] = redactor.String(appErr.Err.Error())
+			}
+		}
+		logger.Response(fields)
+
+		return nil
+	}
+}
 
-		return err
+func addTraceID(c *fiber.Ctx, fields map[string]interface{}) {
+	if spanContext := trace.SpanContextFromContext(c.UserContext()); spanContext.IsValid() {
+		fields["trace_id"] = spanContext.TraceID().String()
 	}
 }
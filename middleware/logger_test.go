@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"golang-template/logger"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestLog(t *testing.T) {
+	testCaseList := []struct {
+		name         string
+		path         string
+		contentType  string
+		body         string
+		expectedBody any
+	}{
+		{
+			name:         "json body",
+			path:         "/api/v1/user/register",
+			contentType:  fiber.MIMEApplicationJSON,
+			body:         `{"username":"john","password":"Secret123"}`,
+			expectedBody: map[string]any{"username": "john", "password": logger.Redacted},
+		},
+		{
+			name:         "form body",
+			path:         "/api/v1/user/register",
+			contentType:  fiber.MIMEApplicationForm,
+			body:         "username=john&password=Secret123",
+			expectedBody: map[string]any{"username": "john", "password": logger.Redacted},
+		},
+		{
+			name:         "multipart body",
+			path:         "/api/v1/user/register",
+			contentType:  fiber.MIMEMultipartForm + "; boundary=x",
+			body:         "--x\r\nContent-Disposition: form-data; name=\"password\"\r\n\r\nSecret123\r\n--x--\r\n",
+			expectedBody: "[OMITTED 74 bytes]",
+		},
+		{
+			name:         "skipped path",
+			path:         "/api/v1/auth/login",
+			contentType:  fiber.MIMEApplicationJSON,
+			body:         `{"username":"john","password":"Secret123"}`,
+			expectedBody: nil,
+		},
+		{
+			name:         "skipped deeper path",
+			path:         "/api/v1/auth/mfa/verify",
+			contentType:  fiber.MIMEApplicationJSON,
+			body:         `{"token":"abc","code":"123456"}`,
+			expectedBody: nil,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			var logged map[string]interface{}
+			loggerMock := logger.NewLoggerMock()
+			loggerMock.On("Request", mock.Anything).Run(func(args mock.Arguments) {
+				logged = args.Get(0).(map[string]interface{})
+			})
+
+			app := fiber.New()
+			app.Use(NewRequestLog(loggerMock, LogConfig{SkipBodyPaths: []string{"/api/v1/auth/*"}}))
+			app.Post("/*", func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			request := httptest.NewRequest(fiber.MethodPost, testCase.path, strings.NewReader(testCase.body))
+			request.Header.Set(fiber.HeaderContentType, testCase.contentType)
+			_, err := app.Test(request, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, testCase.expectedBody, logged["body"])
+			assert.NotContains(t, logged["headers"], "Secret123")
+		})
+	}
+}
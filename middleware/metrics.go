@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"errors"
+	"golang-template/app"
+	"golang-template/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NewMetrics records the request rate, errors and duration of every request
+// by method, route template and status. The route is only known once the
+// router has matched it, so in-flight requests are counted by method alone.
+func NewMetrics(registry metrics.Registry) fiber.Handler {
+	requests := registry.NewCounterVec("http_requests_total", "Number of HTTP requests handled.", "method", "route", "status")
+	duration := registry.NewHistogramVec("http_request_duration_seconds", "Duration of HTTP requests in seconds.", prometheus.DefBuckets, "method", "route", "status")
+	inFlight := registry.NewGaugeVec("http_requests_in_flight", "Number of HTTP requests being handled.", "method")
+
+	routeLabel := newRouteLabel()
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		method := utils.CopyString(c.Method())
+		inFlight.WithLabelValues(method).Inc()
+		defer inFlight.WithLabelValues(method).Dec()
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = errorStatus(err)
+		}
+		labels := prometheus.Labels{"method": method, "route": routeLabel(c, status), "status": strconv.Itoa(status)}
+		requests.With(labels).Inc()
+		duration.With(labels).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// errorStatus is the status ErrorHandler will respond with for err.
+func errorStatus(err error) int {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberError(fiberErr).Status()
+	}
+	return app.AsError(err).Status()
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"golang-template/app"
+	"golang-template/metrics"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		method        string
+		path          string
+		expectedLabel string
+	}{
+		{
+			name:          "route template",
+			method:        fiber.MethodGet,
+			path:          "/users/42",
+			expectedLabel: `method="GET",route="/users/:id",status="200"`,
+		},
+		{
+			name:          "handler error",
+			method:        fiber.MethodPost,
+			path:          "/users",
+			expectedLabel: `method="POST",route="/users",status="409"`,
+		},
+		{
+			name:          "rejected by group middleware",
+			method:        fiber.MethodGet,
+			path:          "/admin/settings",
+			expectedLabel: `method="GET",route="/admin",status="401"`,
+		},
+		{
+			name:          "unmatched route",
+			method:        fiber.MethodGet,
+			path:          "/missing",
+			expectedLabel: `method="GET",route="unmatched",status="404"`,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			registry := metrics.NewRegistry()
+			fiberApp := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			fiberApp.Use(NewMetrics(registry))
+			fiberApp.Get("/users/:id", func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+			fiberApp.Post("/users", func(c *fiber.Ctx) error {
+				return app.NewConflictError("user_already_exists", "exists")
+			})
+			admin := fiberApp.Group("/admin", func(c *fiber.Ctx) error {
+				return errMissingBearerToken
+			})
+			admin.Get("/settings", func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			_, err := fiberApp.Test(httptest.NewRequest(testCase.method, testCase.path, nil), -1)
+			assert.NoError(t, err)
+
+			expected := `
+# HELP http_requests_total Number of HTTP requests handled.
+# TYPE http_requests_total counter
+http_requests_total{` + testCase.expectedLabel + `} 1
+`
+			assert.NoError(t, testutil.GatherAndCompare(registry.Gatherer(), strings.NewReader(expected), "http_requests_total"))
+			count, err := testutil.GatherAndCount(registry.Gatherer(), "http_request_duration_seconds")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, count)
+			assert.NoError(t, testutil.GatherAndCompare(registry.Gatherer(), strings.NewReader(`
+# HELP http_requests_in_flight Number of HTTP requests being handled.
+# TYPE http_requests_in_flight gauge
+http_requests_in_flight{method="`+testCase.method+`"} 0
+`), "http_requests_in_flight"))
+		})
+	}
+}
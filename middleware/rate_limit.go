@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"golang-template/app"
+	"golang-template/logger"
+	"golang-template/ratelimit"
+	"golang-template/token"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderAPIKey             = "X-API-Key"
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+)
+
+var errRateLimited = app.NewTooManyRequestsError("rate_limited", "too many requests")
+
+type RateLimitConfig struct {
+	Limiter  ratelimit.Limiter
+	Policies []ratelimit.Policy
+	// TokenManager identifies users for ratelimit.KeyUser policies, which
+	// run before the auth middleware. Without it users are limited by IP.
+	TokenManager token.Manager
+}
+
+// NewRateLimit applies the first policy matching the request and rejects it
+// with 429 and Retry-After once the client is over the limit. Every limited
+// response carries the RateLimit-* headers. When the store fails the request
+// is let through, so a broken store cannot take the API down.
+func NewRateLimit(config RateLimitConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		policy, ok := matchPolicy(c, config.Policies)
+		if !ok {
+			return c.Next()
+		}
+
+		key := rateLimitKey(c, policy.Key, config.TokenManager)
+		result, err := config.Limiter.Allow(c.UserContext(), policy, key)
+		if err != nil {
+			logger.FromContext(c.UserContext()).With(logger.Fields{
+				"policy": policy.Name,
+				"error":  err.Error(),
+			}).Warn("Rate limit check failed")
+			return c.Next()
+		}
+
+		c.Set(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+		c.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+		c.Set(HeaderRateLimitReset, seconds(result.Reset))
+		c.Set(HeaderRateLimitPolicy, fmt.Sprintf("%d;w=%s", policy.Limit, seconds(policy.Window)))
+		if !result.Allowed {
+			c.Set(fiber.HeaderRetryAfter, seconds(result.RetryAfter))
+			return errRateLimited
+		}
+		return c.Next()
+	}
+}
+
+// matchPolicy returns the first policy with a route matching the request.
+// Routes are "[METHOD ]pattern" path.Match patterns, where a trailing "/*"
+// also matches deeper paths.
+func matchPolicy(c *fiber.Ctx, policies []ratelimit.Policy) (ratelimit.Policy, bool) {
+	for _, policy := range policies {
+		if len(policy.Routes) == 0 {
+			return policy, true
+		}
+		for _, route := range policy.Routes {
+			method, pattern, found := strings.Cut(route, " ")
+			if !found {
+				method, pattern = "", route
+			}
+			if method != "" && method != c.Method() {
+				continue
+			}
+			if matchPath(pattern, c.Path()) {
+				return policy, true
+			}
+		}
+	}
+	return ratelimit.Policy{}, false
+}
+
+// matchPath reports whether p matches the path.Match pattern, where a
+// trailing "/*" also matches deeper paths.
+func matchPath(pattern string, p string) bool {
+	if matched, _ := path.Match(pattern, p); matched {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(pattern, "*")
+	return ok && strings.HasSuffix(prefix, "/") && strings.HasPrefix(p, prefix)
+}
+
+func rateLimitKey(c *fiber.Ctx, kind ratelimit.KeyKind, tokenManager token.Manager) string {
+	switch kind {
+	case ratelimit.KeyUser:
+		if claims := peekCurrentUser(c, tokenManager); claims != nil {
+			return "user:" + claims.Subject
+		}
+	case ratelimit.KeyAPIKey:
+		if apiKey := c.Get(HeaderAPIKey); apiKey != "" {
+			// Hashed so the key itself is never stored.
+			sum := sha256.Sum256([]byte(apiKey))
+			return "api_key:" + hex.EncodeToString(sum[:])
+		}
+	}
+	return "ip:" + c.IP()
+}
+
+// seconds rounds d up to whole seconds, as the RateLimit headers expect.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
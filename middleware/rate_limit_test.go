@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang-template/logger"
+	"golang-template/ratelimit"
+	"golang-template/token"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRateLimit_Policies(t *testing.T) {
+	tokenManager, err := token.NewManager(token.Config{Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accessToken, _ := tokenManager.GenerateAccessToken(token.Identity{UserID: "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01", Username: "testuser"})
+
+	policies := []ratelimit.Policy{
+		{Name: "login", Routes: []string{"POST /api/v1/auth/login"}, Key: ratelimit.KeyIP},
+		{Name: "partner", Routes: []string{"/api/v1/partner/*"}, Key: ratelimit.KeyAPIKey},
+		{Name: "api", Routes: []string{"/api/*"}, Key: ratelimit.KeyUser},
+	}
+
+	testCaseList := []struct {
+		name           string
+		method         string
+		path           string
+		headers        map[string]string
+		expectedPolicy string
+		expectedKey    string
+	}{
+		{name: "method and path", method: "POST", path: "/api/v1/auth/login", expectedPolicy: "login", expectedKey: "ip:0.0.0.0"},
+		{name: "other method falls through", method: "GET", path: "/api/v1/auth/login", expectedPolicy: "api", expectedKey: "ip:0.0.0.0"},
+		{
+			name:           "user from bearer token",
+			method:         "GET",
+			path:           "/api/v1/user/7",
+			headers:        map[string]string{"Authorization": "Bearer " + accessToken},
+			expectedPolicy: "api",
+			expectedKey:    "user:0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01",
+		},
+		{
+			name:           "invalid token limited by IP",
+			method:         "GET",
+			path:           "/api/v1/user/7",
+			headers:        map[string]string{"Authorization": "Bearer invalid"},
+			expectedPolicy: "api",
+			expectedKey:    "ip:0.0.0.0",
+		},
+		{
+			name:           "hashed API key",
+			method:         "GET",
+			path:           "/api/v1/partner/orders",
+			headers:        map[string]string{HeaderAPIKey: "key-1"},
+			expectedPolicy: "partner",
+			expectedKey:    "api_key:be2974546978e3739e6d6da85c4be9f334ce32df2b9fd4b6ff1b55c0d57e9d44",
+		},
+		{name: "no matching policy", method: "GET", path: "/health"},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			limiterMock := ratelimit.NewLimiterMock()
+			if testCase.expectedPolicy != "" {
+				limiterMock.On("Allow", mock.Anything, mock.MatchedBy(func(policy ratelimit.Policy) bool {
+					return policy.Name == testCase.expectedPolicy
+				}), testCase.expectedKey).Return(ratelimit.Result{Allowed: true, Limit: 10, Remaining: 9}, nil)
+			}
+
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Use(NewRateLimit(RateLimitConfig{Limiter: limiterMock, Policies: policies, TokenManager: tokenManager}))
+			app.All("/*", func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			request := httptest.NewRequest(testCase.method, testCase.path, nil)
+			for key, value := range testCase.headers {
+				request.Header.Set(key, value)
+			}
+			response, err := app.Test(request, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, fiber.StatusOK, response.StatusCode)
+			if testCase.expectedPolicy != "" {
+				assert.Equal(t, "9", response.Header.Get(HeaderRateLimitRemaining))
+			} else {
+				assert.Empty(t, response.Header.Get(HeaderRateLimitLimit))
+			}
+			limiterMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRateLimit_Result(t *testing.T) {
+	policy := ratelimit.Policy{Name: "login", Limit: 10, Window: time.Minute, Key: ratelimit.KeyIP}
+
+	testCaseList := []struct {
+		name               string
+		result             ratelimit.Result
+		err                error
+		expectedStatusCode int
+		expectedHeaders    map[string]string
+	}{
+		{
+			name:               "allowed",
+			result:             ratelimit.Result{Allowed: true, Limit: 10, Remaining: 4, Reset: 30 * time.Second},
+			expectedStatusCode: fiber.StatusOK,
+			expectedHeaders: map[string]string{
+				HeaderRateLimitLimit:     "10",
+				HeaderRateLimitRemaining: "4",
+				HeaderRateLimitReset:     "30",
+				HeaderRateLimitPolicy:    "10;w=60",
+				fiber.HeaderRetryAfter:   "",
+			},
+		},
+		{
+			name:               "over the limit",
+			result:             ratelimit.Result{Limit: 10, Reset: time.Minute, RetryAfter: 5500 * time.Millisecond},
+			expectedStatusCode: fiber.StatusTooManyRequests,
+			expectedHeaders: map[string]string{
+				HeaderRateLimitRemaining: "0",
+				HeaderRateLimitReset:     "60",
+				fiber.HeaderRetryAfter:   "6",
+			},
+		},
+		{
+			name:               "store failure lets the request through",
+			err:                errors.New("database is locked"),
+			expectedStatusCode: fiber.StatusOK,
+			expectedHeaders:    map[string]string{HeaderRateLimitLimit: ""},
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			limiterMock := ratelimit.NewLimiterMock()
+			limiterMock.On("Allow", mock.Anything, policy, "ip:0.0.0.0").Return(testCase.result, testCase.err)
+
+			loggerMock := logger.NewLoggerMock()
+			if testCase.err != nil {
+				loggerMock.On("With", logger.Fields{"policy": "login", "error": testCase.err.Error()}).Return(loggerMock)
+				loggerMock.On("Warn", "Rate limit check failed")
+			}
+
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Use(func(c *fiber.Ctx) error {
+				c.SetUserContext(logger.WithContext(c.UserContext(), loggerMock))
+				return c.Next()
+			})
+			app.Use(NewRateLimit(RateLimitConfig{Limiter: limiterMock, Policies: []ratelimit.Policy{policy}}))
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			response, err := app.Test(httptest.NewRequest("GET", "/", nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
+			for key, value := range testCase.expectedHeaders {
+				assert.Equal(t, value, response.Header.Get(key), key)
+			}
+			limiterMock.AssertExpectations(t)
+			loggerMock.AssertExpectations(t)
+		})
+	}
+}
@@ -1,21 +1,93 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"golang-template/app"
+	"golang-template/logger"
+	"runtime/debug"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/google/uuid"
 )
 
-func Recover(c *fiber.Ctx) error {
-	defer func() {
-		if err := recover(); err != nil {
-			// Handle the error and respond with an error message
-			c.Status(fiber.StatusInternalServerError).JSON(app.Response{
-				Status:  "error",
-				Message: fmt.Sprintf("%v", err),
-			})
-		}
-	}()
-	return c.Next()
+// CrashReport describes a recovered panic. ErrorID is also returned to the
+// client so a support request can be matched with the logs.
+type CrashReport struct {
+	ErrorID   string
+	RequestID string
+	Method    string
+	Path      string
+	Panic     any
+	Stack     []byte
+}
+
+// CrashReporter is notified of every recovered panic, e.g. to forward it to
+// an error tracking service. Report runs on the request goroutine, so slow
+// reporters should hand the report off.
+type CrashReporter interface {
+	Report(ctx context.Context, report CrashReport)
+}
+
+type CrashReporterFunc func(ctx context.Context, report CrashReport)
+
+func (f CrashReporterFunc) Report(ctx context.Context, report CrashReport) {
+	f(ctx, report)
+}
+
+type RecoverConfig struct {
+	// ShowDetails returns the panic value as the error message. Only enable
+	// it for local development, it may leak internals.
+	ShowDetails bool
+	Reporters   []CrashReporter
+}
+
+// NewRecover turns a panic into an internal error carrying an errorId, logs
+// it with its stack trace and passes it to the configured reporters. The
+// request context is read when the panic is recovered, so it can be
+// registered before the middleware that adds the request logger.
+func NewRecover(config RecoverConfig) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			report := CrashReport{
+				ErrorID:   uuid.NewString(),
+				RequestID: fmt.Sprint(c.Locals("requestid")),
+				Method:    utils.CopyString(c.Method()),
+				Path:      utils.CopyString(c.Path()),
+				Panic:     recovered,
+				Stack:     debug.Stack(),
+			}
+
+			ctx := c.UserContext()
+			logger.FromContext(ctx).With(logger.Fields{
+				"request_id": report.RequestID,
+				"error_id":   report.ErrorID,
+				"panic":      fmt.Sprint(recovered),
+				"stack":      string(report.Stack),
+			}).Error("Recovered from panic")
+			for _, reporter := range config.Reporters {
+				reporter.Report(ctx, report)
+			}
+
+			appErr := app.ErrInternal.Wrap(panicError(recovered)).WithDetails(fiber.Map{"errorId": report.ErrorID})
+			if config.ShowDetails {
+				appErr = appErr.WithMessage(fmt.Sprint(recovered))
+			}
+			err = appErr
+		}()
+		return c.Next()
+	}
+}
+
+func panicError(recovered any) error {
+	if err, ok := recovered.(error); ok {
+		return err
+	}
+	return fmt.Errorf("panic: %v", recovered)
 }
@@ -1,34 +1,118 @@
 package middleware
 
 import (
+	"context"
+	"encoding/json"
+	"golang-template/logger"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestRecover(t *testing.T) {
-	t.Run("test recover middleware", func(t *testing.T) {
-		errorMessage := "Error panic test"
-		app := fiber.New()
-		app.Use(Recover)
-		app.Get("/", func(c *fiber.Ctx) error {
-			panic(errorMessage)
+	errorMessage := "Error panic test"
+
+	testCaseList := []struct {
+		name            string
+		config          RecoverConfig
+		expectedMessage string
+	}{
+		{
+			name:            "generic message",
+			config:          RecoverConfig{},
+			expectedMessage: "internal server error",
+		},
+		{
+			name:            "show details",
+			config:          RecoverConfig{ShowDetails: true},
+			expectedMessage: errorMessage,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			var reports []CrashReport
+			testCase.config.Reporters = []CrashReporter{
+				CrashReporterFunc(func(ctx context.Context, report CrashReport) {
+					reports = append(reports, report)
+				}),
+			}
+
+			loggerMock := logger.NewLoggerMock()
+			loggerMock.On("With", mock.MatchedBy(func(fields logger.Fields) bool {
+				return fields["request_id"] == "req-1" && fields["panic"] == errorMessage && fields["stack"] != ""
+			})).Return(loggerMock)
+			loggerMock.On("Error", "Recovered from panic")
+
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Use(requestid.New(requestid.Config{Generator: func() string { return "req-1" }}))
+			app.Use(func(c *fiber.Ctx) error {
+				c.SetUserContext(logger.WithContext(c.UserContext(), loggerMock))
+				return c.Next()
+			})
+			app.Use(NewRecover(testCase.config))
+			app.Get("/", func(c *fiber.Ctx) error {
+				panic(errorMessage)
+			})
+			request := httptest.NewRequest("GET", "/", nil)
+			response, err := app.Test(request, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+			body, err := io.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var responseBody struct {
+				Status  string            `json:"status"`
+				Code    string            `json:"code"`
+				Message string            `json:"message"`
+				Errors  map[string]string `json:"errors"`
+			}
+			assert.NoError(t, json.Unmarshal(body, &responseBody))
+			assert.Equal(t, "error", responseBody.Status)
+			assert.Equal(t, "internal_error", responseBody.Code)
+			assert.Equal(t, testCase.expectedMessage, responseBody.Message)
+
+			assert.Len(t, reports, 1)
+			assert.Equal(t, reports[0].ErrorID, responseBody.Errors["errorId"])
+			assert.Equal(t, "req-1", reports[0].RequestID)
+			assert.Equal(t, "/", reports[0].Path)
+			assert.Contains(t, string(reports[0].Stack), "recover_test.go")
+			loggerMock.AssertExpectations(t)
 		})
-		request := httptest.NewRequest("GET", "/", nil)
-		response, err := app.Test(request, -1)
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
-		body, err := io.ReadAll(response.Body)
-		if err != nil {
-			t.Fatal(err)
-		}
-		assert.Equal(t, "{\"status\":\"error\",\"message\":\""+errorMessage+"\"}", string(body))
+	}
+}
+
+func TestRecover_ContextLoggerAddedLater(t *testing.T) {
+	loggerMock := logger.NewLoggerMock()
+	loggerMock.On("With", mock.MatchedBy(func(fields logger.Fields) bool {
+		return fields["panic"] == "late panic"
+	})).Return(loggerMock)
+	loggerMock.On("Error", "Recovered from panic")
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewRecover(RecoverConfig{}))
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(logger.WithContext(c.UserContext(), loggerMock))
+		return c.Next()
 	})
+	app.Get("/", func(c *fiber.Ctx) error {
+		panic("late panic")
+	})
+	response, err := app.Test(httptest.NewRequest("GET", "/", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	loggerMock.AssertExpectations(t)
 }
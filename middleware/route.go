@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// unmatchedRoute labels requests that did not reach a route handler, so
+// unknown paths cannot blow up the label cardinality.
+const unmatchedRoute = "unmatched"
+
+// newRouteLabel returns a function naming the route template a request was
+// handled by, to be called after c.Next().
+//
+// c.Route() ends on the last route that ran. When that is a middleware,
+// e.g. auth mounted on a group, its prefix is used instead, and requests that
+// fiber rejects as not found are all grouped as unmatched. Routes are told
+// apart from middleware by their handler slice since fiber does not export
+// the difference. They are read on the first request, once every route has
+// been registered.
+func newRouteLabel() func(c *fiber.Ctx, status int) string {
+	var once sync.Once
+	handlers := map[*fiber.Handler]struct{}{}
+
+	return func(c *fiber.Ctx, status int) string {
+		once.Do(func() {
+			for _, route := range c.App().GetRoutes(true) {
+				handlers[&route.Handlers[0]] = struct{}{}
+			}
+		})
+		route := c.Route()
+		if len(route.Handlers) > 0 {
+			if _, ok := handlers[&route.Handlers[0]]; ok {
+				return route.Path
+			}
+		}
+		if status == fiber.StatusNotFound {
+			return unmatchedRoute
+		}
+		return route.Path
+	}
+}
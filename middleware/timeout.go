@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"golang-template/app"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewTimeout bounds the request context handed to services through
+// c.UserContext(). Handlers still run to completion, but any database call
+// made after the deadline is cancelled and the response is replaced with
+// app.ErrTimeout.
+func NewTimeout(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return app.ErrTimeout
+		}
+		return err
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	testCaseList := []struct {
+		name               string
+		handler            fiber.Handler
+		expectedStatusCode int
+	}{
+		{
+			name: "completes in time",
+			handler: func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			},
+			expectedStatusCode: fiber.StatusOK,
+		},
+		{
+			name: "deadline exceeded",
+			handler: func(c *fiber.Ctx) error {
+				<-c.UserContext().Done()
+				return c.Status(fiber.StatusInternalServerError).SendString(c.UserContext().Err().Error())
+			},
+			expectedStatusCode: fiber.StatusGatewayTimeout,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Get("/", NewTimeout(20*time.Millisecond), testCase.handler)
+
+			res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), -1)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
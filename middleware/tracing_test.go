@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"golang-template/app"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	testCaseList := []struct {
+		name               string
+		path               string
+		traceParent        string
+		expectedName       string
+		expectedStatusCode int
+		expectedStatus     codes.Code
+		expectedTraceID    string
+	}{
+		{
+			name:               "continues incoming trace",
+			path:               "/users/42",
+			traceParent:        "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedName:       "GET /users/:id",
+			expectedStatusCode: fiber.StatusOK,
+			expectedStatus:     codes.Unset,
+			expectedTraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:               "server error",
+			path:               "/fail",
+			expectedName:       "GET /fail",
+			expectedStatusCode: fiber.StatusInternalServerError,
+			expectedStatus:     codes.Error,
+		},
+	}
+
+	fiberApp := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	fiberApp.Use(NewTracing())
+	fiberApp.Get("/users/:id", func(c *fiber.Ctx) error {
+		_, span := otel.Tracer("test").Start(c.UserContext(), "child")
+		span.End()
+		return c.SendStatus(fiber.StatusOK)
+	})
+	fiberApp.Get("/fail", func(c *fiber.Ctx) error {
+		return app.ErrInternal
+	})
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(fiber.MethodGet, testCase.path, nil)
+			if testCase.traceParent != "" {
+				request.Header.Set("traceparent", testCase.traceParent)
+			}
+			before := len(recorder.Ended())
+
+			response, err := fiberApp.Test(request, -1)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
+			spans := recorder.Ended()[before:]
+			server := spans[len(spans)-1]
+			assert.Equal(t, testCase.expectedName, server.Name())
+			assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+			assert.Equal(t, testCase.expectedStatus, server.Status().Code)
+			assert.Contains(t, server.Attributes(), semconv.HTTPResponseStatusCode(testCase.expectedStatusCode))
+			if testCase.expectedTraceID != "" {
+				assert.Equal(t, testCase.expectedTraceID, server.SpanContext().TraceID().String())
+				assert.Len(t, spans, 2)
+				assert.Equal(t, server.SpanContext().SpanID(), spans[0].Parent().SpanID())
+			}
+		})
+	}
+}
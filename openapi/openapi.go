@@ -0,0 +1,283 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang-template/app"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/swaggest/swgui/v5emb"
+)
+
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower case HTTP methods to their operation.
+type PathItem map[string]*OperationObject
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+type OperationObject struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Operation describes the route registered under the same name with
+// fiber's Name. Params, Query, Request and Response are zero values of the
+// types the handler parses and returns.
+type Operation struct {
+	Summary string
+	Tags    []string
+	// Permission is the permission checked by middleware.RequirePermission.
+	// Setting it or Auth marks the operation as requiring a bearer token.
+	Permission string
+	Auth       bool
+	// Params types path parameters through params tags, untyped ones are
+	// strings.
+	Params any
+	// Query is bound from query tags.
+	Query   any
+	Request any
+	// Response is the data of the app.Response envelope, nil for none.
+	Response any
+	Paged    bool
+	// Status is the success status, 200 when zero.
+	Status int
+}
+
+type Config struct {
+	Info Info
+	// Operations are keyed by route name.
+	Operations map[string]Operation
+	// PathPrefix is where every route must be documented; routes outside it,
+	// like /metrics, are left out.
+	PathPrefix string
+}
+
+const bearerAuth = "bearerAuth"
+
+// Generate documents the routes of an app. It fails when a route below
+// config.PathPrefix has no operation or an operation has no route, so the
+// document cannot drift from the routes that are served.
+func Generate(routes []fiber.Route, config Config) (*Document, error) {
+	builder := newSchemaBuilder()
+	document := &Document{
+		OpenAPI: Version,
+		Info:    config.Info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: builder.components,
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	var errs []error
+	documented := map[string]bool{}
+	for _, route := range routes {
+		if route.Method == fiber.MethodHead || !strings.HasPrefix(route.Path, config.PathPrefix) {
+			continue
+		}
+		operation, ok := config.Operations[route.Name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("route %s %s has no operation", route.Method, route.Path))
+			continue
+		}
+		documented[route.Name] = true
+
+		path, params := convertPath(route.Path)
+		if document.Paths[path] == nil {
+			document.Paths[path] = PathItem{}
+		}
+		document.Paths[path][strings.ToLower(route.Method)] = builder.operation(route.Name, operation, params)
+	}
+
+	var missing []string
+	for name := range config.Operations {
+		if !documented[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		errs = append(errs, fmt.Errorf("operation %s has no route", name))
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	builder.schema(reflect.TypeOf(app.Response{}))
+	builder.schema(reflect.TypeOf(app.Problem{}))
+	return document, nil
+}
+
+func (b *schemaBuilder) operation(name string, operation Operation, pathParams []string) *OperationObject {
+	result := &OperationObject{
+		OperationID: name,
+		Summary:     operation.Summary,
+		Tags:        operation.Tags,
+		Responses:   map[string]*Response{},
+	}
+
+	if operation.Auth || operation.Permission != "" {
+		result.Security = []map[string][]string{{bearerAuth: {}}}
+	}
+	if operation.Permission != "" {
+		result.Description = fmt.Sprintf("Requires the `%s` permission.", operation.Permission)
+	}
+
+	for _, name := range pathParams {
+		schema := &Schema{Type: "string"}
+		if operation.Params != nil {
+			for _, field := range fields(reflect.TypeOf(operation.Params), "params") {
+				if field.name == name {
+					schema, _ = b.field(field.field)
+				}
+			}
+		}
+		result.Parameters = append(result.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	if operation.Query != nil {
+		for _, field := range fields(reflect.TypeOf(operation.Query), "query") {
+			schema, required := b.field(field.field)
+			result.Parameters = append(result.Parameters, &Parameter{Name: field.name, In: "query", Required: required, Schema: schema})
+		}
+	}
+
+	if operation.Request != nil {
+		result.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{fiber.MIMEApplicationJSON: {Schema: b.schema(reflect.TypeOf(operation.Request))}},
+		}
+	}
+
+	status := operation.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	result.Responses[fmt.Sprint(status)] = &Response{
+		Description: http.StatusText(status),
+		Content:     map[string]*MediaType{fiber.MIMEApplicationJSON: {Schema: b.envelope(operation)}},
+	}
+	result.Responses["default"] = &Response{
+		Description: "Error",
+		Content: map[string]*MediaType{
+			fiber.MIMEApplicationJSON:  {Schema: b.schema(reflect.TypeOf(app.Response{}))},
+			"application/problem+json": {Schema: b.schema(reflect.TypeOf(app.Problem{}))},
+		},
+	}
+	return result
+}
+
+// envelope returns the schema of app.Response with data narrowed to the
+// operation's response type.
+func (b *schemaBuilder) envelope(operation Operation) *Schema {
+	envelope := b.schema(reflect.TypeOf(app.Response{}))
+	if operation.Response == nil && !operation.Paged {
+		return envelope
+	}
+
+	narrowed := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	if operation.Response != nil {
+		narrowed.Properties["data"] = b.schema(reflect.TypeOf(operation.Response))
+		narrowed.Required = append(narrowed.Required, "data")
+	}
+	if operation.Paged {
+		narrowed.Properties["paging"] = b.schema(reflect.TypeOf(app.Paging{}))
+		narrowed.Required = append(narrowed.Required, "paging")
+	}
+	return &Schema{AllOf: []*Schema{envelope, narrowed}}
+}
+
+// convertPath turns fiber's /users/:id into /users/{id} and returns the
+// parameter names. Trailing slashes are dropped as routing isn't strict.
+func convertPath(path string) (string, []string) {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			name = strings.TrimSuffix(name, "?")
+			params = append(params, name)
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// Handler serves the document as JSON.
+func Handler(document *Document) (fiber.Handler, error) {
+	body, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Send(body)
+	}, nil
+}
+
+// UIHandler serves Swagger UI for the document at specPath. Mount it on
+// basePath and everything below it, e.g. app.Get("/docs*", ...).
+func UIHandler(title string, specPath string, basePath string) fiber.Handler {
+	return adaptor.HTTPHandler(v5emb.New(title, specPath, basePath))
+}
@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type testItem struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name" validate:"required,min=3,max=32"`
+	Email     string    `json:"email" validate:"omitempty,email"`
+	Kind      string    `json:"kind" validate:"oneof=a b"`
+	Tags      []string  `json:"tags" validate:"max=5,dive,required"`
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type testParams struct {
+	ID int64 `params:"id" validate:"min=1"`
+}
+
+type testQuery struct {
+	Limit int `query:"limit" validate:"required,gte=1,lte=100"`
+}
+
+func TestSchemaBuilder(t *testing.T) {
+	builder := newSchemaBuilder()
+
+	schema := builder.schema(reflect.TypeOf([]testItem{}))
+
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/testItem"}}, schema)
+	minLength, maxLength, maxItems := 3, 32, 5
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"id":        {Type: "integer", Format: "int64"},
+			"name":      {Type: "string", MinLength: &minLength, MaxLength: &maxLength},
+			"email":     {Type: "string", Format: "email"},
+			"kind":      {Type: "string", Enum: []any{"a", "b"}},
+			"tags":      {Type: "array", Items: &Schema{Type: "string"}, MaxItems: &maxItems},
+			"createdAt": {Type: "string", Format: "date-time"},
+		},
+		Required: []string{"name"},
+	}, builder.components["testItem"])
+}
+
+func TestGenerate(t *testing.T) {
+	app := fiber.New()
+	app.Get("/api/items/:id", func(c *fiber.Ctx) error { return nil }).Name("getItem")
+	app.Post("/api/items/", func(c *fiber.Ctx) error { return nil }).Name("createItem")
+	app.Get("/metrics", func(c *fiber.Ctx) error { return nil })
+
+	document, err := Generate(app.GetRoutes(true), Config{
+		Info: Info{Title: "Test", Version: "1.0.0"},
+		Operations: map[string]Operation{
+			"getItem":    {Permission: "item:read", Params: testParams{}, Query: testQuery{}, Response: testItem{}},
+			"createItem": {Request: testItem{}, Status: fiber.StatusCreated},
+		},
+		PathPrefix: "/api/",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, document.Paths, 2)
+	getItem := document.Paths["/api/items/{id}"]["get"]
+	one, hundred := 1.0, 100.0
+	assert.Equal(t, []*Parameter{
+		{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer", Format: "int64", Minimum: &one}},
+		{Name: "limit", In: "query", Required: true, Schema: &Schema{Type: "integer", Format: "int32", Minimum: &one, Maximum: &hundred}},
+	}, getItem.Parameters)
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, getItem.Security)
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/testItem"}, getItem.Responses["200"].Content[fiber.MIMEApplicationJSON].Schema.AllOf[1].Properties["data"])
+
+	createItem := document.Paths["/api/items"]["post"]
+	assert.Nil(t, createItem.Security)
+	assert.NotNil(t, createItem.RequestBody)
+	assert.Contains(t, createItem.Responses, "201")
+	assert.Contains(t, document.Components.Schemas, "Response")
+}
+
+func TestGenerate_Drift(t *testing.T) {
+	app := fiber.New()
+	app.Get("/api/items", func(c *fiber.Ctx) error { return nil })
+
+	_, err := Generate(app.GetRoutes(true), Config{
+		Operations: map[string]Operation{"deleteItem": {}},
+		PathPrefix: "/api/",
+	})
+
+	assert.ErrorContains(t, err, "route GET /api/items has no operation")
+	assert.ErrorContains(t, err, "operation deleteItem has no route")
+}
+
+func TestUIHandler(t *testing.T) {
+	app := fiber.New()
+	app.Get("/docs*", UIHandler("Test", "/openapi.json", "/docs"))
+
+	response, err := app.Test(httptest.NewRequest("GET", "/docs", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fiber.StatusOK, response.StatusCode)
+}
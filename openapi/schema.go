@@ -0,0 +1,260 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema 2020-12 used by OpenAPI 3.1 that the
+// generator produces.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+}
+
+// Rule documents a custom validate tag on the schema of the field it is set
+// on. param is the text after "=", if any.
+type Rule func(schema *Schema, param string)
+
+// rules holds the custom tags of the validator package; the built-in tags
+// are handled by applyTag.
+var rules = map[string]Rule{
+	"username": func(schema *Schema, param string) {
+		schema.Pattern = `^[a-zA-Z0-9._-]{3,32}$`
+	},
+	"password": func(schema *Schema, param string) {
+		schema.MinLength = intPtr(8)
+		schema.Description = "Must be at most 72 bytes and contain an upper case letter, a lower case letter and a digit."
+	},
+}
+
+// RegisterRule documents a custom validate tag registered with
+// validator.RegisterRule. Unknown tags are left out of the schema.
+func RegisterRule(tag string, rule Rule) {
+	rules[tag] = rule
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+type schemaBuilder struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{components: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+// schema returns the schema of t. Named structs are added to the components
+// once and referenced from everywhere else.
+func (b *schemaBuilder) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		return &Schema{Ref: "#/components/schemas/" + b.component(t)}
+	case t.Kind() == reflect.Struct:
+		return b.object(t, "json")
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	}
+	// Interfaces and anything else accept any value.
+	return &Schema{}
+}
+
+func (b *schemaBuilder) component(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := b.components[name]; taken {
+		name = strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + name
+	}
+	b.names[t] = name
+	// Reserved before building so recursive types refer to themselves.
+	b.components[name] = &Schema{}
+	*b.components[name] = *b.object(t, "json")
+	return name
+}
+
+// object builds the schema of a struct from the fields named by tagKey.
+func (b *schemaBuilder) object(t reflect.Type, tagKey string) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, field := range fields(t, tagKey) {
+		property, required := b.field(field.field)
+		schema.Properties[field.name] = property
+		if required {
+			schema.Required = append(schema.Required, field.name)
+		}
+	}
+	return schema
+}
+
+// field returns the schema of a struct field with its validate tag applied
+// and whether the tag makes it required.
+func (b *schemaBuilder) field(field reflect.StructField) (*Schema, bool) {
+	schema := b.schema(field.Type)
+	if schema.Ref != "" {
+		// Keywords next to $ref are allowed in 3.1 but not shown by every
+		// tool, so only plain schemas are annotated.
+		return schema, hasTag(field, "required")
+	}
+
+	required := false
+	for _, tag := range strings.Split(field.Tag.Get("validate"), ",") {
+		name, param, _ := strings.Cut(tag, "=")
+		if name == "dive" {
+			break
+		}
+		if name == "required" {
+			required = true
+			continue
+		}
+		applyTag(schema, name, param)
+	}
+	return schema, required
+}
+
+func applyTag(schema *Schema, name string, param string) {
+	switch name {
+	case "email":
+		schema.Format = "email"
+	case "url":
+		schema.Format = "uri"
+	case "uuid", "uuid4":
+		schema.Format = "uuid"
+	case "oneof":
+		for _, value := range strings.Fields(param) {
+			schema.Enum = append(schema.Enum, value)
+		}
+	case "len":
+		applyBound(schema, "min", param)
+		applyBound(schema, "max", param)
+	case "min", "gte", "max", "lte", "gt", "lt":
+		applyBound(schema, name, param)
+	default:
+		if rule, ok := rules[name]; ok {
+			rule(schema, param)
+		}
+	}
+}
+
+func applyBound(schema *Schema, name string, param string) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case "string", "array":
+		length := intPtr(int(n))
+		lower := name == "min" || name == "gte" || name == "gt"
+		switch {
+		case schema.Type == "string" && lower:
+			schema.MinLength = length
+		case schema.Type == "string":
+			schema.MaxLength = length
+		case lower:
+			schema.MinItems = length
+		default:
+			schema.MaxItems = length
+		}
+	case "integer", "number":
+		switch name {
+		case "min", "gte":
+			schema.Minimum = &n
+		case "max", "lte":
+			schema.Maximum = &n
+		case "gt":
+			schema.ExclusiveMinimum = &n
+		case "lt":
+			schema.ExclusiveMaximum = &n
+		}
+	}
+}
+
+type namedField struct {
+	name  string
+	field reflect.StructField
+}
+
+// fields lists the exported fields of t under the name in their tagKey tag,
+// or the Go name when the tag is missing, including embedded structs.
+func fields(t reflect.Type, tagKey string) []namedField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var result []namedField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get(tagKey), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			result = append(result, fields(field.Type, tagKey)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		result = append(result, namedField{name: name, field: field})
+	}
+	return result
+}
+
+func hasTag(field reflect.StructField, tag string) bool {
+	for _, t := range strings.Split(field.Tag.Get("validate"), ",") {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,20 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LimiterMock struct {
+	mock.Mock
+}
+
+func NewLimiterMock() *LimiterMock {
+	return &LimiterMock{}
+}
+
+func (m *LimiterMock) Allow(ctx context.Context, policy Policy, key string) (Result, error) {
+	args := m.Mock.Called(ctx, policy, key)
+	return args.Get(0).(Result), args.Error(1)
+}
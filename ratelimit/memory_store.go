@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired keys are removed from a store.
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	state   State
+	expires time.Time
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore keeps state in process memory, so limits reset on restart
+// and are not shared between instances.
+func NewMemoryStore() Store {
+	return &memoryStore{entries: map[string]memoryEntry{}, now: time.Now}
+}
+
+func (s *memoryStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(state *State)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, entry := range s.entries {
+			if now.After(entry.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	entry, ok := s.entries[key]
+	if !ok || now.After(entry.expires) {
+		entry = memoryEntry{}
+	}
+	fn(&entry.state)
+	entry.expires = now.Add(ttl)
+	s.entries[key] = entry
+	return nil
+}
@@ -0,0 +1,193 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+type Algorithm string
+
+const (
+	// TokenBucket allows bursts of up to Limit requests and refills Limit
+	// tokens per Window.
+	TokenBucket Algorithm = "token_bucket"
+	// SlidingWindow allows Limit requests in any Window, weighing the
+	// previous fixed window by how much of it still overlaps.
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+type KeyKind string
+
+const (
+	KeyIP KeyKind = "ip"
+	// KeyUser limits authenticated clients by user ID and everyone else by
+	// IP.
+	KeyUser KeyKind = "user"
+	// KeyAPIKey limits by the X-API-Key header, falling back to the IP.
+	KeyAPIKey KeyKind = "api_key"
+)
+
+type Policy struct {
+	Name string `yaml:"name" validate:"required"`
+	// Routes are "[METHOD ]pattern" entries matched with path.Match, e.g.
+	// "POST /api/v1/user/register" or "/api/*". An empty list matches every
+	// request.
+	Routes    []string      `yaml:"routes"`
+	Algorithm Algorithm     `yaml:"algorithm" validate:"oneof=token_bucket sliding_window"`
+	Limit     int           `yaml:"limit" validate:"gt=0"`
+	Window    time.Duration `yaml:"window" validate:"gt=0"`
+	Key       KeyKind       `yaml:"key" validate:"oneof=ip user api_key"`
+}
+
+type Config struct {
+	Enabled bool   `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	Store   string `yaml:"store" env:"RATE_LIMIT_STORE" validate:"oneof=memory sqlite"`
+	// Policies are tried in order and the first one matching the request
+	// applies.
+	Policies []Policy `yaml:"policies" validate:"dive"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Enabled: true,
+		Store:   "memory",
+		Policies: []Policy{
+			{Name: "register", Routes: []string{"POST /api/v1/user/register"}, Algorithm: SlidingWindow, Limit: 5, Window: time.Hour, Key: KeyIP},
+			{Name: "login", Routes: []string{"POST /api/v1/auth/login"}, Algorithm: SlidingWindow, Limit: 10, Window: time.Minute, Key: KeyIP},
+			{Name: "resend-verification", Routes: []string{"POST /api/v1/auth/resend-verification"}, Algorithm: SlidingWindow, Limit: 3, Window: time.Hour, Key: KeyIP},
+			{Name: "forgot-password", Routes: []string{"POST /api/v1/auth/forgot-password"}, Algorithm: SlidingWindow, Limit: 3, Window: time.Hour, Key: KeyIP},
+			{Name: "mfa-verify", Routes: []string{"POST /api/v1/auth/mfa/verify"}, Algorithm: SlidingWindow, Limit: 10, Window: time.Minute, Key: KeyIP},
+			{Name: "api", Routes: []string{"/api/*"}, Algorithm: TokenBucket, Limit: 100, Window: time.Minute, Key: KeyUser},
+		},
+	}
+}
+
+// State is what the algorithms keep per key. Value is the token count or the
+// current window's request count, Previous the previous window's count and
+// UpdatedAt the last refill or the start of the current window.
+type State struct {
+	Value     float64
+	Previous  float64
+	UpdatedAt time.Time
+}
+
+type Store interface {
+	// Update loads the state of key, or a zero State, lets fn change it and
+	// saves it atomically. The state may be dropped once ttl has passed.
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(state *State)) error
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is when the client has its full limit again.
+	Reset time.Duration
+	// RetryAfter is when the next request is allowed, zero when allowed.
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	// Allow counts a request of key against policy.
+	Allow(ctx context.Context, policy Policy, key string) (Result, error)
+}
+
+type limiter struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewLimiter(store Store) Limiter {
+	return &limiter{store: store, now: time.Now}
+}
+
+func (l *limiter) Allow(ctx context.Context, policy Policy, key string) (Result, error) {
+	var take func(state *State, now time.Time) Result
+	switch policy.Algorithm {
+	case TokenBucket:
+		take = policy.takeToken
+	case SlidingWindow:
+		take = policy.takeSlidingWindow
+	default:
+		return Result{}, fmt.Errorf("unknown rate limit algorithm %q", policy.Algorithm)
+	}
+
+	var result Result
+	now := l.now()
+	// The previous window is still needed while it overlaps the current one.
+	err := l.store.Update(ctx, policy.Name+":"+key, 2*policy.Window, func(state *State) {
+		result = take(state, now)
+	})
+	return result, err
+}
+
+func (p Policy) takeToken(state *State, now time.Time) Result {
+	limit := float64(p.Limit)
+	perToken := p.Window / time.Duration(p.Limit)
+
+	tokens := limit
+	if !state.UpdatedAt.IsZero() {
+		elapsed := now.Sub(state.UpdatedAt)
+		tokens = math.Min(limit, state.Value+float64(elapsed)/float64(perToken))
+	}
+
+	result := Result{Limit: p.Limit}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(perToken))
+	}
+	result.Remaining = int(tokens)
+	result.Reset = time.Duration((limit - tokens) * float64(perToken))
+
+	state.Value = tokens
+	state.UpdatedAt = now
+	return result
+}
+
+func (p Policy) takeSlidingWindow(state *State, now time.Time) Result {
+	windowStart := now.Truncate(p.Window)
+	switch {
+	case state.UpdatedAt.Equal(windowStart):
+	case state.UpdatedAt.Equal(windowStart.Add(-p.Window)):
+		state.Previous = state.Value
+		state.Value = 0
+	default:
+		state.Previous = 0
+		state.Value = 0
+	}
+	state.UpdatedAt = windowStart
+
+	elapsed := now.Sub(windowStart)
+	overlap := 1 - float64(elapsed)/float64(p.Window)
+	count := state.Previous*overlap + state.Value
+	untilNextWindow := p.Window - elapsed
+
+	result := Result{Limit: p.Limit}
+	if count+1 <= float64(p.Limit) {
+		state.Value++
+		result.Allowed = true
+		result.Remaining = int(float64(p.Limit) - count - 1)
+	} else {
+		// Wait until enough of the previous window has slid out, or for the
+		// next window when the current one alone is over the limit.
+		result.RetryAfter = untilNextWindow
+		if state.Previous > 0 && state.Value+1 <= float64(p.Limit) {
+			needed := time.Duration((count + 1 - float64(p.Limit)) / state.Previous * float64(p.Window))
+			result.RetryAfter = min(needed, untilNextWindow)
+		}
+	}
+
+	// Requests of the current window keep counting until the end of the next
+	// one, those of the previous window until the end of this one.
+	switch {
+	case state.Value > 0:
+		result.Reset = untilNextWindow + p.Window
+	case state.Previous > 0:
+		result.Reset = untilNextWindow
+	}
+	return result
+}
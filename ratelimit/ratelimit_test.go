@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type step struct {
+	after              time.Duration
+	expectedAllowed    bool
+	expectedRemaining  int
+	expectedRetryAfter time.Duration
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	testCaseList := []struct {
+		name   string
+		policy Policy
+		steps  []step
+	}{
+		{
+			name:   "token bucket allows a burst then refills",
+			policy: Policy{Name: "api", Algorithm: TokenBucket, Limit: 2, Window: time.Minute},
+			steps: []step{
+				{expectedAllowed: true, expectedRemaining: 1},
+				{expectedAllowed: true, expectedRemaining: 0},
+				{expectedAllowed: false, expectedRemaining: 0, expectedRetryAfter: 30 * time.Second},
+				{after: 30 * time.Second, expectedAllowed: true, expectedRemaining: 0},
+				{after: 2 * time.Minute, expectedAllowed: true, expectedRemaining: 1},
+			},
+		},
+		{
+			name:   "sliding window counts the overlapping previous window",
+			policy: Policy{Name: "login", Algorithm: SlidingWindow, Limit: 2, Window: time.Minute},
+			steps: []step{
+				{expectedAllowed: true, expectedRemaining: 1},
+				{expectedAllowed: true, expectedRemaining: 0},
+				{expectedAllowed: false, expectedRemaining: 0, expectedRetryAfter: time.Minute},
+				// 15s into the next window 3/4 of the previous one still
+				// counts, 1.5 requests, so one more is only allowed once a
+				// quarter of it has slid out.
+				{after: 75 * time.Second, expectedAllowed: false, expectedRemaining: 0, expectedRetryAfter: 15 * time.Second},
+				{after: 90 * time.Second, expectedAllowed: true, expectedRemaining: 0},
+				{after: 3 * time.Minute, expectedAllowed: true, expectedRemaining: 1},
+			},
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			limiter := NewLimiter(NewMemoryStore()).(*limiter)
+
+			for i, step := range testCase.steps {
+				limiter.now = func() time.Time { return start.Add(step.after) }
+
+				result, err := limiter.Allow(context.Background(), testCase.policy, "ip:1.2.3.4")
+
+				assert.NoError(t, err)
+				assert.Equal(t, step.expectedAllowed, result.Allowed, "step %d", i)
+				assert.Equal(t, step.expectedRemaining, result.Remaining, "step %d", i)
+				assert.Equal(t, step.expectedRetryAfter, result.RetryAfter, "step %d", i)
+				assert.Equal(t, testCase.policy.Limit, result.Limit)
+			}
+		})
+	}
+}
+
+func TestLimiter_UnknownAlgorithm(t *testing.T) {
+	limiter := NewLimiter(NewMemoryStore())
+
+	_, err := limiter.Allow(context.Background(), Policy{Name: "api", Algorithm: "leaky_bucket", Limit: 1, Window: time.Second}, "ip:1.2.3.4")
+
+	assert.Error(t, err)
+}
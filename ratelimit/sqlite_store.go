@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+type sqliteStore struct {
+	// mu serializes updates: SQLite fails instead of waiting when two
+	// deferred transactions both try to upgrade their read lock.
+	mu        sync.Mutex
+	db        *sql.DB
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewSQLiteStore keeps state in the rate_limits table, so limits survive
+// restarts.
+func NewSQLiteStore(db *sql.DB) Store {
+	return &sqliteStore{db: db, now: time.Now}
+}
+
+func (s *sqliteStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(state *State)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM rate_limits WHERE expires_at < ?`, now.UnixNano()); err != nil {
+			return err
+		}
+		s.lastSweep = now
+	}
+
+	var state State
+	var updatedAt int64
+	query := `SELECT value, previous, updated_at FROM rate_limits WHERE key = ? AND expires_at >= ?`
+	err = tx.QueryRowContext(ctx, query, key, now.UnixNano()).Scan(&state.Value, &state.Previous, &updatedAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return err
+	default:
+		state.UpdatedAt = time.Unix(0, updatedAt)
+	}
+
+	fn(&state)
+
+	query = `
+		INSERT INTO rate_limits (key, value, previous, updated_at, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			value = excluded.value,
+			previous = excluded.previous,
+			updated_at = excluded.updated_at,
+			expires_at = excluded.expires_at
+	`
+	_, err = tx.ExecContext(ctx, query, key, state.Value, state.Previous, state.UpdatedAt.UnixNano(), now.Add(ttl).UnixNano())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
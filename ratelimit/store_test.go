@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang-template/database"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.NewMigrator(db, database.Migrations(), database.MigratorConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestStore_Update(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCaseList := []struct {
+		name     string
+		newStore func(t *testing.T, now *time.Time) Store
+	}{
+		{
+			name: "memory",
+			newStore: func(t *testing.T, now *time.Time) Store {
+				store := NewMemoryStore().(*memoryStore)
+				store.now = func() time.Time { return *now }
+				return store
+			},
+		},
+		{
+			name: "sqlite",
+			newStore: func(t *testing.T, now *time.Time) Store {
+				store := NewSQLiteStore(newTestDB(t)).(*sqliteStore)
+				store.now = func() time.Time { return *now }
+				return store
+			},
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			now := start
+			store := testCase.newStore(t, &now)
+			increment := func() State {
+				var saved State
+				err := store.Update(context.Background(), "login:ip:1.2.3.4", time.Minute, func(state *State) {
+					state.Value++
+					state.Previous = 2
+					state.UpdatedAt = now
+					saved = *state
+				})
+				assert.NoError(t, err)
+				return saved
+			}
+
+			assert.Equal(t, 1.0, increment().Value)
+			now = now.Add(30 * time.Second)
+			state := increment()
+			assert.Equal(t, 2.0, state.Value)
+			assert.Equal(t, 2.0, state.Previous)
+
+			now = now.Add(2 * time.Minute)
+			assert.Equal(t, 1.0, increment().Value, "expired state starts over")
+		})
+	}
+}
+
+func TestSQLiteStore_Persists(t *testing.T) {
+	db := newTestDB(t)
+	policy := Policy{Name: "login", Algorithm: SlidingWindow, Limit: 1, Window: time.Minute}
+
+	result, err := NewLimiter(NewSQLiteStore(db)).Allow(context.Background(), policy, "ip:1.2.3.4")
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// A new store on the same database, as after a restart.
+	result, err = NewLimiter(NewSQLiteStore(db)).Allow(context.Background(), policy, "ip:1.2.3.4")
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+}
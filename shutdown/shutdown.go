@@ -0,0 +1,81 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Hook func(ctx context.Context) error
+
+type Manager interface {
+	// Register adds a hook; hooks run in reverse registration order so
+	// resources are released after everything that depends on them.
+	Register(name string, hook Hook)
+	Ready() bool
+	Shutdown(ctx context.Context) error
+}
+
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+type manager struct {
+	mu    sync.Mutex
+	hooks []namedHook
+	ready atomic.Bool
+	once  sync.Once
+	err   error
+	delay time.Duration
+}
+
+// NewManager returns a Manager that reports ready until Shutdown is called.
+// delay is how long Shutdown waits after failing readiness before running the
+// hooks, giving load balancers time to stop routing traffic.
+func NewManager(delay time.Duration) Manager {
+	m := &manager{delay: delay}
+	m.ready.Store(true)
+	return m
+}
+
+func (m *manager) Register(name string, hook Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, namedHook{name: name, hook: hook})
+}
+
+func (m *manager) Ready() bool {
+	return m.ready.Load()
+}
+
+// Shutdown runs every hook even if earlier ones fail and returns the joined
+// errors. Calling it more than once returns the result of the first call.
+func (m *manager) Shutdown(ctx context.Context) error {
+	m.once.Do(func() {
+		m.ready.Store(false)
+
+		if m.delay > 0 {
+			select {
+			case <-time.After(m.delay):
+			case <-ctx.Done():
+			}
+		}
+
+		m.mu.Lock()
+		hooks := append([]namedHook(nil), m.hooks...)
+		m.mu.Unlock()
+
+		var errs []error
+		for i := len(hooks) - 1; i >= 0; i-- {
+			if err := hooks[i].hook(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", hooks[i].name, err))
+			}
+		}
+		m.err = errors.Join(errs...)
+	})
+	return m.err
+}
@@ -0,0 +1,78 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	manager := NewManager(0)
+	var order []string
+	var readyDuringHooks bool
+
+	manager.Register("database", func(ctx context.Context) error {
+		order = append(order, "database")
+		return nil
+	})
+	manager.Register("worker", func(ctx context.Context) error {
+		order = append(order, "worker")
+		return errors.New("worker stuck")
+	})
+	manager.Register("http server", func(ctx context.Context) error {
+		order = append(order, "http server")
+		readyDuringHooks = manager.Ready()
+		return nil
+	})
+
+	assert.True(t, manager.Ready())
+
+	err := manager.Shutdown(context.Background())
+
+	assert.EqualError(t, err, "worker: worker stuck")
+	assert.Equal(t, []string{"http server", "worker", "database"}, order)
+	assert.False(t, readyDuringHooks)
+	assert.False(t, manager.Ready())
+
+	assert.Equal(t, err, manager.Shutdown(context.Background()))
+	assert.Len(t, order, 3)
+}
+
+func TestManager_ShutdownDelay(t *testing.T) {
+	testCaseList := []struct {
+		name        string
+		delay       time.Duration
+		timeout     time.Duration
+		minDuration time.Duration
+		maxDuration time.Duration
+	}{
+		{name: "waits for delay", delay: 50 * time.Millisecond, timeout: time.Second, minDuration: 50 * time.Millisecond, maxDuration: time.Second},
+		{name: "context cuts delay short", delay: time.Minute, timeout: 20 * time.Millisecond, minDuration: 20 * time.Millisecond, maxDuration: time.Second},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			manager := NewManager(testCase.delay)
+			hookCalled := false
+			manager.Register("database", func(ctx context.Context) error {
+				hookCalled = true
+				return nil
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), testCase.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := manager.Shutdown(ctx)
+			elapsed := time.Since(start)
+
+			assert.NoError(t, err)
+			assert.True(t, hookCalled)
+			assert.GreaterOrEqual(t, elapsed, testCase.minDuration)
+			assert.Less(t, elapsed, testCase.maxDuration)
+		})
+	}
+}
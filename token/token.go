@@ -0,0 +1,193 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+type Config struct {
+	Algorithm       string
+	Secret          string
+	PrivateKeyPEM   string
+	Issuer          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+type Identity struct {
+	UserID      string
+	Username    string
+	Roles       []string
+	Permissions []string
+}
+
+type Claims struct {
+	jwt.RegisteredClaims
+	Username    string   `json:"username"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+func (c *Claims) UserID() string {
+	return c.Subject
+}
+
+func (c *Claims) HasPermission(permission string) bool {
+	return slices.Contains(c.Permissions, permission)
+}
+
+type Manager interface {
+	GenerateAccessToken(identity Identity) (string, error)
+	ParseAccessToken(tokenString string) (*Claims, error)
+	GenerateRefreshToken() (string, error)
+	AccessTokenTTL() time.Duration
+	RefreshTokenTTL() time.Duration
+}
+
+type manager struct {
+	config        Config
+	signingMethod jwt.SigningMethod
+	signingKey    crypto.PrivateKey
+	verifyKey     crypto.PublicKey
+}
+
+func NewManager(config Config) (Manager, error) {
+	m := &manager{config: config}
+
+	switch config.Algorithm {
+	case AlgorithmHS256, "":
+		if config.Secret == "" {
+			return nil, errors.New("token secret is required for HS256")
+		}
+		m.signingMethod = jwt.SigningMethodHS256
+		m.signingKey = []byte(config.Secret)
+		m.verifyKey = []byte(config.Secret)
+	case AlgorithmEdDSA:
+		privateKey, err := parseEd25519PrivateKey(config.PrivateKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		m.signingMethod = jwt.SigningMethodEdDSA
+		m.signingKey = privateKey
+		m.verifyKey = privateKey.Public()
+	default:
+		return nil, fmt.Errorf("unsupported token algorithm %q", config.Algorithm)
+	}
+
+	if m.config.AccessTokenTTL <= 0 {
+		m.config.AccessTokenTTL = 15 * time.Minute
+	}
+	if m.config.RefreshTokenTTL <= 0 {
+		m.config.RefreshTokenTTL = 7 * 24 * time.Hour
+	}
+
+	return m, nil
+}
+
+func (m *manager) GenerateAccessToken(identity Identity) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.config.Issuer,
+			Subject:   identity.UserID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.config.AccessTokenTTL)),
+		},
+		Username:    identity.Username,
+		Roles:       identity.Roles,
+		Permissions: identity.Permissions,
+	}
+
+	return jwt.NewWithClaims(m.signingMethod, claims).SignedString(m.signingKey)
+}
+
+func (m *manager) ParseAccessToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{m.signingMethod.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if m.config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(m.config.Issuer))
+	}
+
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return m.verifyKey, nil
+	}, options...)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func (m *manager) GenerateRefreshToken() (string, error) {
+	return GenerateOpaque()
+}
+
+func (m *manager) AccessTokenTTL() time.Duration {
+	return m.config.AccessTokenTTL
+}
+
+func (m *manager) RefreshTokenTTL() time.Duration {
+	return m.config.RefreshTokenTTL
+}
+
+// HashRefreshToken returns the value stored in the database, so a leaked
+// table can't be used to refresh sessions.
+func HashRefreshToken(refreshToken string) string {
+	return HashOpaque(refreshToken)
+}
+
+// GenerateOpaque returns a random URL safe token for values that are looked
+// up by their hash, like refresh tokens and email verification links.
+func GenerateOpaque() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func HashOpaque(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func parseEd25519PrivateKey(privateKeyPEM string) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, errors.New("token private key must be PEM encoded")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("token private key is not an Ed25519 key")
+	}
+	return privateKey, nil
+}
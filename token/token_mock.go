@@ -0,0 +1,43 @@
+package token
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ManagerMock struct {
+	mock.Mock
+}
+
+func NewManagerMock() *ManagerMock {
+	return &ManagerMock{}
+}
+
+func (m *ManagerMock) GenerateAccessToken(identity Identity) (string, error) {
+	args := m.Mock.Called(identity)
+	return args.String(0), args.Error(1)
+}
+
+func (m *ManagerMock) ParseAccessToken(tokenString string) (*Claims, error) {
+	args := m.Mock.Called(tokenString)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Claims), args.Error(1)
+}
+
+func (m *ManagerMock) GenerateRefreshToken() (string, error) {
+	args := m.Mock.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *ManagerMock) AccessTokenTTL() time.Duration {
+	args := m.Mock.Called()
+	return args.Get(0).(time.Duration)
+}
+
+func (m *ManagerMock) RefreshTokenTTL() time.Duration {
+	args := m.Mock.Called()
+	return args.Get(0).(time.Duration)
+}
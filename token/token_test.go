@@ -0,0 +1,122 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newEd25519PEM(t *testing.T) string {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func TestManager_AccessToken(t *testing.T) {
+	testCaseList := []struct {
+		name   string
+		config Config
+	}{
+		{
+			name:   "HS256",
+			config: Config{Algorithm: AlgorithmHS256, Secret: "secret", Issuer: "test"},
+		},
+		{
+			name:   "EdDSA",
+			config: Config{Algorithm: AlgorithmEdDSA, PrivateKeyPEM: newEd25519PEM(t), Issuer: "test"},
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			manager, err := NewManager(testCase.config)
+			assert.NoError(t, err)
+
+			accessToken, err := manager.GenerateAccessToken(Identity{
+				UserID:      "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01",
+				Username:    "testuser",
+				Roles:       []string{"admin"},
+				Permissions: []string{"user:read"},
+			})
+			assert.NoError(t, err)
+
+			claims, err := manager.ParseAccessToken(accessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01", claims.UserID())
+			assert.Equal(t, "testuser", claims.Username)
+			assert.Equal(t, "test", claims.Issuer)
+			assert.Equal(t, []string{"admin"}, claims.Roles)
+			assert.True(t, claims.HasPermission("user:read"))
+			assert.False(t, claims.HasPermission("user:write"))
+		})
+	}
+}
+
+func TestManager_ParseAccessToken(t *testing.T) {
+	manager, _ := NewManager(Config{Secret: "secret"})
+	otherManager, _ := NewManager(Config{Secret: "other-secret"})
+	expiredManager, _ := NewManager(Config{Secret: "secret", AccessTokenTTL: time.Nanosecond})
+
+	otherToken, _ := otherManager.GenerateAccessToken(Identity{UserID: "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01", Username: "testuser"})
+	expiredToken, _ := expiredManager.GenerateAccessToken(Identity{UserID: "0190f5a6-3c1e-7b2a-9d4f-5e6a7b8c9d01", Username: "testuser"})
+	time.Sleep(time.Millisecond)
+
+	testCaseList := []struct {
+		name        string
+		accessToken string
+	}{
+		{name: "malformed token", accessToken: "not-a-token"},
+		{name: "wrong signature", accessToken: otherToken},
+		{name: "expired token", accessToken: expiredToken},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			claims, err := manager.ParseAccessToken(testCase.accessToken)
+			assert.Nil(t, claims)
+			assert.Equal(t, ErrInvalidToken, err)
+		})
+	}
+}
+
+func TestNewManager(t *testing.T) {
+	testCaseList := []struct {
+		name   string
+		config Config
+	}{
+		{name: "HS256 without secret", config: Config{Algorithm: AlgorithmHS256}},
+		{name: "EdDSA without key", config: Config{Algorithm: AlgorithmEdDSA}},
+		{name: "unsupported algorithm", config: Config{Algorithm: "RS256", Secret: "secret"}},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewManager(testCase.config)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestManager_GenerateRefreshToken(t *testing.T) {
+	manager, _ := NewManager(Config{Secret: "secret"})
+
+	first, err := manager.GenerateRefreshToken()
+	assert.NoError(t, err)
+	second, err := manager.GenerateRefreshToken()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, HashRefreshToken(first), HashRefreshToken(first))
+	assert.NotEqual(t, first, HashRefreshToken(first))
+}
@@ -0,0 +1,144 @@
+// Package totp implements the time-based one-time passwords of RFC 6238 that
+// authenticator apps generate.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+)
+
+type Algorithm string
+
+const (
+	SHA1   Algorithm = "SHA1"
+	SHA256 Algorithm = "SHA256"
+	SHA512 Algorithm = "SHA512"
+)
+
+var ErrInvalidSecret = errors.New("invalid totp secret")
+
+// Options are the code parameters shared with the authenticator app through
+// the otpauth URI.
+type Options struct {
+	Algorithm Algorithm
+	Digits    int
+	Period    time.Duration
+}
+
+// DefaultOptions are the parameters every authenticator app supports.
+var DefaultOptions = Options{Algorithm: SHA1, Digits: 6, Period: 30 * time.Second}
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random 160 bit secret, base32 encoded as apps
+// expect it.
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(buf), nil
+}
+
+// Step returns the number of periods since the Unix epoch at t.
+func Step(t time.Time, options Options) int64 {
+	return t.Unix() / int64(options.Period/time.Second)
+}
+
+// Code returns the code of the time step.
+func Code(secret string, step int64, options Options) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	var newHash func() hash.Hash
+	switch options.Algorithm {
+	case SHA1, "":
+		newHash = sha1.New
+	case SHA256:
+		newHash = sha256.New
+	case SHA512:
+		newHash = sha512.New
+	default:
+		return "", fmt.Errorf("unsupported totp algorithm %q", options.Algorithm)
+	}
+
+	mac := hmac.New(newHash, key)
+	_ = binary.Write(mac, binary.BigEndian, uint64(step))
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation, RFC 4226 section 5.3.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulo := uint32(1)
+	for i := 0; i < options.Digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", options.Digits, value%modulo), nil
+}
+
+// Validate reports whether code is the code of a time step at most skew
+// periods before or after t, and returns that step so callers can refuse to
+// accept it twice.
+func Validate(secret string, code string, t time.Time, skew int, options Options) (int64, bool) {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != options.Digits {
+		return 0, false
+	}
+
+	current := Step(t, options)
+	for offset := -int64(skew); offset <= int64(skew); offset++ {
+		expected, err := Code(secret, current+offset, options)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return current + offset, true
+		}
+	}
+	return 0, false
+}
+
+// URI returns the otpauth URI authenticator apps import, usually from a QR
+// code. account is shown next to issuer in the app.
+func URI(issuer string, account string, secret string, options Options) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", string(options.Algorithm))
+	query.Set("digits", strconv.Itoa(options.Digits))
+	query.Set("period", strconv.Itoa(int(options.Period/time.Second)))
+
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// QRCode returns a PNG of size by size pixels encoding content.
+func QRCode(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	key, err := encoding.DecodeString(strings.TrimRight(secret, "="))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
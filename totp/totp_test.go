@@ -0,0 +1,122 @@
+package totp
+
+import (
+	"bytes"
+	"encoding/base32"
+	"image/png"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The secrets and codes of RFC 6238 appendix B.
+var (
+	rfcSecretSHA1   = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	rfcSecretSHA256 = base32.StdEncoding.EncodeToString([]byte("12345678901234567890123456789012"))
+	rfcSecretSHA512 = base32.StdEncoding.EncodeToString([]byte("1234567890123456789012345678901234567890123456789012345678901234"))
+)
+
+func TestCode_RFC6238(t *testing.T) {
+	testCaseList := []struct {
+		unix   int64
+		sha1   string
+		sha256 string
+		sha512 string
+	}{
+		{unix: 59, sha1: "94287082", sha256: "46119246", sha512: "90693936"},
+		{unix: 1111111109, sha1: "07081804", sha256: "68084774", sha512: "25091201"},
+		{unix: 1111111111, sha1: "14050471", sha256: "67062674", sha512: "99943326"},
+		{unix: 1234567890, sha1: "89005924", sha256: "91819424", sha512: "93441116"},
+		{unix: 2000000000, sha1: "69279037", sha256: "90698825", sha512: "38618901"},
+		{unix: 20000000000, sha1: "65353130", sha256: "77737706", sha512: "47863826"},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(time.Unix(testCase.unix, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+			for _, vector := range []struct {
+				algorithm Algorithm
+				secret    string
+				expected  string
+			}{
+				{SHA1, rfcSecretSHA1, testCase.sha1},
+				{SHA256, rfcSecretSHA256, testCase.sha256},
+				{SHA512, rfcSecretSHA512, testCase.sha512},
+			} {
+				options := Options{Algorithm: vector.algorithm, Digits: 8, Period: 30 * time.Second}
+				code, err := Code(vector.secret, Step(time.Unix(testCase.unix, 0), options), options)
+				assert.NoError(t, err)
+				assert.Equal(t, vector.expected, code, vector.algorithm)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := Step(now, DefaultOptions)
+	previous, _ := Code(rfcSecretSHA1, step-1, DefaultOptions)
+	tooOld, _ := Code(rfcSecretSHA1, step-2, DefaultOptions)
+
+	testCaseList := []struct {
+		name         string
+		code         string
+		expectedStep int64
+		expectedOK   bool
+	}{
+		{name: "current step", code: "050471", expectedStep: step, expectedOK: true},
+		{name: "with spaces", code: "050 471", expectedStep: step, expectedOK: true},
+		{name: "previous step within skew", code: previous, expectedStep: step - 1, expectedOK: true},
+		{name: "outside skew", code: tooOld},
+		{name: "wrong code", code: "123456"},
+		{name: "wrong length", code: "50471"},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			matched, ok := Validate(rfcSecretSHA1, testCase.code, now, 1, DefaultOptions)
+			assert.Equal(t, testCase.expectedOK, ok)
+			assert.Equal(t, testCase.expectedStep, matched)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	code, err := Code(secret, 1, DefaultOptions)
+	assert.NoError(t, err)
+	assert.Len(t, code, 6)
+
+	_, err = Code("not base32!", 1, DefaultOptions)
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("golang-template", "alice smith", "JBSWY3DPEHPK3PXP", DefaultOptions)
+
+	parsed, err := url.Parse(uri)
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/golang-template:alice smith", parsed.Path)
+	assert.Equal(t, url.Values{
+		"secret":    {"JBSWY3DPEHPK3PXP"},
+		"issuer":    {"golang-template"},
+		"algorithm": {"SHA1"},
+		"digits":    {"6"},
+		"period":    {"30"},
+	}, parsed.Query())
+}
+
+func TestQRCode(t *testing.T) {
+	data, err := QRCode("otpauth://totp/test:alice?secret=JBSWY3DPEHPK3PXP", 256)
+	assert.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 256, img.Bounds().Dx())
+}
@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+type Config struct {
+	// Exporter is none, otlp, stdout or file. With none, incoming trace
+	// context is still propagated to logs and outbound calls.
+	Exporter string `yaml:"exporter" env:"TRACE_EXPORTER" validate:"oneof=none otlp stdout file"`
+	// Endpoint is the host:port of an OTLP/HTTP collector. When empty the
+	// standard OTEL_EXPORTER_OTLP_* variables are used.
+	Endpoint    string  `yaml:"endpoint" env:"TRACE_OTLP_ENDPOINT"`
+	Insecure    bool    `yaml:"insecure" env:"TRACE_OTLP_INSECURE"`
+	FilePath    string  `yaml:"filePath" env:"TRACE_FILE_PATH"`
+	SampleRatio float64 `yaml:"sampleRatio" env:"TRACE_SAMPLE_RATIO" validate:"gte=0,lte=1"`
+	ServiceName string  `yaml:"serviceName" env:"TRACE_SERVICE_NAME" validate:"required"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Exporter:    "none",
+		FilePath:    "./logs/traces.json",
+		SampleRatio: 1,
+		ServiceName: "golang-template",
+	}
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The returned function flushes pending spans and must be called
+// on shutdown.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, closer, err := newExporter(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(config.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
+
+func newExporter(ctx context.Context, config Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch config.Exporter {
+	case "none":
+		return nil, nil, nil
+	case "otlp":
+		var options []otlptracehttp.Option
+		if config.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(config.Endpoint))
+		}
+		if config.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, options...)
+		return exporter, nil, err
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exporter, nil, err
+	case "file":
+		if err := os.MkdirAll(filepath.Dir(config.FilePath), 0o755); err != nil {
+			return nil, nil, err
+		}
+		file, err := os.OpenFile(config.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return exporter, file, nil
+	}
+	return nil, nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+}
@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetup(t *testing.T) {
+	testCaseList := []struct {
+		name          string
+		config        func(config *Config)
+		expectedError bool
+	}{
+		{
+			name:   "none",
+			config: func(config *Config) {},
+		},
+		{
+			name: "otlp",
+			config: func(config *Config) {
+				config.Exporter = "otlp"
+				config.Endpoint = "localhost:4318"
+				config.Insecure = true
+			},
+		},
+		{
+			name: "unknown exporter",
+			config: func(config *Config) {
+				config.Exporter = "jaeger"
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			previous := otel.GetTracerProvider()
+			t.Cleanup(func() { otel.SetTracerProvider(previous) })
+			config := DefaultConfig()
+			testCase.config(&config)
+
+			shutdown, err := Setup(context.Background(), config)
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+func TestSetup_FileExporter(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	config := DefaultConfig()
+	config.Exporter = "file"
+	config.FilePath = filepath.Join(t.TempDir(), "traces", "traces.json")
+
+	shutdown, err := Setup(context.Background(), config)
+	assert.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "operation", trace.WithSpanKind(trace.SpanKindInternal))
+	span.End()
+	assert.NoError(t, shutdown(context.Background()))
+
+	content, err := os.ReadFile(config.FilePath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"Name":"operation"`)
+	assert.Contains(t, string(content), `"Value":"golang-template"`)
+}
@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"regexp"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	minPasswordLength = 8
+	// maxPasswordBytes is where bcrypt stops accepting passwords.
+	maxPasswordBytes = 72
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9._-]{3,32}$`)
+
+func registerRules() {
+	rules := []struct {
+		tag      string
+		fn       validator.Func
+		messages map[string]string
+	}{
+		{
+			tag: "username",
+			fn:  validateUsername,
+			messages: map[string]string{
+				"en": "{0} must be 3 to 32 characters of letters, digits, '.', '_' or '-'",
+				"th": "{0} ต้องมีความยาว 3 ถึง 32 ตัวอักษร และประกอบด้วยตัวอักษรภาษาอังกฤษ ตัวเลข '.' '_' หรือ '-' เท่านั้น",
+			},
+		},
+		{
+			tag: "password",
+			fn:  validatePassword,
+			messages: map[string]string{
+				"en": "{0} must be at least 8 characters, at most 72 bytes and contain an upper case letter, a lower case letter and a digit",
+				"th": "{0} ต้องมีความยาวอย่างน้อย 8 ตัวอักษรและไม่เกิน 72 ไบต์ และมีตัวพิมพ์ใหญ่ ตัวพิมพ์เล็ก และตัวเลข",
+			},
+		},
+	}
+
+	for _, rule := range rules {
+		if err := RegisterRule(rule.tag, rule.fn, rule.messages); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func validateUsername(field validator.FieldLevel) bool {
+	return usernamePattern.MatchString(field.Field().String())
+}
+
+// validatePassword requires a minimum length and a mix of upper case, lower
+// case and digit characters. The maximum is in bytes, as bcrypt rejects
+// longer passwords.
+func validatePassword(field validator.FieldLevel) bool {
+	password := field.Field().String()
+	if len([]rune(password)) < minPasswordLength || len(password) > maxPasswordBytes {
+		return false
+	}
+
+	var hasUpper, hasLower, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	return hasUpper && hasLower && hasDigit
+}
@@ -1,25 +1,200 @@
 package validator
 
 import (
-	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/th"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	thTranslations "github.com/go-playground/validator/v10/translations/th"
 )
 
-var Validate = validator.New(validator.WithRequiredStructEnabled())
+const DefaultLocale = "en"
 
+var (
+	Validate = validator.New(validator.WithRequiredStructEnabled())
+
+	universalTranslator = ut.New(en.New(), en.New(), th.New())
+
+	// defaultTranslations holds the built-in messages of every supported
+	// locale.
+	defaultTranslations = map[string]func(*validator.Validate, ut.Translator) error{
+		"en": enTranslations.RegisterDefaultTranslations,
+		"th": thTranslations.RegisterDefaultTranslations,
+	}
+)
+
+// FieldError describes one failed rule. Field is the path of the field as
+// clients see it, using json (or query) tag names.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors lists every field that failed validation.
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, fieldErr := range v {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func init() {
+	Validate.RegisterTagNameFunc(fieldName)
+
+	for locale, register := range defaultTranslations {
+		translator, _ := universalTranslator.GetTranslator(locale)
+		if err := register(Validate, translator); err != nil {
+			panic(err)
+		}
+	}
+
+	registerRules()
+}
+
+// RegisterRule adds a custom validation tag. messages maps a locale to its
+// message template, where {0} is the field name and {1} the rule parameter;
+// the English message is used for locales that have none.
+func RegisterRule(tag string, fn validator.Func, messages map[string]string) error {
+	if err := Validate.RegisterValidation(tag, fn); err != nil {
+		return err
+	}
+
+	for locale := range defaultTranslations {
+		message, ok := messages[locale]
+		if !ok {
+			message = messages[DefaultLocale]
+		}
+		translator, _ := universalTranslator.GetTranslator(locale)
+		err := Validate.RegisterTranslation(tag, translator, func(translator ut.Translator) error {
+			return translator.Add(tag, message, true)
+		}, translateFieldError)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateStruct validates s and reports failures in the default locale.
 func ValidateStruct(s interface{}) error {
+	return ValidateStructLocale(s, DefaultLocale)
+}
+
+// ValidateStructLocale validates s and reports every failing field with a
+// message in the best locale for acceptLanguage, an Accept-Language value.
+func ValidateStructLocale(s interface{}, acceptLanguage string) error {
 	err := Validate.Struct(s)
-	if err != nil {
-		validationErrors := err.(validator.ValidationErrors)
+	if err == nil {
+		return nil
+	}
 
-		if len(validationErrors) == 0 {
-			return nil
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	translator := Translator(acceptLanguage)
+	fieldErrors := make(ValidationErrors, len(validationErrors))
+	for i, fieldErr := range validationErrors {
+		fieldErrors[i] = FieldError{
+			Field:   fieldPath(fieldErr.Namespace()),
+			Rule:    fieldErr.Tag(),
+			Param:   fieldErr.Param(),
+			Message: fieldErr.Translate(translator),
 		}
+	}
+	return fieldErrors
+}
+
+// Translator picks the supported translator that best matches an
+// Accept-Language value such as "th-TH,th;q=0.9,en;q=0.8". Region subtags
+// fall back to the base language and unknown languages to DefaultLocale.
+func Translator(acceptLanguage string) ut.Translator {
+	for _, language := range parseAcceptLanguage(acceptLanguage) {
+		language = strings.ReplaceAll(language, "-", "_")
+		base, _, _ := strings.Cut(language, "_")
+		if translator, found := universalTranslator.FindTranslator(language, base); found {
+			return translator
+		}
+	}
+	translator, _ := universalTranslator.GetTranslator(DefaultLocale)
+	return translator
+}
 
-		firstErr := validationErrors[0]
-		return fmt.Errorf("%s %s %s", firstErr.Field(), firstErr.Tag(), firstErr.Param())
+func parseAcceptLanguage(acceptLanguage string) []string {
+	type weightedLanguage struct {
+		language string
+		quality  float64
 	}
 
-	return err
+	var languages []weightedLanguage
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		language, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if language == "" || language == "*" {
+			continue
+		}
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		languages = append(languages, weightedLanguage{language: strings.ToLower(language), quality: quality})
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	result := make([]string, len(languages))
+	for i, language := range languages {
+		result[i] = language.language
+	}
+	return result
+}
+
+func translateFieldError(translator ut.Translator, fieldErr validator.FieldError) string {
+	message, err := translator.T(fieldErr.Tag(), fieldErr.Field(), fieldErr.Param())
+	if err != nil {
+		return fieldErr.Error()
+	}
+	return message
+}
+
+// fieldName reports fields by the name clients use in requests.
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "query"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// fieldPath drops the top level struct name from a namespace such as
+// "UserRegister.profile.email".
+func fieldPath(namespace string) string {
+	_, path, found := strings.Cut(namespace, ".")
+	if !found {
+		return namespace
+	}
+	return path
 }
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -44,3 +45,75 @@ func TestValidateStruct(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+type signUp struct {
+	Username string  `json:"username" validate:"required,username"`
+	Password string  `json:"password" validate:"required,password"`
+	Profile  profile `json:"profile"`
+}
+
+type profile struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func TestValidateStructLocale(t *testing.T) {
+	testCaseList := []struct {
+		name           string
+		data           *signUp
+		acceptLanguage string
+		expectedErrors ValidationErrors
+	}{
+		{
+			name:           "valid",
+			data:           &signUp{Username: "john.doe", Password: "Passw0rd1", Profile: profile{Email: "john@example.com"}},
+			acceptLanguage: "en",
+			expectedErrors: nil,
+		},
+		{
+			name:           "reports every field with json names",
+			data:           &signUp{Username: "j!", Password: "password", Profile: profile{Email: "invalid"}},
+			acceptLanguage: "en-US,en;q=0.9",
+			expectedErrors: ValidationErrors{
+				{Field: "username", Rule: "username", Message: "username must be 3 to 32 characters of letters, digits, '.', '_' or '-'"},
+				{Field: "password", Rule: "password", Message: "password must be at least 8 characters, at most 72 bytes and contain an upper case letter, a lower case letter and a digit"},
+				{Field: "profile.email", Rule: "email", Message: "email must be a valid email address"},
+			},
+		},
+		{
+			name:           "password longer than 72 bytes",
+			data:           &signUp{Username: "john.doe", Password: "Passw0rd" + strings.Repeat("x", 65), Profile: profile{Email: "john@example.com"}},
+			acceptLanguage: "th",
+			expectedErrors: ValidationErrors{
+				{Field: "password", Rule: "password", Message: "password ต้องมีความยาวอย่างน้อย 8 ตัวอักษรและไม่เกิน 72 ไบต์ และมีตัวพิมพ์ใหญ่ ตัวพิมพ์เล็ก และตัวเลข"},
+			},
+		},
+		{
+			name:           "translates by accept language",
+			data:           &signUp{Password: "Passw0rd1", Profile: profile{Email: "john@example.com"}},
+			acceptLanguage: "fr;q=0.9,th-TH",
+			expectedErrors: ValidationErrors{
+				{Field: "username", Rule: "required", Message: "โปรดระบุ username"},
+			},
+		},
+		{
+			name:           "falls back to the default locale",
+			data:           &signUp{Password: "Passw0rd1", Profile: profile{Email: "john@example.com"}},
+			acceptLanguage: "fr",
+			expectedErrors: ValidationErrors{
+				{Field: "username", Rule: "required", Message: "username is a required field"},
+			},
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := ValidateStructLocale(testCase.data, testCase.acceptLanguage)
+
+			if testCase.expectedErrors == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, testCase.expectedErrors, err)
+		})
+	}
+}